@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reservation-service/config"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationKey is the metadata key carrying the caller's bearer token.
+const AuthorizationKey = "authorization"
+
+// RoleAdmin is the token role of platform administrators, who may act on any
+// restaurant.
+const RoleAdmin = "admin"
+
+// Caller is the user an RPC was authenticated as.
+type Caller struct {
+	UserID string
+	Role   string
+}
+
+// IsAdmin reports whether the caller is a platform administrator.
+func (c Caller) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// Claims are the claims of the access tokens issued by the auth service. The
+// subject is the user id.
+type Claims struct {
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Verifier checks HS256 access tokens signed with a secret shared with the
+// auth service.
+type Verifier struct {
+	secret []byte
+	issuer string
+}
+
+func NewVerifier(cfg config.TokenConfig) *Verifier {
+	return &Verifier{secret: []byte(cfg.Secret), issuer: cfg.Issuer}
+}
+
+// Verify returns the caller a token was issued to. It fails for tokens that
+// are malformed, expired, signed with another key or algorithm, issued by
+// another issuer or lack a subject, and for every token when no secret is
+// configured.
+func (v *Verifier) Verify(token string) (Caller, error) {
+	if len(v.secret) == 0 {
+		return Caller{}, errors.New("token authentication is not configured")
+	}
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired()}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return v.secret, nil
+	}, opts...)
+	if err != nil {
+		return Caller{}, fmt.Errorf("invalid token: %v", err)
+	}
+	if claims.Subject == "" {
+		return Caller{}, errors.New("invalid token: no subject")
+	}
+	return Caller{UserID: claims.Subject, Role: claims.Role}, nil
+}
+
+type callerKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated caller.
+func NewContext(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// FromContext returns the caller authenticated by the interceptors, if any.
+func FromContext(ctx context.Context) (Caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(Caller)
+	return c, ok
+}
+
+// UnaryServerInterceptor authenticates the bearer token in the authorization
+// metadata and stores its caller in the context. Calls without a token go
+// through anonymously and are left to the handlers to refuse; calls with an
+// invalid token fail with Unauthenticated.
+func UnaryServerInterceptor(v *Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := v.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor does for streaming RPCs what UnaryServerInterceptor
+// does for unary ones.
+func StreamServerInterceptor(v *Verifier) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := v.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream carries the context holding the caller.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (v *Verifier) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a Bearer token")
+	}
+	caller, err := v.Verify(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return NewContext(ctx, caller), nil
+}
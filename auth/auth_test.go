@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"reservation-service/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NoError(t, err)
+	return token
+}
+
+func claimsFor(subject, role string, expires time.Duration) Claims {
+	return Claims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    "auth-service",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expires)),
+	}}
+}
+
+func TestVerify(t *testing.T) {
+	v := NewVerifier(config.TokenConfig{Secret: secret, Issuer: "auth-service"})
+	userId := "67188541-6344-42bd-8be2-a14c558d30aa"
+
+	caller, err := v.Verify(sign(t, jwt.SigningMethodHS256, []byte(secret), claimsFor(userId, RoleAdmin, time.Hour)))
+	assert.NoError(t, err)
+	assert.Equal(t, userId, caller.UserID)
+	assert.True(t, caller.IsAdmin())
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", sign(t, jwt.SigningMethodHS256, []byte(secret), claimsFor(userId, "", -time.Minute))},
+		{"other key", sign(t, jwt.SigningMethodHS256, []byte("another-secret-another-secret-00"), claimsFor(userId, "", time.Hour))},
+		{"unsigned", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claimsFor(userId, "", time.Hour))},
+		{"no subject", sign(t, jwt.SigningMethodHS256, []byte(secret), claimsFor("", "", time.Hour))},
+		{"malformed", "not-a-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(tt.token)
+			assert.Error(t, err)
+		})
+	}
+
+	other := NewVerifier(config.TokenConfig{Secret: secret, Issuer: "someone-else"})
+	_, err = other.Verify(sign(t, jwt.SigningMethodHS256, []byte(secret), claimsFor(userId, "", time.Hour)))
+	assert.Error(t, err)
+
+	unconfigured := NewVerifier(config.TokenConfig{})
+	_, err = unconfigured.Verify(sign(t, jwt.SigningMethodHS256, []byte(""), claimsFor(userId, "", time.Hour)))
+	assert.Error(t, err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	v := NewVerifier(config.TokenConfig{Secret: secret})
+	interceptor := UnaryServerInterceptor(v)
+	var got Caller
+	var authenticated bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, authenticated = FromContext(ctx)
+		return nil, nil
+	}
+	call := func(md metadata.MD) error {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/x/Y"}, handler)
+		return err
+	}
+
+	// A spoofed user id header no longer authenticates anyone.
+	assert.NoError(t, call(metadata.Pairs("x-user-id", "67188541-6344-42bd-8be2-a14c558d30aa")))
+	assert.False(t, authenticated)
+
+	token := sign(t, jwt.SigningMethodHS256, []byte(secret), claimsFor("67188541-6344-42bd-8be2-a14c558d30aa", "", time.Hour))
+	assert.NoError(t, call(metadata.Pairs(AuthorizationKey, "Bearer "+token)))
+	assert.True(t, authenticated)
+	assert.Equal(t, "67188541-6344-42bd-8be2-a14c558d30aa", got.UserID)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(metadata.Pairs(AuthorizationKey, token))))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(metadata.Pairs(AuthorizationKey, "Bearer not-a-token"))))
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"reservation-service/config"
+	authpb "reservation-service/generated/auth_service"
+	paymentpb "reservation-service/generated/payment_service"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Clients holds connections to the downstream services.
+type Clients struct {
+	Payment paymentpb.PaymentServiceClient
+	Auth    authpb.AuthServiceClient
+
+	conns []*grpc.ClientConn
+}
+
+// New connects lazily to the payment and auth services. Calls are traced and
+// bounded by the configured timeout unless the caller sets a deadline.
+func New(payment, auth config.ServiceConfig) (*Clients, error) {
+	paymentConn, err := dial(payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to payment service: %v", err)
+	}
+	authConn, err := dial(auth)
+	if err != nil {
+		paymentConn.Close()
+		return nil, fmt.Errorf("failed to connect to auth service: %v", err)
+	}
+
+	return &Clients{
+		Payment: paymentpb.NewPaymentServiceClient(paymentConn),
+		Auth:    authpb.NewAuthServiceClient(authConn),
+		conns:   []*grpc.ClientConn{paymentConn, authConn},
+	}, nil
+}
+
+func (c *Clients) Close() error {
+	var firstErr error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func dial(cfg config.ServiceConfig) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	return grpc.NewClient(cfg.Addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(timeoutInterceptor(cfg.Timeout)),
+	)
+}
+
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
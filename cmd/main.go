@@ -1,44 +1,221 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"reservation-service/auth"
+	"reservation-service/clients"
 	"reservation-service/config"
 	pb "reservation-service/generated/reservation_service"
+	"reservation-service/geo"
 	"reservation-service/logs"
+	"reservation-service/metrics"
+	"reservation-service/notifications"
+	"reservation-service/outbox"
 	"reservation-service/service"
 	"reservation-service/storage/postgres"
 	"reservation-service/storage/redis"
+	"reservation-service/tracing"
+	"syscall"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	logs.InitLogger()
-	logs.Logger.Info("Starting the server")
-	db, err := postgres.ConnectDB()
+	if len(os.Args) > 1 && os.Args[1] == "menu" {
+		os.Exit(menuCommand(os.Args[2:]))
+	}
+
+	config, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed load config: %v", err)
+	}
+
+	logger, logCloser, err := logs.New(config.Log)
+	if err != nil {
+		log.Fatalf("Failed init logger: %v", err)
+	}
+	defer logCloser.Close()
+	logger.Info("Starting the server")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Init(ctx, config.Tracing)
 	if err != nil {
-		logs.Logger.Error("Failed connect to Data Base","error",err.Error())
-		panic(err)
+		logger.Error("Failed init tracing", "error", err.Error())
+		os.Exit(1)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(flushCtx)
+	}()
+
+	db, err := postgres.ConnectDB(config.Postgres)
+	if err != nil {
+		logger.Error("Failed connect to Data Base", "error", err.Error())
+		os.Exit(1)
 	}
 	defer db.Close()
-	r := redis.ConnectR()
+	r := redis.ConnectR(config.Redis)
+	defer r.Close()
+	r.AddHook(tracing.RedisHook())
 
-	config := config.Load()
+	c, err := clients.New(config.Payment, config.Auth)
+	if err != nil {
+		logger.Error("Failed connect to downstream services", "error", err.Error())
+		os.Exit(1)
+	}
+	defer c.Close()
 
-	listener, err := net.Listen("tcp", config.GRPC_PORT)
-	if err != nil{
-		logs.Logger.Error("Failed listen","error",err.Error())
-		panic(err)
+	verifier := auth.NewVerifier(config.Tokens)
+	var m *metrics.Metrics
+	interceptors := []grpc.UnaryServerInterceptor{logs.UnaryServerInterceptor(logger), auth.UnaryServerInterceptor(verifier)}
+	streamInterceptors := []grpc.StreamServerInterceptor{logs.StreamServerInterceptor(logger), auth.StreamServerInterceptor(verifier)}
+	if config.Metrics.Enabled {
+		m = metrics.New()
+		m.RegisterDB(db, "reservation_service")
+		r.AddHook(m.RedisHook())
+		interceptors = append(interceptors, m.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, m.StreamServerInterceptor())
+
+		metricsServer := &http.Server{Addr: config.Metrics.Addr, Handler: m.Handler()}
+		go func() {
+			logger.Info("Metrics server is Running", "addr", config.Metrics.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Failed metrics server", "error", err.Error())
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
+	listener, err := net.Listen("tcp", config.GRPC.Port)
+	if err != nil {
+		logger.Error("Failed listen", "error", err.Error())
+		os.Exit(1)
 	}
-	s := service.NewRRestaurantService(*postgres.NewRRestaurantRepo(db, r))
-	server := grpc.NewServer()
-	pb.RegisterReservationServiceServer(server,s)
+	repo := postgres.NewRRestaurantRepo(db, r)
+	s := service.NewRRestaurantService(*repo, c, logger, m)
+	s.Geocoder, err = geo.NewGeocoder(config.Geocoder)
+	if err != nil {
+		logger.Error("Failed init geocoder", "error", err.Error())
+		os.Exit(1)
+	}
+	if config.Features.PurgeJob {
+		go s.RunPurgeJob(ctx, int32(config.Purge.RetentionDays), config.Purge.Interval)
+	}
+	if config.Features.NoShowJob {
+		go s.RunNoShowJob(ctx, config.NoShow.GracePeriod, config.NoShow.Interval)
+	}
+	if config.Features.DepositExpiryJob {
+		go s.RunDepositExpiryJob(ctx, config.Deposit.HoldTTL, config.Deposit.Interval)
+	}
+	if config.Features.PriceChangeJob {
+		go s.RunPriceChangeJob(ctx, config.Prices.Interval)
+	}
+	if config.Features.OutboxRelay {
+		relay := &outbox.Relay{
+			Store:        repo,
+			Sink:         outbox.RedisStreamSink{Client: r, Stream: config.Outbox.Stream},
+			Logger:       logger,
+			BatchSize:    config.Outbox.BatchSize,
+			PollInterval: config.Outbox.PollInterval,
+			Lease:        config.Outbox.Lease,
+			MaxBackoff:   config.Outbox.MaxBackoff,
+		}
+		go relay.Run(ctx)
+	}
+	if config.Features.Notifications {
+		email, err := notifications.NewChannel("email", config.Notify.Email, config.Notify, logger)
+		if err != nil {
+			logger.Error("Failed init email notifications", "error", err.Error())
+			os.Exit(1)
+		}
+		sms, err := notifications.NewChannel("sms", config.Notify.SMS, config.Notify, logger)
+		if err != nil {
+			logger.Error("Failed init sms notifications", "error", err.Error())
+			os.Exit(1)
+		}
+		dispatcher := &notifications.Dispatcher{
+			Store:         repo,
+			Email:         email,
+			SMS:           sms,
+			Logger:        logger,
+			BatchSize:     config.Notify.BatchSize,
+			PollInterval:  config.Notify.PollInterval,
+			Lease:         config.Notify.Lease,
+			MaxAttempts:   config.Notify.MaxAttempts,
+			RetryInterval: config.Notify.RetryInterval,
+		}
+		go dispatcher.Run(ctx)
+	}
+
+	var opts []grpc.ServerOption
+	if config.GRPC.TLS.Enabled {
+		creds, err := credentials.NewServerTLSFromFile(config.GRPC.TLS.CertFile, config.GRPC.TLS.KeyFile)
+		if err != nil {
+			logger.Error("Failed load TLS credentials", "error", err.Error())
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	opts = append(opts,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+	server := grpc.NewServer(opts...)
+	pb.RegisterReservationServiceServer(server, s)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go service.WatchHealth(ctx, healthServer, db, r, config.GRPC.HealthCheckInterval)
+
+	if config.Features.Reflection {
+		reflection.Register(server)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server is Running", "PORT", config.GRPC.Port, "addr", listener.Addr().String())
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err = <-serveErr:
+		if err != nil {
+			logger.Error("Failed server is running", "error", err.Error())
+		}
+		return
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down the server")
+	healthServer.Shutdown()
 
-	logs.Logger.Info("Server is Running","PORT",config.GRPC_PORT)
-	log.Println("Server is running on ", listener.Addr())
-	if err = server.Serve(listener);err != nil{
-		logs.Logger.Error("Failed server is running","error",err.Error())
-		panic(err)
+	// Drain in-flight RPCs, but do not wait forever on a stuck stream.
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(config.GRPC.ShutdownTimeout):
+		logger.Warn("Graceful shutdown timed out, forcing stop")
+		server.Stop()
 	}
+	logger.Info("Server stopped")
 }
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/menuio"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const menuUsage = `usage:
+  reservation-service menu import -restaurant ID [-dry-run] [-format csv|json] FILE
+  reservation-service menu export -restaurant ID [-format csv|json] [FILE]
+
+Both connect to the service at -addr. Without FILE, export writes to stdout.`
+
+// importBatchSize is how many rows are sent per ImportMenu message.
+const importBatchSize = 100
+
+// menuCommand runs "reservation-service menu ..." and returns the exit code.
+func menuCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "import" && args[0] != "export") {
+		fmt.Fprintln(os.Stderr, menuUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("menu "+args[0], flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:50051", "address of the reservation service")
+	useTLS := flags.Bool("tls", false, "connect with TLS")
+	restaurantId := flags.String("restaurant", "", "restaurant id")
+	format := flags.String("format", "", "csv or json; by default taken from the file's extension")
+	dryRun := flags.Bool("dry-run", false, "validate the import without saving it")
+	timeout := flags.Duration("timeout", time.Minute, "how long to wait for the service")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, menuUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *restaurantId == "" || flags.NArg() > 1 || (args[0] == "import" && flags.NArg() != 1) {
+		flags.Usage()
+		return 2
+	}
+	file := flags.Arg(0)
+	if *format == "" {
+		if file == "" {
+			*format = menuio.CSV
+		} else {
+			f, err := menuio.FormatOf(file)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			*format = f
+		}
+	}
+
+	creds := insecure.NewCredentials()
+	if *useTLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer conn.Close()
+	client := pb.NewReservationServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if args[0] == "export" {
+		err = exportMenu(ctx, client, *restaurantId, *format, file)
+	} else {
+		err = importMenu(ctx, client, *restaurantId, *format, file, *dryRun)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func importMenu(ctx context.Context, client pb.ReservationServiceClient, restaurantId, format, file string, dryRun bool) error {
+	restaurant, err := client.GetRestaurant(ctx, &pb.GetRestaurantRequest{Id: restaurantId})
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, err := menuio.Read(f, format, restaurant.Restaurant.Currency)
+	if err != nil {
+		return fmt.Errorf("%s:\n%v", file, err)
+	}
+
+	stream, err := client.ImportMenu(ctx)
+	if err != nil {
+		return err
+	}
+	for start := 0; start == 0 || start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+		err = stream.Send(&pb.ImportMenuRequest{RestaurantId: restaurantId, DryRun: dryRun, Rows: rows[start:end]})
+		if err == io.EOF {
+			// The service ended the stream; CloseAndRecv returns why.
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, rowErr := range res.Errors {
+		fmt.Fprintf(os.Stderr, "row %d (%s): %s\n", rowErr.Row, rowErr.Name, rowErr.Message)
+	}
+	switch {
+	case len(res.Errors) > 0:
+		return fmt.Errorf("%d rows were rejected, nothing was imported", len(res.Errors))
+	case res.Committed:
+		fmt.Printf("imported: %d created, %d updated, %d new categories\n", res.Created, res.Updated, res.CategoriesCreated)
+	default:
+		fmt.Printf("dry run: %d to create, %d to update, %d new categories\n", res.Created, res.Updated, res.CategoriesCreated)
+	}
+	return nil
+}
+
+func exportMenu(ctx context.Context, client pb.ReservationServiceClient, restaurantId, format, file string) error {
+	res, err := client.ExportMenu(ctx, &pb.ExportMenuRequest{RestaurantId: restaurantId})
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return menuio.Write(os.Stdout, format, res.Rows)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err = menuio.Write(f, format, res.Rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
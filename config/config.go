@@ -1,36 +1,544 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	DB_HOST     string
-	DB_PORT     int
-	DB_USERNAME string
-	DB_DATABASE string
-	DB_PASSWORD string
-	GRPC_PORT   string
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	Postgres PostgresConfig `yaml:"postgres"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Payment  ServiceConfig  `yaml:"payment_service"`
+	Auth     ServiceConfig  `yaml:"auth_service"`
+	Tokens   TokenConfig    `yaml:"tokens"`
+	Purge    PurgeConfig    `yaml:"purge"`
+	NoShow   NoShowConfig   `yaml:"no_show"`
+	Deposit  DepositConfig  `yaml:"deposit"`
+	Prices   PriceConfig    `yaml:"prices"`
+	Log      LogConfig      `yaml:"log"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Notify   NotifyConfig   `yaml:"notifications"`
+	Geocoder GeocoderConfig `yaml:"geocoder"`
+	Features FeatureFlags   `yaml:"features"`
 }
 
-func Load() Config {
+type GRPCConfig struct {
+	Port                string        `yaml:"port"`
+	ShutdownTimeout     time.Duration `yaml:"shutdown_timeout"`
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+	TLS                 TLSConfig     `yaml:"tls"`
+}
+
+type TLSConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+type PostgresConfig struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	User            string        `yaml:"user"`
+	Database        string        `yaml:"database"`
+	Password        string        `yaml:"password"`
+	SSLMode         string        `yaml:"sslmode"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+type RedisConfig struct {
+	Addr         string        `yaml:"addr"`
+	Password     string        `yaml:"password"`
+	DB           int           `yaml:"db"`
+	PoolSize     int           `yaml:"pool_size"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+// ServiceConfig describes a downstream gRPC service.
+type ServiceConfig struct {
+	Addr    string        `yaml:"addr"`
+	Timeout time.Duration `yaml:"timeout"`
+	TLS     bool          `yaml:"tls"`
+}
+
+// TokenConfig verifies the HS256 access tokens issued by the auth service.
+// Without a secret no caller can authenticate.
+type TokenConfig struct {
+	Secret string `yaml:"secret"`
+	Issuer string `yaml:"issuer"`
+}
+
+type PurgeConfig struct {
+	RetentionDays int           `yaml:"retention_days"`
+	Interval      time.Duration `yaml:"interval"`
+}
+
+// NoShowConfig controls how long after its time a confirmed reservation is
+// marked as a no-show when the guest was not seated.
+type NoShowConfig struct {
+	GracePeriod time.Duration `yaml:"grace_period"`
+	Interval    time.Duration `yaml:"interval"`
+}
+
+// DepositConfig controls how long a reservation is held for an unpaid
+// deposit before it is cancelled.
+type DepositConfig struct {
+	HoldTTL  time.Duration `yaml:"hold_ttl"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// PriceConfig controls how often scheduled menu prices are applied.
+type PriceConfig struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+// LogConfig selects where logs go (stdout, file or both) and the minimum level.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Output string `yaml:"output"`
+	File   string `yaml:"file"`
+}
+
+// MetricsConfig controls the Prometheus HTTP endpoint.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// TracingConfig selects the OpenTelemetry span exporter: none, stdout or otlp.
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint"`
+	Insecure     bool    `yaml:"insecure"`
+	SampleRatio  float64 `yaml:"sample_ratio"`
+	ServiceName  string  `yaml:"service_name"`
+}
+
+// OutboxConfig controls the relay that publishes outbox events to a Redis stream.
+type OutboxConfig struct {
+	Stream       string        `yaml:"stream"`
+	BatchSize    int           `yaml:"batch_size"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	Lease        time.Duration `yaml:"lease"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"`
+}
+
+// NotifyConfig selects the guest notification channels. Email may be none,
+// log, file or smtp; SMS may be none, log or file.
+type NotifyConfig struct {
+	Email         string        `yaml:"email"`
+	SMS           string        `yaml:"sms"`
+	File          string        `yaml:"file"`
+	SMTP          SMTPConfig    `yaml:"smtp"`
+	BatchSize     int           `yaml:"batch_size"`
+	PollInterval  time.Duration `yaml:"poll_interval"`
+	Lease         time.Duration `yaml:"lease"`
+	MaxAttempts   int           `yaml:"max_attempts"`
+	RetryInterval time.Duration `yaml:"retry_interval"`
+}
+
+// GeocoderConfig selects how restaurant addresses are located: none, or
+// nominatim for an OpenStreetMap Nominatim server at URL.
+type GeocoderConfig struct {
+	Provider  string        `yaml:"provider"`
+	URL       string        `yaml:"url"`
+	UserAgent string        `yaml:"user_agent"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+type SMTPConfig struct {
+	Addr     string `yaml:"addr"`
+	From     string `yaml:"from"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type FeatureFlags struct {
+	Reflection       bool `yaml:"reflection"`
+	PurgeJob         bool `yaml:"purge_job"`
+	NoShowJob        bool `yaml:"no_show_job"`
+	DepositExpiryJob bool `yaml:"deposit_expiry_job"`
+	PriceChangeJob   bool `yaml:"price_change_job"`
+	OutboxRelay      bool `yaml:"outbox_relay"`
+	Notifications    bool `yaml:"notifications"`
+}
+
+// Load builds the configuration from defaults, the optional YAML file named
+// by CONFIG_FILE and finally environment variables, then validates it.
+func Load() (Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Error loading .env file")
 	}
 
-	cfg := Config{}
-	cfg.DB_HOST = cast.ToString(Coalesce("DB_HOST", "localhost"))
-	cfg.DB_PORT = cast.ToInt(Coalesce("DB_PORT", 5432))
-	cfg.DB_USERNAME = cast.ToString(Coalesce("DB_USERNAME", "postgres"))
-	cfg.DB_DATABASE = cast.ToString(Coalesce("DB_DATABASE", "reservation_service"))
-	cfg.DB_PASSWORD = cast.ToString(Coalesce("DB_PASSWORD", "03212164"))
-	cfg.GRPC_PORT = cast.ToString(Coalesce("GRPC_PORT", ":50051"))
+	cfg := defaults()
+
+	if path := cast.ToString(Coalesce("CONFIG_FILE", "")); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("config: read %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("config: parse %s: %w", path, err)
+		}
+	}
+
+	// Durations need a unit: a bare 24 would otherwise be read as 24ns.
+	var errs []error
+	duration := func(key string, value time.Duration) time.Duration {
+		s, ok := os.LookupEnv(key)
+		if !ok {
+			return value
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %q must be a duration with a unit, such as 30s or 24h", key, s))
+			return value
+		}
+		return d
+	}
+
+	cfg.GRPC.Port = cast.ToString(Coalesce("GRPC_PORT", cfg.GRPC.Port))
+	cfg.GRPC.ShutdownTimeout = duration("GRPC_SHUTDOWN_TIMEOUT", cfg.GRPC.ShutdownTimeout)
+	cfg.GRPC.HealthCheckInterval = duration("GRPC_HEALTH_CHECK_INTERVAL", cfg.GRPC.HealthCheckInterval)
+	cfg.GRPC.TLS.Enabled = cast.ToBool(Coalesce("GRPC_TLS_ENABLED", cfg.GRPC.TLS.Enabled))
+	cfg.GRPC.TLS.CertFile = cast.ToString(Coalesce("GRPC_TLS_CERT_FILE", cfg.GRPC.TLS.CertFile))
+	cfg.GRPC.TLS.KeyFile = cast.ToString(Coalesce("GRPC_TLS_KEY_FILE", cfg.GRPC.TLS.KeyFile))
+
+	cfg.Postgres.Host = cast.ToString(Coalesce("DB_HOST", cfg.Postgres.Host))
+	cfg.Postgres.Port = cast.ToInt(Coalesce("DB_PORT", cfg.Postgres.Port))
+	cfg.Postgres.User = cast.ToString(Coalesce("DB_USERNAME", cfg.Postgres.User))
+	cfg.Postgres.Database = cast.ToString(Coalesce("DB_DATABASE", cfg.Postgres.Database))
+	cfg.Postgres.Password = cast.ToString(Coalesce("DB_PASSWORD", cfg.Postgres.Password))
+	cfg.Postgres.SSLMode = cast.ToString(Coalesce("DB_SSLMODE", cfg.Postgres.SSLMode))
+	cfg.Postgres.ConnectTimeout = duration("DB_CONNECT_TIMEOUT", cfg.Postgres.ConnectTimeout)
+	cfg.Postgres.MaxOpenConns = cast.ToInt(Coalesce("DB_MAX_OPEN_CONNS", cfg.Postgres.MaxOpenConns))
+	cfg.Postgres.MaxIdleConns = cast.ToInt(Coalesce("DB_MAX_IDLE_CONNS", cfg.Postgres.MaxIdleConns))
+	cfg.Postgres.ConnMaxLifetime = duration("DB_CONN_MAX_LIFETIME", cfg.Postgres.ConnMaxLifetime)
+
+	cfg.Redis.Addr = cast.ToString(Coalesce("REDIS_ADDR", cfg.Redis.Addr))
+	cfg.Redis.Password = cast.ToString(Coalesce("REDIS_PASSWORD", cfg.Redis.Password))
+	cfg.Redis.DB = cast.ToInt(Coalesce("REDIS_DB", cfg.Redis.DB))
+	cfg.Redis.PoolSize = cast.ToInt(Coalesce("REDIS_POOL_SIZE", cfg.Redis.PoolSize))
+	cfg.Redis.DialTimeout = duration("REDIS_DIAL_TIMEOUT", cfg.Redis.DialTimeout)
+	cfg.Redis.ReadTimeout = duration("REDIS_READ_TIMEOUT", cfg.Redis.ReadTimeout)
+	cfg.Redis.WriteTimeout = duration("REDIS_WRITE_TIMEOUT", cfg.Redis.WriteTimeout)
+
+	cfg.Payment.Addr = cast.ToString(Coalesce("PAYMENT_SERVICE_ADDR", cfg.Payment.Addr))
+	cfg.Payment.Timeout = duration("PAYMENT_SERVICE_TIMEOUT", cfg.Payment.Timeout)
+	cfg.Payment.TLS = cast.ToBool(Coalesce("PAYMENT_SERVICE_TLS", cfg.Payment.TLS))
+	cfg.Auth.Addr = cast.ToString(Coalesce("AUTH_SERVICE_ADDR", cfg.Auth.Addr))
+	cfg.Auth.Timeout = duration("AUTH_SERVICE_TIMEOUT", cfg.Auth.Timeout)
+	cfg.Auth.TLS = cast.ToBool(Coalesce("AUTH_SERVICE_TLS", cfg.Auth.TLS))
+	cfg.Tokens.Secret = cast.ToString(Coalesce("AUTH_TOKEN_SECRET", cfg.Tokens.Secret))
+	cfg.Tokens.Issuer = cast.ToString(Coalesce("AUTH_TOKEN_ISSUER", cfg.Tokens.Issuer))
+
+	cfg.Purge.RetentionDays = cast.ToInt(Coalesce("PURGE_RETENTION_DAYS", cfg.Purge.RetentionDays))
+	cfg.Purge.Interval = duration("PURGE_INTERVAL", cfg.Purge.Interval)
+
+	cfg.NoShow.GracePeriod = duration("NO_SHOW_GRACE_PERIOD", cfg.NoShow.GracePeriod)
+	cfg.NoShow.Interval = duration("NO_SHOW_INTERVAL", cfg.NoShow.Interval)
 
-	return cfg
+	cfg.Deposit.HoldTTL = duration("DEPOSIT_HOLD_TTL", cfg.Deposit.HoldTTL)
+	cfg.Deposit.Interval = duration("DEPOSIT_EXPIRY_INTERVAL", cfg.Deposit.Interval)
+
+	cfg.Prices.Interval = duration("PRICE_CHANGE_INTERVAL", cfg.Prices.Interval)
+
+	cfg.Log.Level = cast.ToString(Coalesce("LOG_LEVEL", cfg.Log.Level))
+	cfg.Log.Output = cast.ToString(Coalesce("LOG_OUTPUT", cfg.Log.Output))
+	cfg.Log.File = cast.ToString(Coalesce("LOG_FILE", cfg.Log.File))
+
+	cfg.Metrics.Enabled = cast.ToBool(Coalesce("METRICS_ENABLED", cfg.Metrics.Enabled))
+	cfg.Metrics.Addr = cast.ToString(Coalesce("METRICS_ADDR", cfg.Metrics.Addr))
+
+	cfg.Tracing.Exporter = cast.ToString(Coalesce("TRACING_EXPORTER", cfg.Tracing.Exporter))
+	cfg.Tracing.OTLPEndpoint = cast.ToString(Coalesce("TRACING_OTLP_ENDPOINT", cfg.Tracing.OTLPEndpoint))
+	cfg.Tracing.Insecure = cast.ToBool(Coalesce("TRACING_INSECURE", cfg.Tracing.Insecure))
+	cfg.Tracing.SampleRatio = cast.ToFloat64(Coalesce("TRACING_SAMPLE_RATIO", cfg.Tracing.SampleRatio))
+	cfg.Tracing.ServiceName = cast.ToString(Coalesce("TRACING_SERVICE_NAME", cfg.Tracing.ServiceName))
+
+	cfg.Outbox.Stream = cast.ToString(Coalesce("OUTBOX_STREAM", cfg.Outbox.Stream))
+	cfg.Outbox.BatchSize = cast.ToInt(Coalesce("OUTBOX_BATCH_SIZE", cfg.Outbox.BatchSize))
+	cfg.Outbox.PollInterval = duration("OUTBOX_POLL_INTERVAL", cfg.Outbox.PollInterval)
+	cfg.Outbox.Lease = duration("OUTBOX_LEASE", cfg.Outbox.Lease)
+	cfg.Outbox.MaxBackoff = duration("OUTBOX_MAX_BACKOFF", cfg.Outbox.MaxBackoff)
+
+	cfg.Notify.Email = cast.ToString(Coalesce("NOTIFY_EMAIL", cfg.Notify.Email))
+	cfg.Notify.SMS = cast.ToString(Coalesce("NOTIFY_SMS", cfg.Notify.SMS))
+	cfg.Notify.File = cast.ToString(Coalesce("NOTIFY_FILE", cfg.Notify.File))
+	cfg.Notify.SMTP.Addr = cast.ToString(Coalesce("SMTP_ADDR", cfg.Notify.SMTP.Addr))
+	cfg.Notify.SMTP.From = cast.ToString(Coalesce("SMTP_FROM", cfg.Notify.SMTP.From))
+	cfg.Notify.SMTP.Username = cast.ToString(Coalesce("SMTP_USERNAME", cfg.Notify.SMTP.Username))
+	cfg.Notify.SMTP.Password = cast.ToString(Coalesce("SMTP_PASSWORD", cfg.Notify.SMTP.Password))
+	cfg.Notify.BatchSize = cast.ToInt(Coalesce("NOTIFY_BATCH_SIZE", cfg.Notify.BatchSize))
+	cfg.Notify.PollInterval = duration("NOTIFY_POLL_INTERVAL", cfg.Notify.PollInterval)
+	cfg.Notify.Lease = duration("NOTIFY_LEASE", cfg.Notify.Lease)
+	cfg.Notify.MaxAttempts = cast.ToInt(Coalesce("NOTIFY_MAX_ATTEMPTS", cfg.Notify.MaxAttempts))
+	cfg.Notify.RetryInterval = duration("NOTIFY_RETRY_INTERVAL", cfg.Notify.RetryInterval)
+
+	cfg.Geocoder.Provider = cast.ToString(Coalesce("GEOCODER", cfg.Geocoder.Provider))
+	cfg.Geocoder.URL = cast.ToString(Coalesce("GEOCODER_URL", cfg.Geocoder.URL))
+	cfg.Geocoder.UserAgent = cast.ToString(Coalesce("GEOCODER_USER_AGENT", cfg.Geocoder.UserAgent))
+	cfg.Geocoder.Timeout = duration("GEOCODER_TIMEOUT", cfg.Geocoder.Timeout)
+
+	cfg.Features.Reflection = cast.ToBool(Coalesce("FEATURE_REFLECTION", cfg.Features.Reflection))
+	cfg.Features.PurgeJob = cast.ToBool(Coalesce("FEATURE_PURGE_JOB", cfg.Features.PurgeJob))
+	cfg.Features.NoShowJob = cast.ToBool(Coalesce("FEATURE_NO_SHOW_JOB", cfg.Features.NoShowJob))
+	cfg.Features.DepositExpiryJob = cast.ToBool(Coalesce("FEATURE_DEPOSIT_EXPIRY_JOB", cfg.Features.DepositExpiryJob))
+	cfg.Features.PriceChangeJob = cast.ToBool(Coalesce("FEATURE_PRICE_CHANGE_JOB", cfg.Features.PriceChangeJob))
+	cfg.Features.OutboxRelay = cast.ToBool(Coalesce("FEATURE_OUTBOX_RELAY", cfg.Features.OutboxRelay))
+	cfg.Features.Notifications = cast.ToBool(Coalesce("FEATURE_NOTIFICATIONS", cfg.Features.Notifications))
+
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func defaults() Config {
+	return Config{
+		GRPC: GRPCConfig{
+			Port:                ":50051",
+			ShutdownTimeout:     15 * time.Second,
+			HealthCheckInterval: 5 * time.Second,
+		},
+		Postgres: PostgresConfig{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Database:        "reservation_service",
+			SSLMode:         "disable",
+			ConnectTimeout:  5 * time.Second,
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Redis: RedisConfig{
+			Addr:         "localhost:6379",
+			PoolSize:     10,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+		},
+		Payment: ServiceConfig{
+			Addr:    "localhost:50052",
+			Timeout: 5 * time.Second,
+		},
+		Auth: ServiceConfig{
+			Addr:    "localhost:50053",
+			Timeout: 5 * time.Second,
+		},
+		Purge: PurgeConfig{
+			RetentionDays: 30,
+			Interval:      24 * time.Hour,
+		},
+		NoShow: NoShowConfig{
+			GracePeriod: 30 * time.Minute,
+			Interval:    5 * time.Minute,
+		},
+		Deposit: DepositConfig{
+			HoldTTL:  30 * time.Minute,
+			Interval: time.Minute,
+		},
+		Prices: PriceConfig{
+			Interval: time.Minute,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Output: "both",
+			File:   "logs/app.log",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Addr:    ":9090",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4317",
+			Insecure:     true,
+			SampleRatio:  1,
+			ServiceName:  "reservation-service",
+		},
+		Outbox: OutboxConfig{
+			Stream:       "reservation-events",
+			BatchSize:    100,
+			PollInterval: time.Second,
+			Lease:        30 * time.Second,
+			MaxBackoff:   5 * time.Minute,
+		},
+		Notify: NotifyConfig{
+			Email:         "log",
+			SMS:           "log",
+			File:          "logs/notifications.log",
+			BatchSize:     50,
+			PollInterval:  30 * time.Second,
+			Lease:         time.Minute,
+			MaxAttempts:   5,
+			RetryInterval: time.Minute,
+		},
+		Geocoder: GeocoderConfig{
+			Provider:  "none",
+			URL:       "https://nominatim.openstreetmap.org",
+			UserAgent: "reservation-service",
+			Timeout:   5 * time.Second,
+		},
+		Features: FeatureFlags{
+			PurgeJob:         true,
+			NoShowJob:        true,
+			DepositExpiryJob: true,
+			PriceChangeJob:   true,
+			OutboxRelay:      true,
+			Notifications:    true,
+		},
+	}
+}
+
+// Validate reports every invalid setting at once so a misconfigured
+// deployment can be fixed in a single pass.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.GRPC.Port != "", "GRPC_PORT is required")
+	check(c.GRPC.ShutdownTimeout > 0, "GRPC_SHUTDOWN_TIMEOUT must be positive")
+	check(c.GRPC.HealthCheckInterval > 0, "GRPC_HEALTH_CHECK_INTERVAL must be positive")
+	if c.GRPC.TLS.Enabled {
+		check(c.GRPC.TLS.CertFile != "", "GRPC_TLS_CERT_FILE is required when TLS is enabled")
+		check(c.GRPC.TLS.KeyFile != "", "GRPC_TLS_KEY_FILE is required when TLS is enabled")
+	}
+
+	check(c.Postgres.Host != "", "DB_HOST is required")
+	check(c.Postgres.Port > 0 && c.Postgres.Port < 65536, "DB_PORT %d is out of range", c.Postgres.Port)
+	check(c.Postgres.User != "", "DB_USERNAME is required")
+	check(c.Postgres.Database != "", "DB_DATABASE is required")
+	check(c.Postgres.Password != "", "DB_PASSWORD is required")
+	check(c.Postgres.ConnectTimeout >= time.Second, "DB_CONNECT_TIMEOUT must be at least 1s")
+	check(c.Postgres.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS must be positive")
+	check(c.Postgres.MaxIdleConns >= 0 && c.Postgres.MaxIdleConns <= c.Postgres.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+
+	check(c.Redis.Addr != "", "REDIS_ADDR is required")
+	check(c.Redis.DB >= 0, "REDIS_DB must not be negative")
+	check(c.Redis.PoolSize > 0, "REDIS_POOL_SIZE must be positive")
+
+	check(c.Payment.Addr != "", "PAYMENT_SERVICE_ADDR is required")
+	check(c.Payment.Timeout > 0, "PAYMENT_SERVICE_TIMEOUT must be positive")
+	check(c.Auth.Addr != "", "AUTH_SERVICE_ADDR is required")
+	check(c.Auth.Timeout > 0, "AUTH_SERVICE_TIMEOUT must be positive")
+	check(c.Tokens.Secret == "" || len(c.Tokens.Secret) >= 32, "AUTH_TOKEN_SECRET must be at least 32 bytes")
+
+	if c.Features.PurgeJob {
+		check(c.Purge.RetentionDays >= 0, "PURGE_RETENTION_DAYS must not be negative")
+		check(c.Purge.Interval > 0, "PURGE_INTERVAL must be positive")
+	}
+
+	if c.Features.NoShowJob {
+		check(c.NoShow.GracePeriod >= 0, "NO_SHOW_GRACE_PERIOD must not be negative")
+		check(c.NoShow.Interval > 0, "NO_SHOW_INTERVAL must be positive")
+	}
+
+	if c.Features.DepositExpiryJob {
+		check(c.Deposit.HoldTTL > 0, "DEPOSIT_HOLD_TTL must be positive")
+		check(c.Deposit.Interval > 0, "DEPOSIT_EXPIRY_INTERVAL must be positive")
+	}
+
+	if c.Features.PriceChangeJob {
+		check(c.Prices.Interval > 0, "PRICE_CHANGE_INTERVAL must be positive")
+	}
+
+	if c.Features.OutboxRelay {
+		check(c.Outbox.Stream != "", "OUTBOX_STREAM is required when the outbox relay is enabled")
+		check(c.Outbox.BatchSize > 0, "OUTBOX_BATCH_SIZE must be positive")
+		check(c.Outbox.PollInterval > 0, "OUTBOX_POLL_INTERVAL must be positive")
+		check(c.Outbox.Lease > 0, "OUTBOX_LEASE must be positive")
+		check(c.Outbox.MaxBackoff >= c.Outbox.PollInterval, "OUTBOX_MAX_BACKOFF must not be shorter than OUTBOX_POLL_INTERVAL")
+	}
+
+	if c.Features.Notifications {
+		switch c.Notify.Email {
+		case "none", "log", "file":
+		case "smtp":
+			check(c.Notify.SMTP.Addr != "", "SMTP_ADDR is required when NOTIFY_EMAIL is smtp")
+			check(c.Notify.SMTP.From != "", "SMTP_FROM is required when NOTIFY_EMAIL is smtp")
+		default:
+			check(false, "NOTIFY_EMAIL %q must be one of none, log, file, smtp", c.Notify.Email)
+		}
+		switch c.Notify.SMS {
+		case "none", "log", "file":
+		default:
+			check(false, "NOTIFY_SMS %q must be one of none, log, file", c.Notify.SMS)
+		}
+		if c.Notify.Email == "file" || c.Notify.SMS == "file" {
+			check(c.Notify.File != "", "NOTIFY_FILE is required for the file channel")
+		}
+		check(c.Notify.BatchSize > 0, "NOTIFY_BATCH_SIZE must be positive")
+		check(c.Notify.PollInterval > 0, "NOTIFY_POLL_INTERVAL must be positive")
+		check(c.Notify.Lease > 0, "NOTIFY_LEASE must be positive")
+		check(c.Notify.MaxAttempts > 0 && c.Notify.MaxAttempts <= 10, "NOTIFY_MAX_ATTEMPTS must be between 1 and 10")
+		check(c.Notify.RetryInterval > 0, "NOTIFY_RETRY_INTERVAL must be positive")
+	}
+
+	switch c.Geocoder.Provider {
+	case "none":
+	case "nominatim":
+		check(c.Geocoder.URL != "", "GEOCODER_URL is required when GEOCODER is nominatim")
+		check(c.Geocoder.UserAgent != "", "GEOCODER_USER_AGENT is required when GEOCODER is nominatim")
+		check(c.Geocoder.Timeout > 0, "GEOCODER_TIMEOUT must be positive")
+	default:
+		check(false, "GEOCODER %q must be one of none, nominatim", c.Geocoder.Provider)
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "LOG_LEVEL %q must be one of debug, info, warn, error", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Output) {
+	case "stdout":
+	case "file", "both":
+		check(c.Log.File != "", "LOG_FILE is required when LOG_OUTPUT is %s", c.Log.Output)
+	default:
+		check(false, "LOG_OUTPUT %q must be one of stdout, file, both", c.Log.Output)
+	}
+
+	if c.Metrics.Enabled {
+		check(c.Metrics.Addr != "", "METRICS_ADDR is required when metrics are enabled")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		check(c.Tracing.OTLPEndpoint != "", "TRACING_OTLP_ENDPOINT is required for the otlp exporter")
+	default:
+		check(false, "TRACING_EXPORTER %q must be one of none, stdout, otlp", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
 }
 
 func Coalesce(key string, defaultValue interface{}) interface{} {
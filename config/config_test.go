@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDefaultsRequirePassword(t *testing.T) {
+	cfg := defaults()
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_PASSWORD is required")
+
+	cfg.Postgres.Password = "secret"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := defaults()
+	cfg.Postgres.Password = "secret"
+	cfg.Postgres.Port = 70000
+	cfg.Redis.Addr = ""
+	cfg.GRPC.TLS.Enabled = true
+	cfg.Tokens.Secret = "short"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_PORT 70000 is out of range")
+	assert.Contains(t, err.Error(), "REDIS_ADDR is required")
+	assert.Contains(t, err.Error(), "GRPC_TLS_CERT_FILE is required")
+	assert.Contains(t, err.Error(), "AUTH_TOKEN_SECRET must be at least 32 bytes")
+}
+
+func TestLoadYAMLThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := `
+postgres:
+  password: from-file
+  max_open_conns: 50
+redis:
+  addr: redis:6379
+payment_service:
+  timeout: 2s
+`
+	assert.NoError(t, os.WriteFile(path, []byte(yaml), 0600))
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("REDIS_ADDR", "cache:6380")
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Postgres.Password)
+	assert.Equal(t, 50, cfg.Postgres.MaxOpenConns)
+	assert.Equal(t, "cache:6380", cfg.Redis.Addr)
+	assert.Equal(t, 2*time.Second, cfg.Payment.Timeout)
+	assert.Equal(t, "localhost", cfg.Postgres.Host)
+}
+
+func TestLoadRejectsUnitlessDurations(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("PURGE_INTERVAL", "24")
+	t.Setenv("NOTIFY_LEASE", "soon")
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `PURGE_INTERVAL "24" must be a duration with a unit`)
+	assert.Contains(t, err.Error(), `NOTIFY_LEASE "soon" must be a duration with a unit`)
+
+	t.Setenv("PURGE_INTERVAL", "24h")
+	t.Setenv("NOTIFY_LEASE", "90s")
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.Purge.Interval)
+	assert.Equal(t, 90*time.Second, cfg.Notify.Lease)
+}
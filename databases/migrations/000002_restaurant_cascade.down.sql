@@ -0,0 +1,7 @@
+-- Drop Outbox Table
+DROP TABLE IF EXISTS Outbox;
+
+ALTER TABLE Reservations DROP COLUMN IF EXISTS payment_id;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS cancellation_reason;
+
+ALTER TABLE Restaurants DROP COLUMN IF EXISTS is_active;
@@ -0,0 +1,19 @@
+-- Restaurants can be taken offline without being deleted
+ALTER TABLE Restaurants ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT true;
+
+-- Why a reservation was cancelled and which payment settled it
+ALTER TABLE Reservations ADD COLUMN cancellation_reason TEXT;
+ALTER TABLE Reservations ADD COLUMN payment_id UUID;
+
+-- Create Outbox Table
+CREATE TABLE Outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX outbox_unpublished_idx ON Outbox (created_at) WHERE published_at IS NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS outbox_unpublished_idx;
+CREATE INDEX outbox_unpublished_idx ON Outbox (created_at) WHERE published_at IS NULL;
+
+ALTER TABLE Outbox DROP COLUMN IF EXISTS last_error;
+ALTER TABLE Outbox DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE Outbox DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,7 @@
+-- Delivery bookkeeping for the outbox relay
+ALTER TABLE Outbox ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE Outbox ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE Outbox ADD COLUMN last_error TEXT;
+
+DROP INDEX IF EXISTS outbox_unpublished_idx;
+CREATE INDEX outbox_unpublished_idx ON Outbox (next_attempt_at, created_at) WHERE published_at IS NULL;
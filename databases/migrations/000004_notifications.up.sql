@@ -0,0 +1,30 @@
+-- Where and how a guest wants to be notified
+CREATE TABLE NotificationPreferences (
+    user_id UUID PRIMARY KEY,
+    email VARCHAR(255),
+    phone_number VARCHAR(50),
+    email_enabled BOOLEAN NOT NULL DEFAULT true,
+    sms_enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Notifications Table
+CREATE TABLE Notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reservation_id UUID NOT NULL REFERENCES Reservations(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL CHECK (kind IN ('confirmation', 'change', 'cancellation', 'reminder_24h', 'reminder_2h')),
+    reservation_time TIMESTAMP NOT NULL,
+    send_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending' CHECK (status IN ('Pending', 'Sent', 'Skipped', 'Failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX notifications_due_idx ON Notifications (send_at) WHERE status = 'Pending';
+
+-- A reminder is sent once per reservation time
+CREATE UNIQUE INDEX notifications_reminder_idx ON Notifications (reservation_id, kind, reservation_time)
+    WHERE kind IN ('reminder_24h', 'reminder_2h');
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS reservations_no_show_idx;
+
+DROP TABLE IF EXISTS NoShowPolicies;
+DROP TABLE IF EXISTS GuestNoShows;
+
+ALTER TABLE Reservations DROP COLUMN IF EXISTS deposit_required;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS seated_at;
+UPDATE Reservations SET status = 'Confirmed' WHERE status = 'Seated';
+UPDATE Reservations SET status = 'Cancelled' WHERE status = 'NoShow';
+ALTER TABLE Reservations DROP CONSTRAINT IF EXISTS reservations_status_check;
+ALTER TABLE Reservations ADD CONSTRAINT reservations_status_check
+    CHECK (status IN ('Pending', 'Confirmed', 'Cancelled'));
@@ -0,0 +1,25 @@
+-- Guests can be seated or marked as no-show
+ALTER TABLE Reservations DROP CONSTRAINT IF EXISTS reservations_status_check;
+ALTER TABLE Reservations ADD CONSTRAINT reservations_status_check
+    CHECK (status IN ('Pending', 'Confirmed', 'Cancelled', 'Seated', 'NoShow'));
+ALTER TABLE Reservations ADD COLUMN seated_at TIMESTAMP;
+ALTER TABLE Reservations ADD COLUMN deposit_required BOOLEAN NOT NULL DEFAULT false;
+
+-- No-shows per guest
+CREATE TABLE GuestNoShows (
+    user_id UUID PRIMARY KEY,
+    no_show_count INTEGER NOT NULL DEFAULT 0,
+    last_no_show_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- What a restaurant does with guests who often do not show up
+CREATE TABLE NoShowPolicies (
+    restaurant_id UUID PRIMARY KEY REFERENCES Restaurants(id) ON DELETE CASCADE,
+    threshold INTEGER NOT NULL CHECK (threshold >= 0),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('none', 'deposit', 'block')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX reservations_no_show_idx ON Reservations (reservation_time) WHERE status = 'Confirmed' AND deleted_at = 0;
@@ -0,0 +1,5 @@
+ALTER TABLE Reservations DROP COLUMN IF EXISTS cancellation_fee;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS cancellation_policy;
+
+DROP TABLE IF EXISTS NonRefundableSlots;
+DROP TABLE IF EXISTS CancellationPolicies;
@@ -0,0 +1,23 @@
+-- Per restaurant cancellation terms
+CREATE TABLE CancellationPolicies (
+    restaurant_id UUID PRIMARY KEY REFERENCES Restaurants(id) ON DELETE CASCADE,
+    free_until_hours INTEGER NOT NULL DEFAULT 0 CHECK (free_until_hours >= 0),
+    late_fee_percent INTEGER NOT NULL DEFAULT 0 CHECK (late_fee_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Weekly slots in which bookings cannot be refunded; NULL day_of_week means every day
+CREATE TABLE NonRefundableSlots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES CancellationPolicies(restaurant_id) ON DELETE CASCADE,
+    day_of_week SMALLINT CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time > start_time)
+);
+
+CREATE INDEX non_refundable_slots_restaurant_idx ON NonRefundableSlots (restaurant_id);
+
+-- The policy applied when the guest cancelled
+ALTER TABLE Reservations ADD COLUMN cancellation_policy JSONB;
+ALTER TABLE Reservations ADD COLUMN cancellation_fee DECIMAL(10, 2);
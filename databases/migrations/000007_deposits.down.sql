@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS reservations_deposit_hold_idx;
+
+DROP TABLE IF EXISTS DepositRules;
+
+ALTER TABLE Reservations DROP COLUMN IF EXISTS deposit_paid_at;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS deposit_payment_id;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS deposit_amount;
+ALTER TABLE Reservations DROP COLUMN IF EXISTS party_size;
@@ -0,0 +1,23 @@
+-- Party size and deposit held for a reservation
+ALTER TABLE Reservations ADD COLUMN party_size INTEGER NOT NULL DEFAULT 1 CHECK (party_size > 0);
+ALTER TABLE Reservations ADD COLUMN deposit_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE Reservations ADD COLUMN deposit_payment_id UUID;
+ALTER TABLE Reservations ADD COLUMN deposit_paid_at TIMESTAMP;
+
+-- Per restaurant deposit rules; NULL day_of_week means every day, NULL times the whole day
+CREATE TABLE DepositRules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    amount_per_person DECIMAL(10, 2) NOT NULL CHECK (amount_per_person > 0),
+    day_of_week SMALLINT CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME,
+    end_time TIME,
+    min_party_size INTEGER NOT NULL DEFAULT 1 CHECK (min_party_size > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((start_time IS NULL) = (end_time IS NULL) AND (start_time IS NULL OR end_time > start_time))
+);
+
+CREATE INDEX deposit_rules_restaurant_idx ON DepositRules (restaurant_id);
+
+CREATE INDEX reservations_deposit_hold_idx ON Reservations (created_at)
+    WHERE status = 'Pending' AND deposit_required AND deposit_paid_at IS NULL AND deleted_at = 0;
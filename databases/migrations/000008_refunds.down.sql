@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Refunds;
+
+DROP TABLE IF EXISTS RestaurantStaff;
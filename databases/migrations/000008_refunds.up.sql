@@ -0,0 +1,28 @@
+-- Restaurant staff and their role; managers may refund beyond the cancellation policy
+CREATE TABLE RestaurantStaff (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('staff', 'manager')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (restaurant_id, user_id)
+);
+
+-- Refunds of reservation payments; balance_before is the payment amount the refund was taken from
+CREATE TABLE Refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    reservation_id UUID NOT NULL REFERENCES Reservations(id),
+    payment_id UUID NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    balance_before DECIMAL(10, 2) NOT NULL,
+    reason TEXT,
+    requested_by UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending' CHECK (status IN ('Pending', 'Completed')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX refunds_reservation_idx ON Refunds (reservation_id);
+
+-- Only one refund of a payment may be in flight at a time
+CREATE UNIQUE INDEX refunds_pending_payment_idx ON Refunds (payment_id) WHERE status = 'Pending';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS reservation_orders_reservation_idx;
+
+ALTER TABLE Reservations DROP COLUMN IF EXISTS promo_code_id;
+
+DROP TABLE IF EXISTS PromoCodes;
+DROP TABLE IF EXISTS RestaurantTaxes;
+DROP TABLE IF EXISTS BillingSettings;
@@ -0,0 +1,37 @@
+-- Service charge of a restaurant, in percent of the discounted subtotal
+CREATE TABLE BillingSettings (
+    restaurant_id UUID PRIMARY KEY REFERENCES Restaurants(id) ON DELETE CASCADE,
+    service_charge_percent DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (service_charge_percent BETWEEN 0 AND 100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Taxes a restaurant charges, each in percent of the discounted subtotal plus service charge
+CREATE TABLE RestaurantTaxes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    percent DECIMAL(5, 2) NOT NULL CHECK (percent > 0 AND percent <= 100),
+    UNIQUE (restaurant_id, name)
+);
+
+-- Promo codes give either percent_off or amount_off of the subtotal
+CREATE TABLE PromoCodes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    percent_off DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (percent_off BETWEEN 0 AND 100),
+    amount_off DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount_off >= 0),
+    min_subtotal DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    valid_until TIMESTAMP,
+    max_redemptions INTEGER,
+    redemptions INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (restaurant_id, code),
+    CHECK ((percent_off > 0) <> (amount_off > 0))
+);
+
+ALTER TABLE Reservations ADD COLUMN promo_code_id UUID REFERENCES PromoCodes(id) ON DELETE SET NULL;
+
+CREATE INDEX reservation_orders_reservation_idx ON ReservationOrders (reservation_id) WHERE deleted_at = 0;
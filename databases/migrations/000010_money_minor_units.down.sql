@@ -0,0 +1,30 @@
+UPDATE Reservations
+SET cancellation_policy = (cancellation_policy - 'fee' - 'refund')
+    || CASE WHEN cancellation_policy ? 'fee' THEN jsonb_build_object('fee',
+        (cancellation_policy->'fee'->>'minorUnits')::numeric / 100) ELSE '{}'::jsonb END
+    || CASE WHEN cancellation_policy ? 'refund' THEN jsonb_build_object('refund',
+        (cancellation_policy->'refund'->>'minorUnits')::numeric / 100) ELSE '{}'::jsonb END
+WHERE cancellation_policy IS NOT NULL;
+
+ALTER TABLE PromoCodes RENAME COLUMN min_subtotal_minor TO min_subtotal;
+ALTER TABLE PromoCodes ALTER COLUMN min_subtotal TYPE DECIMAL(10, 2) USING min_subtotal / 100.0;
+ALTER TABLE PromoCodes RENAME COLUMN amount_off_minor TO amount_off;
+ALTER TABLE PromoCodes ALTER COLUMN amount_off TYPE DECIMAL(10, 2) USING amount_off / 100.0;
+
+ALTER TABLE Refunds RENAME COLUMN balance_before_minor TO balance_before;
+ALTER TABLE Refunds ALTER COLUMN balance_before TYPE DECIMAL(10, 2) USING balance_before / 100.0;
+ALTER TABLE Refunds RENAME COLUMN amount_minor TO amount;
+ALTER TABLE Refunds ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+
+ALTER TABLE DepositRules RENAME COLUMN amount_per_person_minor TO amount_per_person;
+ALTER TABLE DepositRules ALTER COLUMN amount_per_person TYPE DECIMAL(10, 2) USING amount_per_person / 100.0;
+
+ALTER TABLE Reservations RENAME COLUMN cancellation_fee_minor TO cancellation_fee;
+ALTER TABLE Reservations ALTER COLUMN cancellation_fee TYPE DECIMAL(10, 2) USING cancellation_fee / 100.0;
+ALTER TABLE Reservations RENAME COLUMN deposit_minor TO deposit_amount;
+ALTER TABLE Reservations ALTER COLUMN deposit_amount TYPE DECIMAL(10, 2) USING deposit_amount / 100.0;
+
+ALTER TABLE Menu RENAME COLUMN price_minor TO price;
+ALTER TABLE Menu ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0;
+
+ALTER TABLE Restaurants DROP COLUMN IF EXISTS currency;
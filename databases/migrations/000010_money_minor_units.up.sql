@@ -0,0 +1,36 @@
+-- Amounts are stored as integer minor units of the restaurant's currency.
+-- Existing amounts are in a currency with cents, so they are scaled by 100.
+ALTER TABLE Restaurants ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE Menu ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100)::bigint;
+ALTER TABLE Menu RENAME COLUMN price TO price_minor;
+
+ALTER TABLE Reservations ALTER COLUMN deposit_amount TYPE BIGINT USING ROUND(deposit_amount * 100)::bigint;
+ALTER TABLE Reservations RENAME COLUMN deposit_amount TO deposit_minor;
+ALTER TABLE Reservations ALTER COLUMN cancellation_fee TYPE BIGINT USING ROUND(cancellation_fee * 100)::bigint;
+ALTER TABLE Reservations RENAME COLUMN cancellation_fee TO cancellation_fee_minor;
+
+ALTER TABLE DepositRules ALTER COLUMN amount_per_person TYPE BIGINT USING ROUND(amount_per_person * 100)::bigint;
+ALTER TABLE DepositRules RENAME COLUMN amount_per_person TO amount_per_person_minor;
+
+ALTER TABLE Refunds ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100)::bigint;
+ALTER TABLE Refunds RENAME COLUMN amount TO amount_minor;
+ALTER TABLE Refunds ALTER COLUMN balance_before TYPE BIGINT USING ROUND(balance_before * 100)::bigint;
+ALTER TABLE Refunds RENAME COLUMN balance_before TO balance_before_minor;
+
+ALTER TABLE PromoCodes ALTER COLUMN amount_off TYPE BIGINT USING ROUND(amount_off * 100)::bigint;
+ALTER TABLE PromoCodes RENAME COLUMN amount_off TO amount_off_minor;
+ALTER TABLE PromoCodes ALTER COLUMN min_subtotal TYPE BIGINT USING ROUND(min_subtotal * 100)::bigint;
+ALTER TABLE PromoCodes RENAME COLUMN min_subtotal TO min_subtotal_minor;
+
+-- Recorded cancellation policies hold the fee and refund as Money objects
+UPDATE Reservations r
+SET cancellation_policy = (r.cancellation_policy - 'fee' - 'refund')
+    || CASE WHEN r.cancellation_policy ? 'fee' THEN jsonb_build_object('fee', jsonb_build_object(
+        'minorUnits', ROUND((r.cancellation_policy->>'fee')::numeric * 100)::bigint::text,
+        'currency', s.currency)) ELSE '{}'::jsonb END
+    || CASE WHEN r.cancellation_policy ? 'refund' THEN jsonb_build_object('refund', jsonb_build_object(
+        'minorUnits', ROUND((r.cancellation_policy->>'refund')::numeric * 100)::bigint::text,
+        'currency', s.currency)) ELSE '{}'::jsonb END
+FROM Restaurants s
+WHERE s.id = r.restaurant_id AND r.cancellation_policy IS NOT NULL;
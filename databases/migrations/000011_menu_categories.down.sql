@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS menu_category_idx;
+
+ALTER TABLE Menu
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS category_id;
+
+DROP INDEX IF EXISTS menu_categories_name_idx;
+DROP TABLE IF EXISTS MenuCategories;
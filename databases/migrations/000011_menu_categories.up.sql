@@ -0,0 +1,19 @@
+-- Sections of a restaurant's menu, shown by position
+CREATE TABLE MenuCategories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at BIGINT DEFAULT 0
+);
+
+CREATE UNIQUE INDEX menu_categories_name_idx ON MenuCategories (restaurant_id, lower(name)) WHERE deleted_at = 0;
+
+-- Items without a category are listed after all categories
+ALTER TABLE Menu
+    ADD COLUMN category_id UUID REFERENCES MenuCategories(id) ON DELETE SET NULL,
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX menu_category_idx ON Menu (category_id, position) WHERE deleted_at = 0;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS ReservationOrderOptions;
+
+ALTER TABLE ReservationOrders DROP COLUMN IF EXISTS options_key;
+
+DROP TABLE IF EXISTS ModifierOptions;
+DROP TABLE IF EXISTS ModifierGroups;
@@ -0,0 +1,36 @@
+-- Choices made when ordering a menu item, such as size or extras
+CREATE TABLE ModifierGroups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    min_selections INTEGER NOT NULL DEFAULT 0 CHECK (min_selections >= 0),
+    max_selections INTEGER NOT NULL CHECK (max_selections > 0 AND max_selections >= min_selections),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX modifier_groups_menu_item_idx ON ModifierGroups (menu_item_id);
+
+-- Options of a modifier group; price_delta_minor is added to the item's price
+CREATE TABLE ModifierOptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES ModifierGroups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta_minor BIGINT NOT NULL DEFAULT 0 CHECK (price_delta_minor >= 0),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX modifier_options_group_idx ON ModifierOptions (group_id);
+
+-- The same item with other options is another line of the order
+ALTER TABLE ReservationOrders ADD COLUMN options_key TEXT NOT NULL DEFAULT '';
+
+-- Options chosen for an order line, as they were priced when ordered
+CREATE TABLE ReservationOrderOptions (
+    order_id UUID NOT NULL REFERENCES ReservationOrders(id) ON DELETE CASCADE,
+    option_id UUID REFERENCES ModifierOptions(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    price_delta_minor BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX reservation_order_options_order_idx ON ReservationOrderOptions (order_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ReservationDietaryNotes;
+DROP TABLE IF EXISTS MenuItemTags;
+DROP TABLE IF EXISTS MenuTags;
@@ -0,0 +1,42 @@
+-- Allergens and dietary tags items and reservations refer to by code
+CREATE TABLE MenuTags (
+    code VARCHAR(50) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('allergen', 'dietary')),
+    name VARCHAR(100) NOT NULL
+);
+
+INSERT INTO MenuTags (code, kind, name) VALUES
+    ('gluten', 'allergen', 'Gluten'),
+    ('crustaceans', 'allergen', 'Crustaceans'),
+    ('eggs', 'allergen', 'Eggs'),
+    ('fish', 'allergen', 'Fish'),
+    ('peanuts', 'allergen', 'Peanuts'),
+    ('soy', 'allergen', 'Soy'),
+    ('milk', 'allergen', 'Milk'),
+    ('tree_nuts', 'allergen', 'Tree nuts'),
+    ('celery', 'allergen', 'Celery'),
+    ('mustard', 'allergen', 'Mustard'),
+    ('sesame', 'allergen', 'Sesame'),
+    ('sulphites', 'allergen', 'Sulphites'),
+    ('lupin', 'allergen', 'Lupin'),
+    ('molluscs', 'allergen', 'Molluscs'),
+    ('vegetarian', 'dietary', 'Vegetarian'),
+    ('vegan', 'dietary', 'Vegan'),
+    ('halal', 'dietary', 'Halal'),
+    ('kosher', 'dietary', 'Kosher'),
+    ('gluten_free', 'dietary', 'Gluten free');
+
+CREATE TABLE MenuItemTags (
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    tag_code VARCHAR(50) NOT NULL REFERENCES MenuTags(code),
+    PRIMARY KEY (menu_item_id, tag_code)
+);
+
+CREATE INDEX menu_item_tags_tag_idx ON MenuItemTags (tag_code);
+
+-- Allergies and diets a party asked about when booking
+CREATE TABLE ReservationDietaryNotes (
+    reservation_id UUID NOT NULL REFERENCES Reservations(id) ON DELETE CASCADE,
+    tag_code VARCHAR(50) NOT NULL REFERENCES MenuTags(code),
+    PRIMARY KEY (reservation_id, tag_code)
+);
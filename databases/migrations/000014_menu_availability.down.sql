@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS MenuItemStock;
+DROP TABLE IF EXISTS MenuItemAvailability;
+
+ALTER TABLE Menu
+    DROP COLUMN IF EXISTS daily_stock,
+    DROP COLUMN IF EXISTS sold_out;
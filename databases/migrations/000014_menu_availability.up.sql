@@ -0,0 +1,23 @@
+-- A sold out item stays on the menu but cannot be ordered; daily_stock is
+-- how many can be ordered per day, NULL meaning no limit
+ALTER TABLE Menu
+    ADD COLUMN sold_out BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN daily_stock INTEGER CHECK (daily_stock > 0);
+
+-- Times an item is served; an item without windows is served all day
+CREATE TABLE MenuItemAvailability (
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN -1 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time > start_time)
+);
+
+CREATE INDEX menu_item_availability_item_idx ON MenuItemAvailability (menu_item_id);
+
+-- How many of an item are ordered for reservations on a day
+CREATE TABLE MenuItemStock (
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    stock_date DATE NOT NULL,
+    ordered INTEGER NOT NULL DEFAULT 0 CHECK (ordered >= 0),
+    PRIMARY KEY (menu_item_id, stock_date)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE ReservationOrders DROP COLUMN IF EXISTS unit_price_minor;
+
+DROP TABLE IF EXISTS MenuPriceChanges;
@@ -0,0 +1,25 @@
+-- Every price a menu item has had or is scheduled to have. A change whose
+-- applied_at is NULL is scheduled and becomes the item's price once
+-- effective_at has passed
+CREATE TABLE MenuPriceChanges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    price_minor BIGINT NOT NULL CHECK (price_minor >= 0),
+    effective_at TIMESTAMP NOT NULL,
+    applied_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX menu_price_changes_item_idx ON MenuPriceChanges (menu_item_id, effective_at);
+CREATE INDEX menu_price_changes_scheduled_idx ON MenuPriceChanges (effective_at) WHERE applied_at IS NULL;
+
+INSERT INTO MenuPriceChanges (menu_item_id, price_minor, effective_at, applied_at)
+SELECT id, price_minor, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM Menu;
+
+-- The item's price when it was ordered, so later price changes leave
+-- existing orders alone
+ALTER TABLE ReservationOrders ADD COLUMN unit_price_minor BIGINT;
+
+UPDATE ReservationOrders o SET unit_price_minor = m.price_minor FROM Menu m WHERE m.id = o.menu_item_id;
+
+ALTER TABLE ReservationOrders ALTER COLUMN unit_price_minor SET NOT NULL;
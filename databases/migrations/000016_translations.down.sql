@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS MenuItemTranslations;
+DROP TABLE IF EXISTS RestaurantTranslations;
@@ -0,0 +1,18 @@
+-- Restaurant and menu item text per locale. Restaurants.name/description and
+-- Menu.name/description stay the untranslated text used when no translation
+-- matches the caller's locales
+CREATE TABLE RestaurantTranslations (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (restaurant_id, locale)
+);
+
+CREATE TABLE MenuItemTranslations (
+    menu_item_id UUID NOT NULL REFERENCES Menu(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (menu_item_id, locale)
+);
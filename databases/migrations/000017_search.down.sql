@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS menu_name_trgm_idx;
+DROP INDEX IF EXISTS menu_search_idx;
+DROP INDEX IF EXISTS restaurants_name_trgm_idx;
+DROP INDEX IF EXISTS restaurants_search_idx;
+
+ALTER TABLE Menu DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE Restaurants DROP COLUMN IF EXISTS search_vector;
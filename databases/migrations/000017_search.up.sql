@@ -0,0 +1,19 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Words of restaurants and menu items for Search, names weighing most. The
+-- simple configuration does not stem, as names are in several languages
+ALTER TABLE Restaurants ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(address, '')), 'C')
+) STORED;
+
+ALTER TABLE Menu ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX restaurants_search_idx ON Restaurants USING GIN (search_vector);
+CREATE INDEX restaurants_name_trgm_idx ON Restaurants USING GIN (name gin_trgm_ops);
+CREATE INDEX menu_search_idx ON Menu USING GIN (search_vector);
+CREATE INDEX menu_name_trgm_idx ON Menu USING GIN (name gin_trgm_ops);
@@ -0,0 +1,8 @@
+DROP FUNCTION IF EXISTS restaurant_open_at(UUID, TIMESTAMP);
+DROP TABLE IF EXISTS RestaurantOpeningHours;
+DROP INDEX IF EXISTS restaurants_location_idx;
+
+ALTER TABLE Restaurants
+    DROP CONSTRAINT IF EXISTS restaurants_location_check,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,40 @@
+-- Where a restaurant is, in degrees; NULL until it is set or geocoded
+ALTER TABLE Restaurants
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT restaurants_location_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Nearby searches narrow the restaurants to a bounding box first
+CREATE INDEX restaurants_location_idx ON Restaurants (latitude, longitude) WHERE deleted_at = 0;
+
+-- When a restaurant is open, on one weekday (0 is Sunday) or every day (-1).
+-- Hours closing before they open run past midnight
+CREATE TABLE RestaurantOpeningHours (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN -1 AND 6),
+    open_time TIME NOT NULL,
+    close_time TIME NOT NULL CHECK (close_time <> open_time)
+);
+
+CREATE INDEX restaurant_opening_hours_restaurant_idx ON RestaurantOpeningHours (restaurant_id);
+
+-- Whether a restaurant is open at a local time. A restaurant without opening
+-- hours is always open
+CREATE FUNCTION restaurant_open_at(restaurant UUID, at TIMESTAMP) RETURNS BOOLEAN AS $$
+    SELECT
+        NOT EXISTS (SELECT 1 FROM RestaurantOpeningHours WHERE restaurant_id = restaurant)
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                RestaurantOpeningHours h
+            WHERE
+                h.restaurant_id = restaurant
+                AND (
+                    (h.day_of_week IN (-1, EXTRACT(DOW FROM at)) AND at::time >= h.open_time
+                        AND (at::time < h.close_time OR h.close_time < h.open_time))
+                    OR (h.day_of_week IN (-1, EXTRACT(DOW FROM at - INTERVAL '1 day')) AND h.close_time < h.open_time
+                        AND at::time < h.close_time)
+                )
+        )
+$$ LANGUAGE SQL STABLE;
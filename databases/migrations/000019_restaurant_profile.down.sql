@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS RestaurantSocialLinks;
+DROP TABLE IF EXISTS RestaurantPhotos;
+DROP TABLE IF EXISTS RestaurantTagLinks;
+DROP TABLE IF EXISTS RestaurantTags;
+
+ALTER TABLE Restaurants
+    DROP COLUMN IF EXISTS capacity,
+    DROP COLUMN IF EXISTS price_level;
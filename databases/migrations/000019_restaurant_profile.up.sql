@@ -0,0 +1,75 @@
+-- Price level from 1 (inexpensive) to 4 (very expensive) and number of seats;
+-- NULL while unknown
+ALTER TABLE Restaurants
+    ADD COLUMN price_level SMALLINT CHECK (price_level BETWEEN 1 AND 4),
+    ADD COLUMN capacity INTEGER CHECK (capacity > 0);
+
+-- Cuisines and amenities restaurants refer to by code
+CREATE TABLE RestaurantTags (
+    code VARCHAR(50) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('cuisine', 'amenity')),
+    name VARCHAR(100) NOT NULL
+);
+
+INSERT INTO RestaurantTags (code, kind, name) VALUES
+    ('uzbek', 'cuisine', 'Uzbek'),
+    ('central_asian', 'cuisine', 'Central Asian'),
+    ('turkish', 'cuisine', 'Turkish'),
+    ('georgian', 'cuisine', 'Georgian'),
+    ('russian', 'cuisine', 'Russian'),
+    ('european', 'cuisine', 'European'),
+    ('italian', 'cuisine', 'Italian'),
+    ('french', 'cuisine', 'French'),
+    ('mediterranean', 'cuisine', 'Mediterranean'),
+    ('middle_eastern', 'cuisine', 'Middle Eastern'),
+    ('indian', 'cuisine', 'Indian'),
+    ('chinese', 'cuisine', 'Chinese'),
+    ('japanese', 'cuisine', 'Japanese'),
+    ('korean', 'cuisine', 'Korean'),
+    ('asian', 'cuisine', 'Asian'),
+    ('american', 'cuisine', 'American'),
+    ('mexican', 'cuisine', 'Mexican'),
+    ('seafood', 'cuisine', 'Seafood'),
+    ('steakhouse', 'cuisine', 'Steakhouse'),
+    ('pizza', 'cuisine', 'Pizza'),
+    ('fast_food', 'cuisine', 'Fast food'),
+    ('cafe', 'cuisine', 'Cafe'),
+    ('bakery', 'cuisine', 'Bakery'),
+    ('vegetarian', 'cuisine', 'Vegetarian'),
+    ('parking', 'amenity', 'Parking'),
+    ('wifi', 'amenity', 'Wi-Fi'),
+    ('kids', 'amenity', 'Kids friendly'),
+    ('wheelchair', 'amenity', 'Wheelchair accessible'),
+    ('outdoor_seating', 'amenity', 'Outdoor seating'),
+    ('private_room', 'amenity', 'Private room'),
+    ('live_music', 'amenity', 'Live music'),
+    ('card_payment', 'amenity', 'Card payment'),
+    ('delivery', 'amenity', 'Delivery'),
+    ('takeaway', 'amenity', 'Takeaway'),
+    ('smoking_area', 'amenity', 'Smoking area'),
+    ('pet_friendly', 'amenity', 'Pet friendly');
+
+CREATE TABLE RestaurantTagLinks (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    tag_code VARCHAR(50) NOT NULL REFERENCES RestaurantTags(code),
+    PRIMARY KEY (restaurant_id, tag_code)
+);
+
+CREATE INDEX restaurant_tag_links_tag_idx ON RestaurantTagLinks (tag_code);
+
+-- Photos of a restaurant, shown by position
+CREATE TABLE RestaurantPhotos (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    caption VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (restaurant_id, position)
+);
+
+-- A restaurant's page on each social network, or its website
+CREATE TABLE RestaurantSocialLinks (
+    restaurant_id UUID NOT NULL REFERENCES Restaurants(id) ON DELETE CASCADE,
+    network VARCHAR(50) NOT NULL,
+    url TEXT NOT NULL,
+    PRIMARY KEY (restaurant_id, network)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE Restaurants
+    DROP COLUMN IF EXISTS timezone;
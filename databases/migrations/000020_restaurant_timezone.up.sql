@@ -0,0 +1,3 @@
+-- The IANA time zone of a restaurant. Opening hours are in its local time
+ALTER TABLE Restaurants
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
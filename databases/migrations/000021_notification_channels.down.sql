@@ -0,0 +1,2 @@
+ALTER TABLE Notifications
+    DROP COLUMN IF EXISTS sent_channels;
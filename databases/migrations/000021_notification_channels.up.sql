@@ -0,0 +1,4 @@
+-- The channels a notification has already gone out on, so a retry only
+-- resends the ones that failed
+ALTER TABLE Notifications
+    ADD COLUMN sent_channels TEXT[] NOT NULL DEFAULT '{}';
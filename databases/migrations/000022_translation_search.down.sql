@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS menu_item_translations_name_trgm_idx;
+DROP INDEX IF EXISTS menu_item_translations_search_idx;
+DROP INDEX IF EXISTS restaurant_translations_name_trgm_idx;
+DROP INDEX IF EXISTS restaurant_translations_search_idx;
+
+ALTER TABLE MenuItemTranslations DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE RestaurantTranslations DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,16 @@
+-- Words of translated names and descriptions, so Search also finds
+-- restaurants and menu items by their text in other locales
+ALTER TABLE RestaurantTranslations ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', description), 'B')
+) STORED;
+
+ALTER TABLE MenuItemTranslations ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', description), 'B')
+) STORED;
+
+CREATE INDEX restaurant_translations_search_idx ON RestaurantTranslations USING GIN (search_vector);
+CREATE INDEX restaurant_translations_name_trgm_idx ON RestaurantTranslations USING GIN (name gin_trgm_ops);
+CREATE INDEX menu_item_translations_search_idx ON MenuItemTranslations USING GIN (search_vector);
+CREATE INDEX menu_item_translations_name_trgm_idx ON MenuItemTranslations USING GIN (name gin_trgm_ops);
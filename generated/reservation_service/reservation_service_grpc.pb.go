@@ -27,19 +27,66 @@ type ReservationServiceClient interface {
 	GetRestaurant(ctx context.Context, in *GetRestaurantRequest, opts ...grpc.CallOption) (*GetRestaurantResponse, error)
 	UpdateRestaurant(ctx context.Context, in *UpdateRestaurantRequest, opts ...grpc.CallOption) (*UpdateRestaurantResponse, error)
 	DeleteRestaurant(ctx context.Context, in *DeleteRestaurantRequest, opts ...grpc.CallOption) (*DeleteRestaurantResponse, error)
+	RestoreRestaurant(ctx context.Context, in *RestoreRestaurantRequest, opts ...grpc.CallOption) (*RestoreRestaurantResponse, error)
+	DeactivateRestaurant(ctx context.Context, in *DeactivateRestaurantRequest, opts ...grpc.CallOption) (*DeactivateRestaurantResponse, error)
+	ActivateRestaurant(ctx context.Context, in *ActivateRestaurantRequest, opts ...grpc.CallOption) (*ActivateRestaurantResponse, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	ListNearbyRestaurants(ctx context.Context, in *ListNearbyRestaurantsRequest, opts ...grpc.CallOption) (*ListNearbyRestaurantsResponse, error)
+	SetOpeningHours(ctx context.Context, in *SetOpeningHoursRequest, opts ...grpc.CallOption) (*SetOpeningHoursResponse, error)
+	SetRestaurantProfile(ctx context.Context, in *SetRestaurantProfileRequest, opts ...grpc.CallOption) (*SetRestaurantProfileResponse, error)
+	ListRestaurantTags(ctx context.Context, in *ListRestaurantTagsRequest, opts ...grpc.CallOption) (*ListRestaurantTagsResponse, error)
 	CreateReservation(ctx context.Context, in *CreateReservationRequest, opts ...grpc.CallOption) (*CreateReservationResponse, error)
 	ListReservations(ctx context.Context, in *ListReservationsRequest, opts ...grpc.CallOption) (*ListReservationsResponse, error)
 	GetReservation(ctx context.Context, in *GetReservationRequest, opts ...grpc.CallOption) (*GetReservationResponse, error)
 	UpdateReservation(ctx context.Context, in *UpdateReservationRequest, opts ...grpc.CallOption) (*UpdateReservationResponse, error)
 	DeleteReservation(ctx context.Context, in *DeleteReservationRequest, opts ...grpc.CallOption) (*DeleteReservationResponse, error)
+	RestoreReservation(ctx context.Context, in *RestoreReservationRequest, opts ...grpc.CallOption) (*RestoreReservationResponse, error)
+	CancelReservation(ctx context.Context, in *CancelReservationRequest, opts ...grpc.CallOption) (*CancelReservationResponse, error)
 	CheckReservation(ctx context.Context, in *CheckReservationRequest, opts ...grpc.CallOption) (*CheckReservationResponse, error)
 	OrderMeals(ctx context.Context, in *OrderMealsRequest, opts ...grpc.CallOption) (*OrderMealsResponse, error)
+	GetBill(ctx context.Context, in *GetBillRequest, opts ...grpc.CallOption) (*GetBillResponse, error)
+	ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error)
 	PayReservation(ctx context.Context, in *MakePaymentRequest, opts ...grpc.CallOption) (*MakePaymentResponse, error)
+	PayDeposit(ctx context.Context, in *PayDepositRequest, opts ...grpc.CallOption) (*PayDepositResponse, error)
+	RefundReservation(ctx context.Context, in *RefundReservationRequest, opts ...grpc.CallOption) (*RefundReservationResponse, error)
+	ListRefunds(ctx context.Context, in *ListRefundsRequest, opts ...grpc.CallOption) (*ListRefundsResponse, error)
+	SeatReservation(ctx context.Context, in *SeatReservationRequest, opts ...grpc.CallOption) (*SeatReservationResponse, error)
+	SetRestaurantStaff(ctx context.Context, in *SetRestaurantStaffRequest, opts ...grpc.CallOption) (*SetRestaurantStaffResponse, error)
+	ListRestaurantStaff(ctx context.Context, in *ListRestaurantStaffRequest, opts ...grpc.CallOption) (*ListRestaurantStaffResponse, error)
+	GetBillingSettings(ctx context.Context, in *GetBillingSettingsRequest, opts ...grpc.CallOption) (*GetBillingSettingsResponse, error)
+	SetBillingSettings(ctx context.Context, in *SetBillingSettingsRequest, opts ...grpc.CallOption) (*SetBillingSettingsResponse, error)
+	SetPromoCode(ctx context.Context, in *SetPromoCodeRequest, opts ...grpc.CallOption) (*SetPromoCodeResponse, error)
+	ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (*ListPromoCodesResponse, error)
+	ListDepositRules(ctx context.Context, in *ListDepositRulesRequest, opts ...grpc.CallOption) (*ListDepositRulesResponse, error)
+	SetDepositRules(ctx context.Context, in *SetDepositRulesRequest, opts ...grpc.CallOption) (*SetDepositRulesResponse, error)
+	GetCancellationPolicy(ctx context.Context, in *GetCancellationPolicyRequest, opts ...grpc.CallOption) (*GetCancellationPolicyResponse, error)
+	SetCancellationPolicy(ctx context.Context, in *SetCancellationPolicyRequest, opts ...grpc.CallOption) (*SetCancellationPolicyResponse, error)
+	GetNoShowPolicy(ctx context.Context, in *GetNoShowPolicyRequest, opts ...grpc.CallOption) (*GetNoShowPolicyResponse, error)
+	SetNoShowPolicy(ctx context.Context, in *SetNoShowPolicyRequest, opts ...grpc.CallOption) (*SetNoShowPolicyResponse, error)
+	GetGuestNoShows(ctx context.Context, in *GetGuestNoShowsRequest, opts ...grpc.CallOption) (*GetGuestNoShowsResponse, error)
 	CreateMenuItem(ctx context.Context, in *CreateMenuItemRequest, opts ...grpc.CallOption) (*CreateMenuItemResponse, error)
 	ListMenuItems(ctx context.Context, in *ListMenuItemsRequest, opts ...grpc.CallOption) (*ListMenuItemsResponse, error)
 	GetMenuItem(ctx context.Context, in *GetMenuItemRequest, opts ...grpc.CallOption) (*GetMenuItemResponse, error)
 	UpdateMenuItem(ctx context.Context, in *UpdateMenuItemRequest, opts ...grpc.CallOption) (*UpdateMenuItemResponse, error)
 	DeleteMenuItem(ctx context.Context, in *DeleteMenuItemRequest, opts ...grpc.CallOption) (*DeleteMenuItemResponse, error)
+	RestoreMenuItem(ctx context.Context, in *RestoreMenuItemRequest, opts ...grpc.CallOption) (*RestoreMenuItemResponse, error)
+	MoveMenuItem(ctx context.Context, in *MoveMenuItemRequest, opts ...grpc.CallOption) (*MoveMenuItemResponse, error)
+	SetMenuItemModifiers(ctx context.Context, in *SetMenuItemModifiersRequest, opts ...grpc.CallOption) (*SetMenuItemModifiersResponse, error)
+	SetMenuItemTags(ctx context.Context, in *SetMenuItemTagsRequest, opts ...grpc.CallOption) (*SetMenuItemTagsResponse, error)
+	ListMenuTags(ctx context.Context, in *ListMenuTagsRequest, opts ...grpc.CallOption) (*ListMenuTagsResponse, error)
+	SetMenuItemAvailability(ctx context.Context, in *SetMenuItemAvailabilityRequest, opts ...grpc.CallOption) (*SetMenuItemAvailabilityResponse, error)
+	SetMenuItemSoldOut(ctx context.Context, in *SetMenuItemSoldOutRequest, opts ...grpc.CallOption) (*SetMenuItemSoldOutResponse, error)
+	ImportMenu(ctx context.Context, opts ...grpc.CallOption) (ReservationService_ImportMenuClient, error)
+	ExportMenu(ctx context.Context, in *ExportMenuRequest, opts ...grpc.CallOption) (*ExportMenuResponse, error)
+	GetMenuItemPriceHistory(ctx context.Context, in *GetMenuItemPriceHistoryRequest, opts ...grpc.CallOption) (*GetMenuItemPriceHistoryResponse, error)
+	CancelMenuPriceChange(ctx context.Context, in *CancelMenuPriceChangeRequest, opts ...grpc.CallOption) (*CancelMenuPriceChangeResponse, error)
+	CreateMenuCategory(ctx context.Context, in *CreateMenuCategoryRequest, opts ...grpc.CallOption) (*CreateMenuCategoryResponse, error)
+	ListMenuCategories(ctx context.Context, in *ListMenuCategoriesRequest, opts ...grpc.CallOption) (*ListMenuCategoriesResponse, error)
+	UpdateMenuCategory(ctx context.Context, in *UpdateMenuCategoryRequest, opts ...grpc.CallOption) (*UpdateMenuCategoryResponse, error)
+	DeleteMenuCategory(ctx context.Context, in *DeleteMenuCategoryRequest, opts ...grpc.CallOption) (*DeleteMenuCategoryResponse, error)
+	GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error)
+	UpdateNotificationPreferences(ctx context.Context, in *UpdateNotificationPreferencesRequest, opts ...grpc.CallOption) (*UpdateNotificationPreferencesResponse, error)
+	PurgeDeleted(ctx context.Context, in *PurgeDeletedRequest, opts ...grpc.CallOption) (*PurgeDeletedResponse, error)
 }
 
 type reservationServiceClient struct {
@@ -95,6 +142,78 @@ func (c *reservationServiceClient) DeleteRestaurant(ctx context.Context, in *Del
 	return out, nil
 }
 
+func (c *reservationServiceClient) RestoreRestaurant(ctx context.Context, in *RestoreRestaurantRequest, opts ...grpc.CallOption) (*RestoreRestaurantResponse, error) {
+	out := new(RestoreRestaurantResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/RestoreRestaurant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) DeactivateRestaurant(ctx context.Context, in *DeactivateRestaurantRequest, opts ...grpc.CallOption) (*DeactivateRestaurantResponse, error) {
+	out := new(DeactivateRestaurantResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/DeactivateRestaurant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ActivateRestaurant(ctx context.Context, in *ActivateRestaurantRequest, opts ...grpc.CallOption) (*ActivateRestaurantResponse, error) {
+	out := new(ActivateRestaurantResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ActivateRestaurant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListNearbyRestaurants(ctx context.Context, in *ListNearbyRestaurantsRequest, opts ...grpc.CallOption) (*ListNearbyRestaurantsResponse, error) {
+	out := new(ListNearbyRestaurantsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListNearbyRestaurants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetOpeningHours(ctx context.Context, in *SetOpeningHoursRequest, opts ...grpc.CallOption) (*SetOpeningHoursResponse, error) {
+	out := new(SetOpeningHoursResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetOpeningHours", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetRestaurantProfile(ctx context.Context, in *SetRestaurantProfileRequest, opts ...grpc.CallOption) (*SetRestaurantProfileResponse, error) {
+	out := new(SetRestaurantProfileResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetRestaurantProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListRestaurantTags(ctx context.Context, in *ListRestaurantTagsRequest, opts ...grpc.CallOption) (*ListRestaurantTagsResponse, error) {
+	out := new(ListRestaurantTagsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListRestaurantTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *reservationServiceClient) CreateReservation(ctx context.Context, in *CreateReservationRequest, opts ...grpc.CallOption) (*CreateReservationResponse, error) {
 	out := new(CreateReservationResponse)
 	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CreateReservation", in, out, opts...)
@@ -140,6 +259,24 @@ func (c *reservationServiceClient) DeleteReservation(ctx context.Context, in *De
 	return out, nil
 }
 
+func (c *reservationServiceClient) RestoreReservation(ctx context.Context, in *RestoreReservationRequest, opts ...grpc.CallOption) (*RestoreReservationResponse, error) {
+	out := new(RestoreReservationResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/RestoreReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) CancelReservation(ctx context.Context, in *CancelReservationRequest, opts ...grpc.CallOption) (*CancelReservationResponse, error) {
+	out := new(CancelReservationResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CancelReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *reservationServiceClient) CheckReservation(ctx context.Context, in *CheckReservationRequest, opts ...grpc.CallOption) (*CheckReservationResponse, error) {
 	out := new(CheckReservationResponse)
 	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CheckReservation", in, out, opts...)
@@ -158,6 +295,24 @@ func (c *reservationServiceClient) OrderMeals(ctx context.Context, in *OrderMeal
 	return out, nil
 }
 
+func (c *reservationServiceClient) GetBill(ctx context.Context, in *GetBillRequest, opts ...grpc.CallOption) (*GetBillResponse, error) {
+	out := new(GetBillResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetBill", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error) {
+	out := new(ApplyPromoCodeResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ApplyPromoCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *reservationServiceClient) PayReservation(ctx context.Context, in *MakePaymentRequest, opts ...grpc.CallOption) (*MakePaymentResponse, error) {
 	out := new(MakePaymentResponse)
 	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/PayReservation", in, out, opts...)
@@ -167,6 +322,159 @@ func (c *reservationServiceClient) PayReservation(ctx context.Context, in *MakeP
 	return out, nil
 }
 
+func (c *reservationServiceClient) PayDeposit(ctx context.Context, in *PayDepositRequest, opts ...grpc.CallOption) (*PayDepositResponse, error) {
+	out := new(PayDepositResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/PayDeposit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) RefundReservation(ctx context.Context, in *RefundReservationRequest, opts ...grpc.CallOption) (*RefundReservationResponse, error) {
+	out := new(RefundReservationResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/RefundReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListRefunds(ctx context.Context, in *ListRefundsRequest, opts ...grpc.CallOption) (*ListRefundsResponse, error) {
+	out := new(ListRefundsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListRefunds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SeatReservation(ctx context.Context, in *SeatReservationRequest, opts ...grpc.CallOption) (*SeatReservationResponse, error) {
+	out := new(SeatReservationResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SeatReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetRestaurantStaff(ctx context.Context, in *SetRestaurantStaffRequest, opts ...grpc.CallOption) (*SetRestaurantStaffResponse, error) {
+	out := new(SetRestaurantStaffResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetRestaurantStaff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListRestaurantStaff(ctx context.Context, in *ListRestaurantStaffRequest, opts ...grpc.CallOption) (*ListRestaurantStaffResponse, error) {
+	out := new(ListRestaurantStaffResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListRestaurantStaff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetBillingSettings(ctx context.Context, in *GetBillingSettingsRequest, opts ...grpc.CallOption) (*GetBillingSettingsResponse, error) {
+	out := new(GetBillingSettingsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetBillingSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetBillingSettings(ctx context.Context, in *SetBillingSettingsRequest, opts ...grpc.CallOption) (*SetBillingSettingsResponse, error) {
+	out := new(SetBillingSettingsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetBillingSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetPromoCode(ctx context.Context, in *SetPromoCodeRequest, opts ...grpc.CallOption) (*SetPromoCodeResponse, error) {
+	out := new(SetPromoCodeResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetPromoCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (*ListPromoCodesResponse, error) {
+	out := new(ListPromoCodesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListPromoCodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListDepositRules(ctx context.Context, in *ListDepositRulesRequest, opts ...grpc.CallOption) (*ListDepositRulesResponse, error) {
+	out := new(ListDepositRulesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListDepositRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetDepositRules(ctx context.Context, in *SetDepositRulesRequest, opts ...grpc.CallOption) (*SetDepositRulesResponse, error) {
+	out := new(SetDepositRulesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetDepositRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetCancellationPolicy(ctx context.Context, in *GetCancellationPolicyRequest, opts ...grpc.CallOption) (*GetCancellationPolicyResponse, error) {
+	out := new(GetCancellationPolicyResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetCancellationPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetCancellationPolicy(ctx context.Context, in *SetCancellationPolicyRequest, opts ...grpc.CallOption) (*SetCancellationPolicyResponse, error) {
+	out := new(SetCancellationPolicyResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetCancellationPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetNoShowPolicy(ctx context.Context, in *GetNoShowPolicyRequest, opts ...grpc.CallOption) (*GetNoShowPolicyResponse, error) {
+	out := new(GetNoShowPolicyResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetNoShowPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) SetNoShowPolicy(ctx context.Context, in *SetNoShowPolicyRequest, opts ...grpc.CallOption) (*SetNoShowPolicyResponse, error) {
+	out := new(SetNoShowPolicyResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetNoShowPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetGuestNoShows(ctx context.Context, in *GetGuestNoShowsRequest, opts ...grpc.CallOption) (*GetGuestNoShowsResponse, error) {
+	out := new(GetGuestNoShowsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetGuestNoShows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *reservationServiceClient) CreateMenuItem(ctx context.Context, in *CreateMenuItemRequest, opts ...grpc.CallOption) (*CreateMenuItemResponse, error) {
 	out := new(CreateMenuItemResponse)
 	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CreateMenuItem", in, out, opts...)
@@ -212,74 +520,395 @@ func (c *reservationServiceClient) DeleteMenuItem(ctx context.Context, in *Delet
 	return out, nil
 }
 
-// ReservationServiceServer is the server API for ReservationService service.
-// All implementations must embed UnimplementedReservationServiceServer
-// for forward compatibility
-type ReservationServiceServer interface {
-	CreateRestaurant(context.Context, *CreateRestaurantRequest) (*CreateRestaurantResponse, error)
-	ListRestaurants(context.Context, *ListRestaurantsRequest) (*ListRestaurantsResponse, error)
-	GetRestaurant(context.Context, *GetRestaurantRequest) (*GetRestaurantResponse, error)
-	UpdateRestaurant(context.Context, *UpdateRestaurantRequest) (*UpdateRestaurantResponse, error)
-	DeleteRestaurant(context.Context, *DeleteRestaurantRequest) (*DeleteRestaurantResponse, error)
-	CreateReservation(context.Context, *CreateReservationRequest) (*CreateReservationResponse, error)
-	ListReservations(context.Context, *ListReservationsRequest) (*ListReservationsResponse, error)
-	GetReservation(context.Context, *GetReservationRequest) (*GetReservationResponse, error)
-	UpdateReservation(context.Context, *UpdateReservationRequest) (*UpdateReservationResponse, error)
-	DeleteReservation(context.Context, *DeleteReservationRequest) (*DeleteReservationResponse, error)
-	CheckReservation(context.Context, *CheckReservationRequest) (*CheckReservationResponse, error)
-	OrderMeals(context.Context, *OrderMealsRequest) (*OrderMealsResponse, error)
-	PayReservation(context.Context, *MakePaymentRequest) (*MakePaymentResponse, error)
-	CreateMenuItem(context.Context, *CreateMenuItemRequest) (*CreateMenuItemResponse, error)
-	ListMenuItems(context.Context, *ListMenuItemsRequest) (*ListMenuItemsResponse, error)
-	GetMenuItem(context.Context, *GetMenuItemRequest) (*GetMenuItemResponse, error)
-	UpdateMenuItem(context.Context, *UpdateMenuItemRequest) (*UpdateMenuItemResponse, error)
-	DeleteMenuItem(context.Context, *DeleteMenuItemRequest) (*DeleteMenuItemResponse, error)
-	mustEmbedUnimplementedReservationServiceServer()
+func (c *reservationServiceClient) RestoreMenuItem(ctx context.Context, in *RestoreMenuItemRequest, opts ...grpc.CallOption) (*RestoreMenuItemResponse, error) {
+	out := new(RestoreMenuItemResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/RestoreMenuItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedReservationServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedReservationServiceServer struct {
+func (c *reservationServiceClient) MoveMenuItem(ctx context.Context, in *MoveMenuItemRequest, opts ...grpc.CallOption) (*MoveMenuItemResponse, error) {
+	out := new(MoveMenuItemResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/MoveMenuItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (UnimplementedReservationServiceServer) CreateRestaurant(context.Context, *CreateRestaurantRequest) (*CreateRestaurantResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateRestaurant not implemented")
+func (c *reservationServiceClient) SetMenuItemModifiers(ctx context.Context, in *SetMenuItemModifiersRequest, opts ...grpc.CallOption) (*SetMenuItemModifiersResponse, error) {
+	out := new(SetMenuItemModifiersResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetMenuItemModifiers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedReservationServiceServer) ListRestaurants(context.Context, *ListRestaurantsRequest) (*ListRestaurantsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListRestaurants not implemented")
+
+func (c *reservationServiceClient) SetMenuItemTags(ctx context.Context, in *SetMenuItemTagsRequest, opts ...grpc.CallOption) (*SetMenuItemTagsResponse, error) {
+	out := new(SetMenuItemTagsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetMenuItemTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedReservationServiceServer) GetRestaurant(context.Context, *GetRestaurantRequest) (*GetRestaurantResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRestaurant not implemented")
+
+func (c *reservationServiceClient) ListMenuTags(ctx context.Context, in *ListMenuTagsRequest, opts ...grpc.CallOption) (*ListMenuTagsResponse, error) {
+	out := new(ListMenuTagsResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListMenuTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedReservationServiceServer) UpdateRestaurant(context.Context, *UpdateRestaurantRequest) (*UpdateRestaurantResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateRestaurant not implemented")
+
+func (c *reservationServiceClient) SetMenuItemAvailability(ctx context.Context, in *SetMenuItemAvailabilityRequest, opts ...grpc.CallOption) (*SetMenuItemAvailabilityResponse, error) {
+	out := new(SetMenuItemAvailabilityResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetMenuItemAvailability", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedReservationServiceServer) DeleteRestaurant(context.Context, *DeleteRestaurantRequest) (*DeleteRestaurantResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteRestaurant not implemented")
+
+func (c *reservationServiceClient) SetMenuItemSoldOut(ctx context.Context, in *SetMenuItemSoldOutRequest, opts ...grpc.CallOption) (*SetMenuItemSoldOutResponse, error) {
+	out := new(SetMenuItemSoldOutResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/SetMenuItemSoldOut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedReservationServiceServer) CreateReservation(context.Context, *CreateReservationRequest) (*CreateReservationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateReservation not implemented")
+
+func (c *reservationServiceClient) ImportMenu(ctx context.Context, opts ...grpc.CallOption) (ReservationService_ImportMenuClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ReservationService_ServiceDesc.Streams[0], "/reservation_service.ReservationService/ImportMenu", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &reservationServiceImportMenuClient{stream}
+	return x, nil
 }
-func (UnimplementedReservationServiceServer) ListReservations(context.Context, *ListReservationsRequest) (*ListReservationsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListReservations not implemented")
+
+type ReservationService_ImportMenuClient interface {
+	Send(*ImportMenuRequest) error
+	CloseAndRecv() (*ImportMenuResponse, error)
+	grpc.ClientStream
 }
-func (UnimplementedReservationServiceServer) GetReservation(context.Context, *GetReservationRequest) (*GetReservationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetReservation not implemented")
+
+type reservationServiceImportMenuClient struct {
+	grpc.ClientStream
 }
-func (UnimplementedReservationServiceServer) UpdateReservation(context.Context, *UpdateReservationRequest) (*UpdateReservationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateReservation not implemented")
+
+func (x *reservationServiceImportMenuClient) Send(m *ImportMenuRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *reservationServiceImportMenuClient) CloseAndRecv() (*ImportMenuResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportMenuResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *reservationServiceClient) ExportMenu(ctx context.Context, in *ExportMenuRequest, opts ...grpc.CallOption) (*ExportMenuResponse, error) {
+	out := new(ExportMenuResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ExportMenu", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetMenuItemPriceHistory(ctx context.Context, in *GetMenuItemPriceHistoryRequest, opts ...grpc.CallOption) (*GetMenuItemPriceHistoryResponse, error) {
+	out := new(GetMenuItemPriceHistoryResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetMenuItemPriceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) CancelMenuPriceChange(ctx context.Context, in *CancelMenuPriceChangeRequest, opts ...grpc.CallOption) (*CancelMenuPriceChangeResponse, error) {
+	out := new(CancelMenuPriceChangeResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CancelMenuPriceChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) CreateMenuCategory(ctx context.Context, in *CreateMenuCategoryRequest, opts ...grpc.CallOption) (*CreateMenuCategoryResponse, error) {
+	out := new(CreateMenuCategoryResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/CreateMenuCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) ListMenuCategories(ctx context.Context, in *ListMenuCategoriesRequest, opts ...grpc.CallOption) (*ListMenuCategoriesResponse, error) {
+	out := new(ListMenuCategoriesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/ListMenuCategories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) UpdateMenuCategory(ctx context.Context, in *UpdateMenuCategoryRequest, opts ...grpc.CallOption) (*UpdateMenuCategoryResponse, error) {
+	out := new(UpdateMenuCategoryResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/UpdateMenuCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) DeleteMenuCategory(ctx context.Context, in *DeleteMenuCategoryRequest, opts ...grpc.CallOption) (*DeleteMenuCategoryResponse, error) {
+	out := new(DeleteMenuCategoryResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/DeleteMenuCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) GetNotificationPreferences(ctx context.Context, in *GetNotificationPreferencesRequest, opts ...grpc.CallOption) (*GetNotificationPreferencesResponse, error) {
+	out := new(GetNotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) UpdateNotificationPreferences(ctx context.Context, in *UpdateNotificationPreferencesRequest, opts ...grpc.CallOption) (*UpdateNotificationPreferencesResponse, error) {
+	out := new(UpdateNotificationPreferencesResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/UpdateNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationServiceClient) PurgeDeleted(ctx context.Context, in *PurgeDeletedRequest, opts ...grpc.CallOption) (*PurgeDeletedResponse, error) {
+	out := new(PurgeDeletedResponse)
+	err := c.cc.Invoke(ctx, "/reservation_service.ReservationService/PurgeDeleted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReservationServiceServer is the server API for ReservationService service.
+// All implementations must embed UnimplementedReservationServiceServer
+// for forward compatibility
+type ReservationServiceServer interface {
+	CreateRestaurant(context.Context, *CreateRestaurantRequest) (*CreateRestaurantResponse, error)
+	ListRestaurants(context.Context, *ListRestaurantsRequest) (*ListRestaurantsResponse, error)
+	GetRestaurant(context.Context, *GetRestaurantRequest) (*GetRestaurantResponse, error)
+	UpdateRestaurant(context.Context, *UpdateRestaurantRequest) (*UpdateRestaurantResponse, error)
+	DeleteRestaurant(context.Context, *DeleteRestaurantRequest) (*DeleteRestaurantResponse, error)
+	RestoreRestaurant(context.Context, *RestoreRestaurantRequest) (*RestoreRestaurantResponse, error)
+	DeactivateRestaurant(context.Context, *DeactivateRestaurantRequest) (*DeactivateRestaurantResponse, error)
+	ActivateRestaurant(context.Context, *ActivateRestaurantRequest) (*ActivateRestaurantResponse, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	ListNearbyRestaurants(context.Context, *ListNearbyRestaurantsRequest) (*ListNearbyRestaurantsResponse, error)
+	SetOpeningHours(context.Context, *SetOpeningHoursRequest) (*SetOpeningHoursResponse, error)
+	SetRestaurantProfile(context.Context, *SetRestaurantProfileRequest) (*SetRestaurantProfileResponse, error)
+	ListRestaurantTags(context.Context, *ListRestaurantTagsRequest) (*ListRestaurantTagsResponse, error)
+	CreateReservation(context.Context, *CreateReservationRequest) (*CreateReservationResponse, error)
+	ListReservations(context.Context, *ListReservationsRequest) (*ListReservationsResponse, error)
+	GetReservation(context.Context, *GetReservationRequest) (*GetReservationResponse, error)
+	UpdateReservation(context.Context, *UpdateReservationRequest) (*UpdateReservationResponse, error)
+	DeleteReservation(context.Context, *DeleteReservationRequest) (*DeleteReservationResponse, error)
+	RestoreReservation(context.Context, *RestoreReservationRequest) (*RestoreReservationResponse, error)
+	CancelReservation(context.Context, *CancelReservationRequest) (*CancelReservationResponse, error)
+	CheckReservation(context.Context, *CheckReservationRequest) (*CheckReservationResponse, error)
+	OrderMeals(context.Context, *OrderMealsRequest) (*OrderMealsResponse, error)
+	GetBill(context.Context, *GetBillRequest) (*GetBillResponse, error)
+	ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error)
+	PayReservation(context.Context, *MakePaymentRequest) (*MakePaymentResponse, error)
+	PayDeposit(context.Context, *PayDepositRequest) (*PayDepositResponse, error)
+	RefundReservation(context.Context, *RefundReservationRequest) (*RefundReservationResponse, error)
+	ListRefunds(context.Context, *ListRefundsRequest) (*ListRefundsResponse, error)
+	SeatReservation(context.Context, *SeatReservationRequest) (*SeatReservationResponse, error)
+	SetRestaurantStaff(context.Context, *SetRestaurantStaffRequest) (*SetRestaurantStaffResponse, error)
+	ListRestaurantStaff(context.Context, *ListRestaurantStaffRequest) (*ListRestaurantStaffResponse, error)
+	GetBillingSettings(context.Context, *GetBillingSettingsRequest) (*GetBillingSettingsResponse, error)
+	SetBillingSettings(context.Context, *SetBillingSettingsRequest) (*SetBillingSettingsResponse, error)
+	SetPromoCode(context.Context, *SetPromoCodeRequest) (*SetPromoCodeResponse, error)
+	ListPromoCodes(context.Context, *ListPromoCodesRequest) (*ListPromoCodesResponse, error)
+	ListDepositRules(context.Context, *ListDepositRulesRequest) (*ListDepositRulesResponse, error)
+	SetDepositRules(context.Context, *SetDepositRulesRequest) (*SetDepositRulesResponse, error)
+	GetCancellationPolicy(context.Context, *GetCancellationPolicyRequest) (*GetCancellationPolicyResponse, error)
+	SetCancellationPolicy(context.Context, *SetCancellationPolicyRequest) (*SetCancellationPolicyResponse, error)
+	GetNoShowPolicy(context.Context, *GetNoShowPolicyRequest) (*GetNoShowPolicyResponse, error)
+	SetNoShowPolicy(context.Context, *SetNoShowPolicyRequest) (*SetNoShowPolicyResponse, error)
+	GetGuestNoShows(context.Context, *GetGuestNoShowsRequest) (*GetGuestNoShowsResponse, error)
+	CreateMenuItem(context.Context, *CreateMenuItemRequest) (*CreateMenuItemResponse, error)
+	ListMenuItems(context.Context, *ListMenuItemsRequest) (*ListMenuItemsResponse, error)
+	GetMenuItem(context.Context, *GetMenuItemRequest) (*GetMenuItemResponse, error)
+	UpdateMenuItem(context.Context, *UpdateMenuItemRequest) (*UpdateMenuItemResponse, error)
+	DeleteMenuItem(context.Context, *DeleteMenuItemRequest) (*DeleteMenuItemResponse, error)
+	RestoreMenuItem(context.Context, *RestoreMenuItemRequest) (*RestoreMenuItemResponse, error)
+	MoveMenuItem(context.Context, *MoveMenuItemRequest) (*MoveMenuItemResponse, error)
+	SetMenuItemModifiers(context.Context, *SetMenuItemModifiersRequest) (*SetMenuItemModifiersResponse, error)
+	SetMenuItemTags(context.Context, *SetMenuItemTagsRequest) (*SetMenuItemTagsResponse, error)
+	ListMenuTags(context.Context, *ListMenuTagsRequest) (*ListMenuTagsResponse, error)
+	SetMenuItemAvailability(context.Context, *SetMenuItemAvailabilityRequest) (*SetMenuItemAvailabilityResponse, error)
+	SetMenuItemSoldOut(context.Context, *SetMenuItemSoldOutRequest) (*SetMenuItemSoldOutResponse, error)
+	ImportMenu(ReservationService_ImportMenuServer) error
+	ExportMenu(context.Context, *ExportMenuRequest) (*ExportMenuResponse, error)
+	GetMenuItemPriceHistory(context.Context, *GetMenuItemPriceHistoryRequest) (*GetMenuItemPriceHistoryResponse, error)
+	CancelMenuPriceChange(context.Context, *CancelMenuPriceChangeRequest) (*CancelMenuPriceChangeResponse, error)
+	CreateMenuCategory(context.Context, *CreateMenuCategoryRequest) (*CreateMenuCategoryResponse, error)
+	ListMenuCategories(context.Context, *ListMenuCategoriesRequest) (*ListMenuCategoriesResponse, error)
+	UpdateMenuCategory(context.Context, *UpdateMenuCategoryRequest) (*UpdateMenuCategoryResponse, error)
+	DeleteMenuCategory(context.Context, *DeleteMenuCategoryRequest) (*DeleteMenuCategoryResponse, error)
+	GetNotificationPreferences(context.Context, *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error)
+	UpdateNotificationPreferences(context.Context, *UpdateNotificationPreferencesRequest) (*UpdateNotificationPreferencesResponse, error)
+	PurgeDeleted(context.Context, *PurgeDeletedRequest) (*PurgeDeletedResponse, error)
+	mustEmbedUnimplementedReservationServiceServer()
+}
+
+// UnimplementedReservationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedReservationServiceServer struct {
+}
+
+func (UnimplementedReservationServiceServer) CreateRestaurant(context.Context, *CreateRestaurantRequest) (*CreateRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) ListRestaurants(context.Context, *ListRestaurantsRequest) (*ListRestaurantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRestaurants not implemented")
+}
+func (UnimplementedReservationServiceServer) GetRestaurant(context.Context, *GetRestaurantRequest) (*GetRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) UpdateRestaurant(context.Context, *UpdateRestaurantRequest) (*UpdateRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) DeleteRestaurant(context.Context, *DeleteRestaurantRequest) (*DeleteRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) RestoreRestaurant(context.Context, *RestoreRestaurantRequest) (*RestoreRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) DeactivateRestaurant(context.Context, *DeactivateRestaurantRequest) (*DeactivateRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) ActivateRestaurant(context.Context, *ActivateRestaurantRequest) (*ActivateRestaurantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateRestaurant not implemented")
+}
+func (UnimplementedReservationServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedReservationServiceServer) ListNearbyRestaurants(context.Context, *ListNearbyRestaurantsRequest) (*ListNearbyRestaurantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNearbyRestaurants not implemented")
+}
+func (UnimplementedReservationServiceServer) SetOpeningHours(context.Context, *SetOpeningHoursRequest) (*SetOpeningHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOpeningHours not implemented")
+}
+func (UnimplementedReservationServiceServer) SetRestaurantProfile(context.Context, *SetRestaurantProfileRequest) (*SetRestaurantProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRestaurantProfile not implemented")
+}
+func (UnimplementedReservationServiceServer) ListRestaurantTags(context.Context, *ListRestaurantTagsRequest) (*ListRestaurantTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRestaurantTags not implemented")
+}
+func (UnimplementedReservationServiceServer) CreateReservation(context.Context, *CreateReservationRequest) (*CreateReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReservation not implemented")
+}
+func (UnimplementedReservationServiceServer) ListReservations(context.Context, *ListReservationsRequest) (*ListReservationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReservations not implemented")
+}
+func (UnimplementedReservationServiceServer) GetReservation(context.Context, *GetReservationRequest) (*GetReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReservation not implemented")
+}
+func (UnimplementedReservationServiceServer) UpdateReservation(context.Context, *UpdateReservationRequest) (*UpdateReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReservation not implemented")
 }
 func (UnimplementedReservationServiceServer) DeleteReservation(context.Context, *DeleteReservationRequest) (*DeleteReservationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteReservation not implemented")
 }
+func (UnimplementedReservationServiceServer) RestoreReservation(context.Context, *RestoreReservationRequest) (*RestoreReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreReservation not implemented")
+}
+func (UnimplementedReservationServiceServer) CancelReservation(context.Context, *CancelReservationRequest) (*CancelReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelReservation not implemented")
+}
 func (UnimplementedReservationServiceServer) CheckReservation(context.Context, *CheckReservationRequest) (*CheckReservationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckReservation not implemented")
 }
 func (UnimplementedReservationServiceServer) OrderMeals(context.Context, *OrderMealsRequest) (*OrderMealsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OrderMeals not implemented")
 }
+func (UnimplementedReservationServiceServer) GetBill(context.Context, *GetBillRequest) (*GetBillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBill not implemented")
+}
+func (UnimplementedReservationServiceServer) ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyPromoCode not implemented")
+}
 func (UnimplementedReservationServiceServer) PayReservation(context.Context, *MakePaymentRequest) (*MakePaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PayReservation not implemented")
 }
+func (UnimplementedReservationServiceServer) PayDeposit(context.Context, *PayDepositRequest) (*PayDepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayDeposit not implemented")
+}
+func (UnimplementedReservationServiceServer) RefundReservation(context.Context, *RefundReservationRequest) (*RefundReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundReservation not implemented")
+}
+func (UnimplementedReservationServiceServer) ListRefunds(context.Context, *ListRefundsRequest) (*ListRefundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRefunds not implemented")
+}
+func (UnimplementedReservationServiceServer) SeatReservation(context.Context, *SeatReservationRequest) (*SeatReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SeatReservation not implemented")
+}
+func (UnimplementedReservationServiceServer) SetRestaurantStaff(context.Context, *SetRestaurantStaffRequest) (*SetRestaurantStaffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRestaurantStaff not implemented")
+}
+func (UnimplementedReservationServiceServer) ListRestaurantStaff(context.Context, *ListRestaurantStaffRequest) (*ListRestaurantStaffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRestaurantStaff not implemented")
+}
+func (UnimplementedReservationServiceServer) GetBillingSettings(context.Context, *GetBillingSettingsRequest) (*GetBillingSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBillingSettings not implemented")
+}
+func (UnimplementedReservationServiceServer) SetBillingSettings(context.Context, *SetBillingSettingsRequest) (*SetBillingSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBillingSettings not implemented")
+}
+func (UnimplementedReservationServiceServer) SetPromoCode(context.Context, *SetPromoCodeRequest) (*SetPromoCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPromoCode not implemented")
+}
+func (UnimplementedReservationServiceServer) ListPromoCodes(context.Context, *ListPromoCodesRequest) (*ListPromoCodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPromoCodes not implemented")
+}
+func (UnimplementedReservationServiceServer) ListDepositRules(context.Context, *ListDepositRulesRequest) (*ListDepositRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDepositRules not implemented")
+}
+func (UnimplementedReservationServiceServer) SetDepositRules(context.Context, *SetDepositRulesRequest) (*SetDepositRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDepositRules not implemented")
+}
+func (UnimplementedReservationServiceServer) GetCancellationPolicy(context.Context, *GetCancellationPolicyRequest) (*GetCancellationPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCancellationPolicy not implemented")
+}
+func (UnimplementedReservationServiceServer) SetCancellationPolicy(context.Context, *SetCancellationPolicyRequest) (*SetCancellationPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCancellationPolicy not implemented")
+}
+func (UnimplementedReservationServiceServer) GetNoShowPolicy(context.Context, *GetNoShowPolicyRequest) (*GetNoShowPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNoShowPolicy not implemented")
+}
+func (UnimplementedReservationServiceServer) SetNoShowPolicy(context.Context, *SetNoShowPolicyRequest) (*SetNoShowPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNoShowPolicy not implemented")
+}
+func (UnimplementedReservationServiceServer) GetGuestNoShows(context.Context, *GetGuestNoShowsRequest) (*GetGuestNoShowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGuestNoShows not implemented")
+}
 func (UnimplementedReservationServiceServer) CreateMenuItem(context.Context, *CreateMenuItemRequest) (*CreateMenuItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMenuItem not implemented")
 }
@@ -295,339 +924,1247 @@ func (UnimplementedReservationServiceServer) UpdateMenuItem(context.Context, *Up
 func (UnimplementedReservationServiceServer) DeleteMenuItem(context.Context, *DeleteMenuItemRequest) (*DeleteMenuItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteMenuItem not implemented")
 }
-func (UnimplementedReservationServiceServer) mustEmbedUnimplementedReservationServiceServer() {}
+func (UnimplementedReservationServiceServer) RestoreMenuItem(context.Context, *RestoreMenuItemRequest) (*RestoreMenuItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMenuItem not implemented")
+}
+func (UnimplementedReservationServiceServer) MoveMenuItem(context.Context, *MoveMenuItemRequest) (*MoveMenuItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveMenuItem not implemented")
+}
+func (UnimplementedReservationServiceServer) SetMenuItemModifiers(context.Context, *SetMenuItemModifiersRequest) (*SetMenuItemModifiersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemModifiers not implemented")
+}
+func (UnimplementedReservationServiceServer) SetMenuItemTags(context.Context, *SetMenuItemTagsRequest) (*SetMenuItemTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemTags not implemented")
+}
+func (UnimplementedReservationServiceServer) ListMenuTags(context.Context, *ListMenuTagsRequest) (*ListMenuTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMenuTags not implemented")
+}
+func (UnimplementedReservationServiceServer) SetMenuItemAvailability(context.Context, *SetMenuItemAvailabilityRequest) (*SetMenuItemAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemAvailability not implemented")
+}
+func (UnimplementedReservationServiceServer) SetMenuItemSoldOut(context.Context, *SetMenuItemSoldOutRequest) (*SetMenuItemSoldOutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMenuItemSoldOut not implemented")
+}
+func (UnimplementedReservationServiceServer) ImportMenu(ReservationService_ImportMenuServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportMenu not implemented")
+}
+func (UnimplementedReservationServiceServer) ExportMenu(context.Context, *ExportMenuRequest) (*ExportMenuResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMenu not implemented")
+}
+func (UnimplementedReservationServiceServer) GetMenuItemPriceHistory(context.Context, *GetMenuItemPriceHistoryRequest) (*GetMenuItemPriceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMenuItemPriceHistory not implemented")
+}
+func (UnimplementedReservationServiceServer) CancelMenuPriceChange(context.Context, *CancelMenuPriceChangeRequest) (*CancelMenuPriceChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelMenuPriceChange not implemented")
+}
+func (UnimplementedReservationServiceServer) CreateMenuCategory(context.Context, *CreateMenuCategoryRequest) (*CreateMenuCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMenuCategory not implemented")
+}
+func (UnimplementedReservationServiceServer) ListMenuCategories(context.Context, *ListMenuCategoriesRequest) (*ListMenuCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMenuCategories not implemented")
+}
+func (UnimplementedReservationServiceServer) UpdateMenuCategory(context.Context, *UpdateMenuCategoryRequest) (*UpdateMenuCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMenuCategory not implemented")
+}
+func (UnimplementedReservationServiceServer) DeleteMenuCategory(context.Context, *DeleteMenuCategoryRequest) (*DeleteMenuCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMenuCategory not implemented")
+}
+func (UnimplementedReservationServiceServer) GetNotificationPreferences(context.Context, *GetNotificationPreferencesRequest) (*GetNotificationPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedReservationServiceServer) UpdateNotificationPreferences(context.Context, *UpdateNotificationPreferencesRequest) (*UpdateNotificationPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationPreferences not implemented")
+}
+func (UnimplementedReservationServiceServer) PurgeDeleted(context.Context, *PurgeDeletedRequest) (*PurgeDeletedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeleted not implemented")
+}
+func (UnimplementedReservationServiceServer) mustEmbedUnimplementedReservationServiceServer() {}
+
+// UnsafeReservationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReservationServiceServer will
+// result in compilation errors.
+type UnsafeReservationServiceServer interface {
+	mustEmbedUnimplementedReservationServiceServer()
+}
+
+func RegisterReservationServiceServer(s grpc.ServiceRegistrar, srv ReservationServiceServer) {
+	s.RegisterService(&ReservationService_ServiceDesc, srv)
+}
+
+func _ReservationService_CreateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).CreateRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/CreateRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).CreateRestaurant(ctx, req.(*CreateRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListRestaurants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRestaurantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListRestaurants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListRestaurants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListRestaurants(ctx, req.(*ListRestaurantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetRestaurant(ctx, req.(*GetRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_UpdateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).UpdateRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/UpdateRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).UpdateRestaurant(ctx, req.(*UpdateRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_DeleteRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).DeleteRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/DeleteRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).DeleteRestaurant(ctx, req.(*DeleteRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_RestoreRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).RestoreRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/RestoreRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).RestoreRestaurant(ctx, req.(*RestoreRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_DeactivateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).DeactivateRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/DeactivateRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).DeactivateRestaurant(ctx, req.(*DeactivateRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ActivateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateRestaurantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ActivateRestaurant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ActivateRestaurant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ActivateRestaurant(ctx, req.(*ActivateRestaurantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListNearbyRestaurants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNearbyRestaurantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListNearbyRestaurants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListNearbyRestaurants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListNearbyRestaurants(ctx, req.(*ListNearbyRestaurantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetOpeningHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOpeningHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetOpeningHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetOpeningHours",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetOpeningHours(ctx, req.(*SetOpeningHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetRestaurantProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRestaurantProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetRestaurantProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetRestaurantProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetRestaurantProfile(ctx, req.(*SetRestaurantProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListRestaurantTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRestaurantTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListRestaurantTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListRestaurantTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListRestaurantTags(ctx, req.(*ListRestaurantTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_CreateReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).CreateReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/CreateReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).CreateReservation(ctx, req.(*CreateReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReservationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListReservations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListReservations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListReservations(ctx, req.(*ListReservationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetReservation(ctx, req.(*GetReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_UpdateReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).UpdateReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/UpdateReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).UpdateReservation(ctx, req.(*UpdateReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_DeleteReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).DeleteReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/DeleteReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).DeleteReservation(ctx, req.(*DeleteReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_RestoreReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).RestoreReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/RestoreReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).RestoreReservation(ctx, req.(*RestoreReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_CancelReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).CancelReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/CancelReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).CancelReservation(ctx, req.(*CancelReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_CheckReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).CheckReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/CheckReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).CheckReservation(ctx, req.(*CheckReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_OrderMeals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderMealsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).OrderMeals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/OrderMeals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).OrderMeals(ctx, req.(*OrderMealsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetBill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetBill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetBill",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetBill(ctx, req.(*GetBillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ApplyPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ApplyPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ApplyPromoCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ApplyPromoCode(ctx, req.(*ApplyPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_PayReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).PayReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/PayReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).PayReservation(ctx, req.(*MakePaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_PayDeposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayDepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).PayDeposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/PayDeposit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).PayDeposit(ctx, req.(*PayDepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_RefundReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).RefundReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/RefundReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).RefundReservation(ctx, req.(*RefundReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListRefunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRefundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListRefunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListRefunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListRefunds(ctx, req.(*ListRefundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SeatReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeatReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SeatReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SeatReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SeatReservation(ctx, req.(*SeatReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetRestaurantStaff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRestaurantStaffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetRestaurantStaff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetRestaurantStaff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetRestaurantStaff(ctx, req.(*SetRestaurantStaffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListRestaurantStaff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRestaurantStaffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListRestaurantStaff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListRestaurantStaff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListRestaurantStaff(ctx, req.(*ListRestaurantStaffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetBillingSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBillingSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetBillingSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetBillingSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetBillingSettings(ctx, req.(*GetBillingSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetBillingSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBillingSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetBillingSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetBillingSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetBillingSettings(ctx, req.(*SetBillingSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetPromoCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetPromoCode(ctx, req.(*SetPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListPromoCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPromoCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListPromoCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListPromoCodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListPromoCodes(ctx, req.(*ListPromoCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListDepositRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDepositRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListDepositRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListDepositRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListDepositRules(ctx, req.(*ListDepositRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetDepositRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDepositRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetDepositRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetDepositRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetDepositRules(ctx, req.(*SetDepositRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetCancellationPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCancellationPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetCancellationPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetCancellationPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetCancellationPolicy(ctx, req.(*GetCancellationPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetCancellationPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCancellationPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetCancellationPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetCancellationPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetCancellationPolicy(ctx, req.(*SetCancellationPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetNoShowPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoShowPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetNoShowPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetNoShowPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetNoShowPolicy(ctx, req.(*GetNoShowPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_SetNoShowPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNoShowPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).SetNoShowPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/SetNoShowPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).SetNoShowPolicy(ctx, req.(*SetNoShowPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_GetGuestNoShows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGuestNoShowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetGuestNoShows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetGuestNoShows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetGuestNoShows(ctx, req.(*GetGuestNoShowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_CreateMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).CreateMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/CreateMenuItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).CreateMenuItem(ctx, req.(*CreateMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReservationService_ListMenuItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMenuItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).ListMenuItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/ListMenuItems",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).ListMenuItems(ctx, req.(*ListMenuItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// UnsafeReservationServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ReservationServiceServer will
-// result in compilation errors.
-type UnsafeReservationServiceServer interface {
-	mustEmbedUnimplementedReservationServiceServer()
+func _ReservationService_GetMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).GetMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/GetMenuItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).GetMenuItem(ctx, req.(*GetMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterReservationServiceServer(s grpc.ServiceRegistrar, srv ReservationServiceServer) {
-	s.RegisterService(&ReservationService_ServiceDesc, srv)
+func _ReservationService_UpdateMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationServiceServer).UpdateMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reservation_service.ReservationService/UpdateMenuItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationServiceServer).UpdateMenuItem(ctx, req.(*UpdateMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_CreateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateRestaurantRequest)
+func _ReservationService_DeleteMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMenuItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).CreateRestaurant(ctx, in)
+		return srv.(ReservationServiceServer).DeleteMenuItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/CreateRestaurant",
+		FullMethod: "/reservation_service.ReservationService/DeleteMenuItem",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).CreateRestaurant(ctx, req.(*CreateRestaurantRequest))
+		return srv.(ReservationServiceServer).DeleteMenuItem(ctx, req.(*DeleteMenuItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_ListRestaurants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRestaurantsRequest)
+func _ReservationService_RestoreMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMenuItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).ListRestaurants(ctx, in)
+		return srv.(ReservationServiceServer).RestoreMenuItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/ListRestaurants",
+		FullMethod: "/reservation_service.ReservationService/RestoreMenuItem",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).ListRestaurants(ctx, req.(*ListRestaurantsRequest))
+		return srv.(ReservationServiceServer).RestoreMenuItem(ctx, req.(*RestoreMenuItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_GetRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRestaurantRequest)
+func _ReservationService_MoveMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveMenuItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).GetRestaurant(ctx, in)
+		return srv.(ReservationServiceServer).MoveMenuItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/GetRestaurant",
+		FullMethod: "/reservation_service.ReservationService/MoveMenuItem",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).GetRestaurant(ctx, req.(*GetRestaurantRequest))
+		return srv.(ReservationServiceServer).MoveMenuItem(ctx, req.(*MoveMenuItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_UpdateRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateRestaurantRequest)
+func _ReservationService_SetMenuItemModifiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemModifiersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).UpdateRestaurant(ctx, in)
+		return srv.(ReservationServiceServer).SetMenuItemModifiers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/UpdateRestaurant",
+		FullMethod: "/reservation_service.ReservationService/SetMenuItemModifiers",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).UpdateRestaurant(ctx, req.(*UpdateRestaurantRequest))
+		return srv.(ReservationServiceServer).SetMenuItemModifiers(ctx, req.(*SetMenuItemModifiersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_DeleteRestaurant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRestaurantRequest)
+func _ReservationService_SetMenuItemTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemTagsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).DeleteRestaurant(ctx, in)
+		return srv.(ReservationServiceServer).SetMenuItemTags(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/DeleteRestaurant",
+		FullMethod: "/reservation_service.ReservationService/SetMenuItemTags",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).DeleteRestaurant(ctx, req.(*DeleteRestaurantRequest))
+		return srv.(ReservationServiceServer).SetMenuItemTags(ctx, req.(*SetMenuItemTagsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_CreateReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateReservationRequest)
+func _ReservationService_ListMenuTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMenuTagsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).CreateReservation(ctx, in)
+		return srv.(ReservationServiceServer).ListMenuTags(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/CreateReservation",
+		FullMethod: "/reservation_service.ReservationService/ListMenuTags",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).CreateReservation(ctx, req.(*CreateReservationRequest))
+		return srv.(ReservationServiceServer).ListMenuTags(ctx, req.(*ListMenuTagsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_ListReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListReservationsRequest)
+func _ReservationService_SetMenuItemAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemAvailabilityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).ListReservations(ctx, in)
+		return srv.(ReservationServiceServer).SetMenuItemAvailability(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/ListReservations",
+		FullMethod: "/reservation_service.ReservationService/SetMenuItemAvailability",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).ListReservations(ctx, req.(*ListReservationsRequest))
+		return srv.(ReservationServiceServer).SetMenuItemAvailability(ctx, req.(*SetMenuItemAvailabilityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_GetReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetReservationRequest)
+func _ReservationService_SetMenuItemSoldOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMenuItemSoldOutRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).GetReservation(ctx, in)
+		return srv.(ReservationServiceServer).SetMenuItemSoldOut(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/GetReservation",
+		FullMethod: "/reservation_service.ReservationService/SetMenuItemSoldOut",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).GetReservation(ctx, req.(*GetReservationRequest))
+		return srv.(ReservationServiceServer).SetMenuItemSoldOut(ctx, req.(*SetMenuItemSoldOutRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_UpdateReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateReservationRequest)
+func _ReservationService_ImportMenu_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ReservationServiceServer).ImportMenu(&reservationServiceImportMenuServer{stream})
+}
+
+type ReservationService_ImportMenuServer interface {
+	SendAndClose(*ImportMenuResponse) error
+	Recv() (*ImportMenuRequest, error)
+	grpc.ServerStream
+}
+
+type reservationServiceImportMenuServer struct {
+	grpc.ServerStream
+}
+
+func (x *reservationServiceImportMenuServer) SendAndClose(m *ImportMenuResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *reservationServiceImportMenuServer) Recv() (*ImportMenuRequest, error) {
+	m := new(ImportMenuRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ReservationService_ExportMenu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMenuRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).UpdateReservation(ctx, in)
+		return srv.(ReservationServiceServer).ExportMenu(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/UpdateReservation",
+		FullMethod: "/reservation_service.ReservationService/ExportMenu",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).UpdateReservation(ctx, req.(*UpdateReservationRequest))
+		return srv.(ReservationServiceServer).ExportMenu(ctx, req.(*ExportMenuRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_DeleteReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteReservationRequest)
+func _ReservationService_GetMenuItemPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMenuItemPriceHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).DeleteReservation(ctx, in)
+		return srv.(ReservationServiceServer).GetMenuItemPriceHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/DeleteReservation",
+		FullMethod: "/reservation_service.ReservationService/GetMenuItemPriceHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).DeleteReservation(ctx, req.(*DeleteReservationRequest))
+		return srv.(ReservationServiceServer).GetMenuItemPriceHistory(ctx, req.(*GetMenuItemPriceHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_CheckReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckReservationRequest)
+func _ReservationService_CancelMenuPriceChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMenuPriceChangeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).CheckReservation(ctx, in)
+		return srv.(ReservationServiceServer).CancelMenuPriceChange(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/CheckReservation",
+		FullMethod: "/reservation_service.ReservationService/CancelMenuPriceChange",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).CheckReservation(ctx, req.(*CheckReservationRequest))
+		return srv.(ReservationServiceServer).CancelMenuPriceChange(ctx, req.(*CancelMenuPriceChangeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_OrderMeals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OrderMealsRequest)
+func _ReservationService_CreateMenuCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMenuCategoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).OrderMeals(ctx, in)
+		return srv.(ReservationServiceServer).CreateMenuCategory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/OrderMeals",
+		FullMethod: "/reservation_service.ReservationService/CreateMenuCategory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).OrderMeals(ctx, req.(*OrderMealsRequest))
+		return srv.(ReservationServiceServer).CreateMenuCategory(ctx, req.(*CreateMenuCategoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_PayReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MakePaymentRequest)
+func _ReservationService_ListMenuCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMenuCategoriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).PayReservation(ctx, in)
+		return srv.(ReservationServiceServer).ListMenuCategories(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/PayReservation",
+		FullMethod: "/reservation_service.ReservationService/ListMenuCategories",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).PayReservation(ctx, req.(*MakePaymentRequest))
+		return srv.(ReservationServiceServer).ListMenuCategories(ctx, req.(*ListMenuCategoriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_CreateMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateMenuItemRequest)
+func _ReservationService_UpdateMenuCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMenuCategoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).CreateMenuItem(ctx, in)
+		return srv.(ReservationServiceServer).UpdateMenuCategory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/CreateMenuItem",
+		FullMethod: "/reservation_service.ReservationService/UpdateMenuCategory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).CreateMenuItem(ctx, req.(*CreateMenuItemRequest))
+		return srv.(ReservationServiceServer).UpdateMenuCategory(ctx, req.(*UpdateMenuCategoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_ListMenuItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListMenuItemsRequest)
+func _ReservationService_DeleteMenuCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMenuCategoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).ListMenuItems(ctx, in)
+		return srv.(ReservationServiceServer).DeleteMenuCategory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/ListMenuItems",
+		FullMethod: "/reservation_service.ReservationService/DeleteMenuCategory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).ListMenuItems(ctx, req.(*ListMenuItemsRequest))
+		return srv.(ReservationServiceServer).DeleteMenuCategory(ctx, req.(*DeleteMenuCategoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_GetMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMenuItemRequest)
+func _ReservationService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationPreferencesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).GetMenuItem(ctx, in)
+		return srv.(ReservationServiceServer).GetNotificationPreferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/GetMenuItem",
+		FullMethod: "/reservation_service.ReservationService/GetNotificationPreferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).GetMenuItem(ctx, req.(*GetMenuItemRequest))
+		return srv.(ReservationServiceServer).GetNotificationPreferences(ctx, req.(*GetNotificationPreferencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_UpdateMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateMenuItemRequest)
+func _ReservationService_UpdateNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNotificationPreferencesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).UpdateMenuItem(ctx, in)
+		return srv.(ReservationServiceServer).UpdateNotificationPreferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/UpdateMenuItem",
+		FullMethod: "/reservation_service.ReservationService/UpdateNotificationPreferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).UpdateMenuItem(ctx, req.(*UpdateMenuItemRequest))
+		return srv.(ReservationServiceServer).UpdateNotificationPreferences(ctx, req.(*UpdateNotificationPreferencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReservationService_DeleteMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMenuItemRequest)
+func _ReservationService_PurgeDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeletedRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReservationServiceServer).DeleteMenuItem(ctx, in)
+		return srv.(ReservationServiceServer).PurgeDeleted(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/reservation_service.ReservationService/DeleteMenuItem",
+		FullMethod: "/reservation_service.ReservationService/PurgeDeleted",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReservationServiceServer).DeleteMenuItem(ctx, req.(*DeleteMenuItemRequest))
+		return srv.(ReservationServiceServer).PurgeDeleted(ctx, req.(*PurgeDeletedRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -659,6 +2196,38 @@ var ReservationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteRestaurant",
 			Handler:    _ReservationService_DeleteRestaurant_Handler,
 		},
+		{
+			MethodName: "RestoreRestaurant",
+			Handler:    _ReservationService_RestoreRestaurant_Handler,
+		},
+		{
+			MethodName: "DeactivateRestaurant",
+			Handler:    _ReservationService_DeactivateRestaurant_Handler,
+		},
+		{
+			MethodName: "ActivateRestaurant",
+			Handler:    _ReservationService_ActivateRestaurant_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _ReservationService_Search_Handler,
+		},
+		{
+			MethodName: "ListNearbyRestaurants",
+			Handler:    _ReservationService_ListNearbyRestaurants_Handler,
+		},
+		{
+			MethodName: "SetOpeningHours",
+			Handler:    _ReservationService_SetOpeningHours_Handler,
+		},
+		{
+			MethodName: "SetRestaurantProfile",
+			Handler:    _ReservationService_SetRestaurantProfile_Handler,
+		},
+		{
+			MethodName: "ListRestaurantTags",
+			Handler:    _ReservationService_ListRestaurantTags_Handler,
+		},
 		{
 			MethodName: "CreateReservation",
 			Handler:    _ReservationService_CreateReservation_Handler,
@@ -679,6 +2248,14 @@ var ReservationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteReservation",
 			Handler:    _ReservationService_DeleteReservation_Handler,
 		},
+		{
+			MethodName: "RestoreReservation",
+			Handler:    _ReservationService_RestoreReservation_Handler,
+		},
+		{
+			MethodName: "CancelReservation",
+			Handler:    _ReservationService_CancelReservation_Handler,
+		},
 		{
 			MethodName: "CheckReservation",
 			Handler:    _ReservationService_CheckReservation_Handler,
@@ -687,10 +2264,86 @@ var ReservationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "OrderMeals",
 			Handler:    _ReservationService_OrderMeals_Handler,
 		},
+		{
+			MethodName: "GetBill",
+			Handler:    _ReservationService_GetBill_Handler,
+		},
+		{
+			MethodName: "ApplyPromoCode",
+			Handler:    _ReservationService_ApplyPromoCode_Handler,
+		},
 		{
 			MethodName: "PayReservation",
 			Handler:    _ReservationService_PayReservation_Handler,
 		},
+		{
+			MethodName: "PayDeposit",
+			Handler:    _ReservationService_PayDeposit_Handler,
+		},
+		{
+			MethodName: "RefundReservation",
+			Handler:    _ReservationService_RefundReservation_Handler,
+		},
+		{
+			MethodName: "ListRefunds",
+			Handler:    _ReservationService_ListRefunds_Handler,
+		},
+		{
+			MethodName: "SeatReservation",
+			Handler:    _ReservationService_SeatReservation_Handler,
+		},
+		{
+			MethodName: "SetRestaurantStaff",
+			Handler:    _ReservationService_SetRestaurantStaff_Handler,
+		},
+		{
+			MethodName: "ListRestaurantStaff",
+			Handler:    _ReservationService_ListRestaurantStaff_Handler,
+		},
+		{
+			MethodName: "GetBillingSettings",
+			Handler:    _ReservationService_GetBillingSettings_Handler,
+		},
+		{
+			MethodName: "SetBillingSettings",
+			Handler:    _ReservationService_SetBillingSettings_Handler,
+		},
+		{
+			MethodName: "SetPromoCode",
+			Handler:    _ReservationService_SetPromoCode_Handler,
+		},
+		{
+			MethodName: "ListPromoCodes",
+			Handler:    _ReservationService_ListPromoCodes_Handler,
+		},
+		{
+			MethodName: "ListDepositRules",
+			Handler:    _ReservationService_ListDepositRules_Handler,
+		},
+		{
+			MethodName: "SetDepositRules",
+			Handler:    _ReservationService_SetDepositRules_Handler,
+		},
+		{
+			MethodName: "GetCancellationPolicy",
+			Handler:    _ReservationService_GetCancellationPolicy_Handler,
+		},
+		{
+			MethodName: "SetCancellationPolicy",
+			Handler:    _ReservationService_SetCancellationPolicy_Handler,
+		},
+		{
+			MethodName: "GetNoShowPolicy",
+			Handler:    _ReservationService_GetNoShowPolicy_Handler,
+		},
+		{
+			MethodName: "SetNoShowPolicy",
+			Handler:    _ReservationService_SetNoShowPolicy_Handler,
+		},
+		{
+			MethodName: "GetGuestNoShows",
+			Handler:    _ReservationService_GetGuestNoShows_Handler,
+		},
 		{
 			MethodName: "CreateMenuItem",
 			Handler:    _ReservationService_CreateMenuItem_Handler,
@@ -711,7 +2364,81 @@ var ReservationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteMenuItem",
 			Handler:    _ReservationService_DeleteMenuItem_Handler,
 		},
+		{
+			MethodName: "RestoreMenuItem",
+			Handler:    _ReservationService_RestoreMenuItem_Handler,
+		},
+		{
+			MethodName: "MoveMenuItem",
+			Handler:    _ReservationService_MoveMenuItem_Handler,
+		},
+		{
+			MethodName: "SetMenuItemModifiers",
+			Handler:    _ReservationService_SetMenuItemModifiers_Handler,
+		},
+		{
+			MethodName: "SetMenuItemTags",
+			Handler:    _ReservationService_SetMenuItemTags_Handler,
+		},
+		{
+			MethodName: "ListMenuTags",
+			Handler:    _ReservationService_ListMenuTags_Handler,
+		},
+		{
+			MethodName: "SetMenuItemAvailability",
+			Handler:    _ReservationService_SetMenuItemAvailability_Handler,
+		},
+		{
+			MethodName: "SetMenuItemSoldOut",
+			Handler:    _ReservationService_SetMenuItemSoldOut_Handler,
+		},
+		{
+			MethodName: "ExportMenu",
+			Handler:    _ReservationService_ExportMenu_Handler,
+		},
+		{
+			MethodName: "GetMenuItemPriceHistory",
+			Handler:    _ReservationService_GetMenuItemPriceHistory_Handler,
+		},
+		{
+			MethodName: "CancelMenuPriceChange",
+			Handler:    _ReservationService_CancelMenuPriceChange_Handler,
+		},
+		{
+			MethodName: "CreateMenuCategory",
+			Handler:    _ReservationService_CreateMenuCategory_Handler,
+		},
+		{
+			MethodName: "ListMenuCategories",
+			Handler:    _ReservationService_ListMenuCategories_Handler,
+		},
+		{
+			MethodName: "UpdateMenuCategory",
+			Handler:    _ReservationService_UpdateMenuCategory_Handler,
+		},
+		{
+			MethodName: "DeleteMenuCategory",
+			Handler:    _ReservationService_DeleteMenuCategory_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _ReservationService_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "UpdateNotificationPreferences",
+			Handler:    _ReservationService_UpdateNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "PurgeDeleted",
+			Handler:    _ReservationService_PurgeDeleted_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportMenu",
+			Handler:       _ReservationService_ImportMenu_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "reservation_service.proto",
 }
@@ -0,0 +1,65 @@
+// Package geo works with restaurant locations: distances between coordinates
+// and geocoding of addresses.
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// EarthRadius is the mean radius of the Earth in meters.
+const EarthRadius = 6371000
+
+// ErrNotFound is returned by geocoders that cannot locate an address.
+var ErrNotFound = errors.New("address not found")
+
+// Point is a location in degrees.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Valid reports whether the point lies within the ranges of latitude and
+// longitude.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// Distance returns the great-circle distance between a and b in meters,
+// using the haversine formula.
+func Distance(a, b Point) float64 {
+	dLat := radians(b.Lat - a.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// BoundingBox returns the south-west and north-east corners of a box holding
+// every point within radius meters of center. Near the poles the box spans
+// every longitude. It does not wrap around the antimeridian; a box crossing
+// it is clamped to [-180, 180].
+func BoundingBox(center Point, radius float64) (Point, Point) {
+	dLat := degrees(radius / EarthRadius)
+	sw := Point{Lat: max(center.Lat-dLat, -90), Lng: -180}
+	ne := Point{Lat: min(center.Lat+dLat, 90), Lng: 180}
+	if sw.Lat > -90 && ne.Lat < 90 {
+		dLng := degrees(math.Asin(min(math.Sin(radius/EarthRadius)/math.Cos(radians(center.Lat)), 1)))
+		sw.Lng = max(center.Lng-dLng, -180)
+		ne.Lng = min(center.Lng+dLng, 180)
+	}
+	return sw, ne
+}
+
+// Geocoder finds the location of a postal address.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (Point, error)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
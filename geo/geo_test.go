@@ -0,0 +1,56 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tashkent  = Point{Lat: 41.2995, Lng: 69.2401}
+	samarkand = Point{Lat: 39.6542, Lng: 66.9597}
+)
+
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 265000, Distance(tashkent, samarkand), 5000)
+	assert.InDelta(t, Distance(tashkent, samarkand), Distance(samarkand, tashkent), 1e-6)
+	assert.Zero(t, Distance(tashkent, tashkent))
+}
+
+func TestBoundingBox(t *testing.T) {
+	sw, ne := BoundingBox(tashkent, 10000)
+	assert.Less(t, sw.Lat, tashkent.Lat)
+	assert.Greater(t, ne.Lat, tashkent.Lat)
+	for _, corner := range []Point{{sw.Lat, tashkent.Lng}, {ne.Lat, tashkent.Lng}, {tashkent.Lat, sw.Lng}, {tashkent.Lat, ne.Lng}} {
+		assert.InDelta(t, 10000, Distance(tashkent, corner), 50)
+	}
+
+	sw, ne = BoundingBox(Point{Lat: 89.99, Lng: 10}, 10000)
+	assert.Equal(t, -180.0, sw.Lng)
+	assert.Equal(t, 180.0, ne.Lng)
+	assert.Equal(t, 90.0, ne.Lat)
+}
+
+func TestNominatim(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		if r.URL.Query().Get("q") == "Amir Temur 1, Tashkent" {
+			w.Write([]byte(`[{"lat": "41.2995", "lon": "69.2401", "display_name": "Tashkent"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	geocoder := Nominatim{BaseURL: server.URL + "/", UserAgent: "test-agent"}
+	point, err := geocoder.Geocode(context.Background(), "Amir Temur 1, Tashkent")
+	assert.NoError(t, err)
+	assert.Equal(t, tashkent, point)
+
+	_, err = geocoder.Geocode(context.Background(), "Nowhere")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
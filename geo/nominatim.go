@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reservation-service/config"
+	"strconv"
+	"strings"
+)
+
+// Nominatim geocodes addresses with the search API of an OpenStreetMap
+// Nominatim server. Its usage policy asks for a UserAgent naming the
+// application.
+type Nominatim struct {
+	BaseURL   string
+	UserAgent string
+	Client    *http.Client
+}
+
+type nominatimPlace struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func (n Nominatim) Geocode(ctx context.Context, address string) (Point, error) {
+	query := url.Values{"q": {address}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(n.BaseURL, "/")+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("User-Agent", n.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return Point{}, fmt.Errorf("failed to geocode address: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("failed to geocode address: %s", res.Status)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(res.Body).Decode(&places); err != nil {
+		return Point{}, fmt.Errorf("failed to decode geocoding response: %v", err)
+	}
+	if len(places) == 0 {
+		return Point{}, ErrNotFound
+	}
+	lat, latErr := strconv.ParseFloat(places[0].Lat, 64)
+	lng, lngErr := strconv.ParseFloat(places[0].Lon, 64)
+	point := Point{Lat: lat, Lng: lng}
+	if latErr != nil || lngErr != nil || !point.Valid() {
+		return Point{}, fmt.Errorf("invalid geocoding result %q, %q", places[0].Lat, places[0].Lon)
+	}
+	return point, nil
+}
+
+// NewGeocoder builds the geocoder named by cfg.Provider: none (nil) or
+// nominatim.
+func NewGeocoder(cfg config.GeocoderConfig) (Geocoder, error) {
+	switch cfg.Provider {
+	case "none":
+		return nil, nil
+	case "nominatim":
+		return Nominatim{BaseURL: cfg.URL, UserAgent: cfg.UserAgent, Client: &http.Client{Timeout: cfg.Timeout}}, nil
+	}
+	return nil, fmt.Errorf("unknown geocoder %q", cfg.Provider)
+}
@@ -0,0 +1,100 @@
+// Package locale picks the language to answer a request in from the locales
+// the caller asked for and those a text is translated into.
+package locale
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the gRPC metadata key carrying the caller's locales, in the
+// format of the HTTP Accept-Language header, such as "uz, ru;q=0.8".
+const MetadataKey = "accept-language"
+
+var languageTag = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Normalize returns s in the form locales are stored in, such as "en-gb"
+// for "en_GB", and reports whether it is a valid language tag.
+func Normalize(s string) (string, bool) {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "_", "-"))
+	return s, languageTag.MatchString(s)
+}
+
+// FromContext returns the locales asked for in the incoming metadata, most
+// preferred first. Invalid entries and "*" are skipped.
+func FromContext(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	return Parse(strings.Join(md.Get(MetadataKey), ","))
+}
+
+// Parse reads an Accept-Language list, ordering it by quality and keeping
+// the listed order between equal qualities.
+func Parse(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var list []weighted
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		locale, ok := Normalize(name)
+		if !ok || q <= 0 {
+			continue
+		}
+		list = append(list, weighted{locale, q})
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+
+	locales := make([]string, len(list))
+	for i, w := range list {
+		locales[i] = w.locale
+	}
+	return locales
+}
+
+// Match returns the available locale to use for the preferred ones. Each
+// preferred locale is tried in turn: first exactly, then without its
+// trailing subtags ("uz-latn-uz", "uz-latn", "uz"), then as any locale of
+// the same language. It reports false when nothing matches, in which case
+// the untranslated text is used.
+func Match(preferred, available []string) (string, bool) {
+	has := make(map[string]bool, len(available))
+	for _, locale := range available {
+		has[locale] = true
+	}
+	for _, want := range preferred {
+		for candidate := want; ; {
+			if has[candidate] {
+				return candidate, true
+			}
+			i := strings.LastIndexByte(candidate, '-')
+			if i < 0 {
+				break
+			}
+			candidate = candidate[:i]
+		}
+		language, _, _ := strings.Cut(want, "-")
+		for _, locale := range available {
+			if strings.HasPrefix(locale, language+"-") {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,52 @@
+package locale
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNormalize(t *testing.T) {
+	locale, ok := Normalize(" en_GB ")
+	assert.True(t, ok)
+	assert.Equal(t, "en-gb", locale)
+	_, ok = Normalize("english")
+	assert.False(t, ok)
+	_, ok = Normalize("")
+	assert.False(t, ok)
+}
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, []string{"uz", "ru", "en"}, Parse("en;q=0.5, uz, ru;q=0.8"))
+	assert.Equal(t, []string{"ru-ru", "ru"}, Parse("ru-RU, ru, *;q=0.1, de;q=0, x;q=0.9"))
+	assert.Empty(t, Parse(""))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "ru;q=0.5", MetadataKey, "uz"))
+	assert.Equal(t, []string{"uz", "ru"}, FromContext(ctx))
+}
+
+func TestMatch(t *testing.T) {
+	available := []string{"en", "ru", "uz-cyrl"}
+	for _, tt := range []struct {
+		preferred []string
+		want      string
+		ok        bool
+	}{
+		{[]string{"ru"}, "ru", true},
+		{[]string{"ru-ru"}, "ru", true},
+		{[]string{"uz"}, "uz-cyrl", true},
+		{[]string{"uz-latn"}, "uz-cyrl", true},
+		{[]string{"de", "en-us"}, "en", true},
+		{[]string{"de"}, "", false},
+		{nil, "", false},
+	} {
+		got, ok := Match(tt.preferred, available)
+		assert.Equal(t, tt.want, got, "%v", tt.preferred)
+		assert.Equal(t, tt.ok, ok, "%v", tt.preferred)
+	}
+}
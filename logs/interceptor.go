@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key carrying the request id in both directions.
+const RequestIDKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestID returns the request id assigned by UnaryServerInterceptor.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryServerInterceptor logs one line per RPC with its method, duration,
+// status code, caller address, request id and trace id when traced. The
+// request id is taken from the incoming x-request-id header or generated, and
+// echoed back to the client.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		requestID := incomingRequestID(ctx)
+		ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID))
+
+		resp, err := handler(ctx, req)
+		logRPC(ctx, logger, info.FullMethod, start, requestID, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor does for streaming RPCs what UnaryServerInterceptor
+// does for unary ones, logging the line once the stream ends.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		requestID := incomingRequestID(ss.Context())
+		ctx := context.WithValue(ss.Context(), requestIDKey{}, requestID)
+		ss.SetHeader(metadata.Pairs(RequestIDKey, requestID))
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logRPC(ctx, logger, info.FullMethod, start, requestID, err)
+		return err
+	}
+}
+
+// serverStream carries the context holding the request id.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func logRPC(ctx context.Context, logger *slog.Logger, method string, start time.Time, requestID string, err error) {
+	code := status.Code(err)
+	attrs := []any{
+		"method", method,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"code", code.String(),
+		"caller", caller(ctx),
+		"request_id", requestID,
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		attrs = append(attrs, "trace_id", sc.TraceID().String())
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	logger.Log(ctx, levelFor(code), "rpc", attrs...)
+}
+
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDKey); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func caller(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
+
+// levelFor keeps client mistakes out of the error level.
+func levelFor(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
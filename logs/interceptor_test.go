@@ -0,0 +1,91 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	interceptor := UnaryServerInterceptor(logger)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/reservation_service.ReservationService/GetRestaurant"}
+
+	var seen string
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen = RequestID(ctx)
+		return nil, status.Error(codes.NotFound, "restaurant not found")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "req-1", seen)
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, info.FullMethod, line["method"])
+	assert.Equal(t, "NotFound", line["code"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "unknown", line["caller"])
+	assert.Contains(t, line, "duration_ms")
+}
+
+func TestUnaryServerInterceptorGeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := UnaryServerInterceptor(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	var seen string
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/m"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			seen = RequestID(ctx)
+			return "ok", nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, seen, 32)
+	assert.Contains(t, buf.String(), `"level":"INFO"`)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := StreamServerInterceptor(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	stream := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "req-2"))}
+	info := &grpc.StreamServerInfo{FullMethod: "/reservation_service.ReservationService/ImportMenu", IsClientStream: true}
+
+	var seen string
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		seen = RequestID(ss.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "req-2", seen)
+	assert.Equal(t, []string{"req-2"}, stream.header.Get(RequestIDKey))
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, info.FullMethod, line["method"])
+	assert.Equal(t, "OK", line["code"])
+}
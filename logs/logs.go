@@ -1,19 +1,44 @@
 package logs
 
 import (
-	"log"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"reservation-service/config"
+	"strings"
 )
 
-var Logger *slog.Logger
+// New builds the service logger described by cfg. The returned closer
+// releases the log file, if one was opened, and must be called on shutdown.
+func New(cfg config.LogConfig) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, nil, fmt.Errorf("invalid log level %q: %v", cfg.Level, err)
+	}
 
-func InitLogger(){
-	logFile,err := os.OpenFile("logs/app.log",os.O_CREATE|os.O_WRONLY|os.O_APPEND,0666)
-	if err != nil{
-		log.Fatalf("Failed to open log file : %v",err)
-	} 
+	var (
+		out    io.Writer
+		closer io.Closer = io.NopCloser(nil)
+	)
+	output := strings.ToLower(cfg.Output)
+	if output == "file" || output == "both" {
+		logFile, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file : %v", err)
+		}
+		out, closer = logFile, logFile
+	}
+	switch output {
+	case "stdout":
+		out = os.Stdout
+	case "both":
+		out = io.MultiWriter(os.Stdout, out)
+	case "file":
+	default:
+		return nil, nil, fmt.Errorf("invalid log output %q", cfg.Output)
+	}
 
-	handler := slog.NewJSONHandler(logFile,nil)
-	Logger = slog.New(handler)
-}
\ No newline at end of file
+	handler := slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level})
+	return slog.New(handler), closer, nil
+}
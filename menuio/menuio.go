@@ -0,0 +1,274 @@
+// Package menuio reads and writes restaurant menus as CSV or JSON files, in
+// the rows taken by ImportMenu and returned by ExportMenu.
+package menuio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+)
+
+// Supported formats.
+const (
+	CSV  = "csv"
+	JSON = "json"
+)
+
+// Columns are the CSV header and the keys of the JSON objects. Only name and
+// price are required when reading.
+var Columns = []string{"name", "description", "price", "currency", "category", "allergens", "dietary_tags", "daily_stock", "sold_out"}
+
+// listSeparator separates the tags of a CSV cell, as in "milk;eggs".
+const listSeparator = ";"
+
+// RowError is a row that could not be read. Row counts the menu's rows from
+// 1, leaving out the CSV header, as ImportMenu does.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// FormatOf picks the format from a file name's extension.
+func FormatOf(path string) (string, error) {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case CSV, JSON:
+		return ext, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %q, use a .csv or .json file", path)
+	}
+}
+
+// record is a row as written in a file. Prices are decimal amounts in major
+// units, such as 12.50.
+type record struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Price       json.Number `json:"price"`
+	Currency    string      `json:"currency,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Allergens   []string    `json:"allergens,omitempty"`
+	DietaryTags []string    `json:"dietary_tags,omitempty"`
+	DailyStock  int32       `json:"daily_stock,omitempty"`
+	SoldOut     bool        `json:"sold_out,omitempty"`
+}
+
+func (rec *record) row(defaultCurrency string) (*pb.MenuRow, error) {
+	if strings.TrimSpace(rec.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if rec.Price == "" {
+		return nil, errors.New("price is required")
+	}
+	currency := strings.ToUpper(strings.TrimSpace(rec.Currency))
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	if !money.ValidCurrency(currency) {
+		return nil, fmt.Errorf("invalid currency %q", rec.Currency)
+	}
+	minor, err := money.Parse(rec.Price.String(), currency)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MenuRow{
+		Name:        strings.TrimSpace(rec.Name),
+		Description: rec.Description,
+		Price:       &pb.Money{MinorUnits: minor, Currency: currency},
+		Category:    strings.TrimSpace(rec.Category),
+		Allergens:   rec.Allergens,
+		DietaryTags: rec.DietaryTags,
+		DailyStock:  rec.DailyStock,
+		SoldOut:     rec.SoldOut,
+	}, nil
+}
+
+func recordOf(row *pb.MenuRow) record {
+	return record{
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       json.Number(money.FormatAmount(row.Price.GetMinorUnits(), row.Price.GetCurrency())),
+		Currency:    row.Price.GetCurrency(),
+		Category:    row.Category,
+		Allergens:   row.Allergens,
+		DietaryTags: row.DietaryTags,
+		DailyStock:  row.DailyStock,
+		SoldOut:     row.SoldOut,
+	}
+}
+
+// Read reads a menu in the given format. Prices without a currency are in
+// defaultCurrency. Rows that cannot be read are all reported together, each
+// as a *RowError joined into the returned error.
+func Read(r io.Reader, format, defaultCurrency string) ([]*pb.MenuRow, error) {
+	var (
+		records  []record
+		cellErrs []error
+	)
+	switch format {
+	case CSV:
+		var err error
+		if records, cellErrs, err = readCSV(r); err != nil {
+			return nil, err
+		}
+	case JSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON menu: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	var (
+		rows []*pb.MenuRow
+		errs []error
+	)
+	for i := range records {
+		if i < len(cellErrs) && cellErrs[i] != nil {
+			errs = append(errs, &RowError{Row: i + 1, Err: cellErrs[i]})
+			continue
+		}
+		row, err := records[i].row(defaultCurrency)
+		if err != nil {
+			errs = append(errs, &RowError{Row: i + 1, Err: err})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// readCSV returns one record per data row, along with the errors of cells
+// that could not be read, indexed like the records.
+func readCSV(r io.Reader) ([]record, []error, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Columns, name) {
+			return nil, nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		index[name] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := index[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV column %q is missing", required)
+		}
+	}
+
+	var (
+		records []record
+		errs    []error
+	)
+	for {
+		cells, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		cell := func(name string) string {
+			if i, ok := index[name]; ok {
+				return strings.TrimSpace(cells[i])
+			}
+			return ""
+		}
+
+		rec := record{
+			Name:        cell("name"),
+			Description: cell("description"),
+			Price:       json.Number(cell("price")),
+			Currency:    cell("currency"),
+			Category:    cell("category"),
+			Allergens:   splitList(cell("allergens")),
+			DietaryTags: splitList(cell("dietary_tags")),
+		}
+		var rowErr error
+		if s := cell("daily_stock"); s != "" {
+			n, err := strconv.ParseInt(s, 10, 32)
+			if err != nil {
+				rowErr = fmt.Errorf("invalid daily_stock %q", s)
+			}
+			rec.DailyStock = int32(n)
+		}
+		if s := cell("sold_out"); s != "" {
+			if rec.SoldOut, err = strconv.ParseBool(s); err != nil {
+				rowErr = fmt.Errorf("invalid sold_out %q", s)
+			}
+		}
+		records = append(records, rec)
+		errs = append(errs, rowErr)
+	}
+	return records, errs, nil
+}
+
+// Write writes rows in the given format.
+func Write(w io.Writer, format string, rows []*pb.MenuRow) error {
+	switch format {
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(Columns); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			rec := recordOf(row)
+			stock := ""
+			if rec.DailyStock > 0 {
+				stock = strconv.Itoa(int(rec.DailyStock))
+			}
+			err := cw.Write([]string{
+				rec.Name, rec.Description, rec.Price.String(), rec.Currency, rec.Category,
+				strings.Join(rec.Allergens, listSeparator), strings.Join(rec.DietaryTags, listSeparator),
+				stock, strconv.FormatBool(rec.SoldOut),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case JSON:
+		records := make([]record, len(rows))
+		for i, row := range rows {
+			records[i] = recordOf(row)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func splitList(cell string) []string {
+	var list []string
+	for _, item := range strings.Split(cell, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
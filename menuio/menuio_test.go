@@ -0,0 +1,84 @@
+package menuio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReadCSV(t *testing.T) {
+	rows, err := Read(strings.NewReader(`name,price,category,allergens,daily_stock,sold_out
+Plov,12.50,Mains,,20,
+"Samsa, lamb",4,Starters,milk; cereals_gluten,,true
+`), CSV, "USD")
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "Plov", rows[0].Name)
+		assert.Equal(t, int64(1250), rows[0].Price.MinorUnits)
+		assert.Equal(t, "USD", rows[0].Price.Currency)
+		assert.Equal(t, int32(20), rows[0].DailyStock)
+		assert.Equal(t, "Samsa, lamb", rows[1].Name)
+		assert.Equal(t, []string{"milk", "cereals_gluten"}, rows[1].Allergens)
+		assert.True(t, rows[1].SoldOut)
+	}
+
+	_, err = Read(strings.NewReader("name,colour\nPlov,red\n"), CSV, "USD")
+	assert.ErrorContains(t, err, "unknown CSV column")
+	_, err = Read(strings.NewReader("name\nPlov\n"), CSV, "USD")
+	assert.ErrorContains(t, err, `"price" is missing`)
+}
+
+func TestReadReportsEveryRow(t *testing.T) {
+	_, err := Read(strings.NewReader(`name,price,daily_stock
+Plov,12.50,
+,3.00,
+Lagman,abc,
+Manti,9.999,
+Shashlik,15,many
+`), CSV, "USD")
+	var rowErr *RowError
+	if assert.ErrorAs(t, err, &rowErr) {
+		assert.Equal(t, 2, rowErr.Row)
+	}
+	for _, row := range []string{"row 2:", "row 3:", "row 4:", "row 5:"} {
+		assert.ErrorContains(t, err, row)
+	}
+	assert.NotContains(t, err.Error(), "row 1:")
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+}
+
+func TestRoundTrip(t *testing.T) {
+	rows := []*pb.MenuRow{
+		{Name: "Plov", Description: "Rice, lamb and carrots", Price: &pb.Money{MinorUnits: 1250, Currency: "USD"}, Category: "Mains", DailyStock: 20},
+		{Name: "Salad", Price: &pb.Money{MinorUnits: 700, Currency: "USD"}, DietaryTags: []string{"vegan", "vegetarian"}, SoldOut: true},
+	}
+	for _, format := range []string{CSV, JSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, Write(&buf, format, rows))
+			read, err := Read(&buf, format, "EUR")
+			assert.NoError(t, err)
+			if assert.Len(t, read, len(rows)) {
+				for i := range rows {
+					assert.True(t, proto.Equal(rows[i], read[i]), "%v != %v", rows[i], read[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	format, err := FormatOf("menu.CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, CSV, format)
+	format, err = FormatOf("/tmp/menu.json")
+	assert.NoError(t, err)
+	assert.Equal(t, JSON, format)
+	_, err = FormatOf("menu.xlsx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "reservation_service"
+
+// Metrics holds every collector exposed by the service. A nil *Metrics is
+// valid and records nothing, which keeps tests and tools free of setup.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+	redisCalls  *prometheus.HistogramVec
+
+	reservationsCreated   *prometheus.CounterVec
+	reservationsCancelled *prometheus.CounterVec
+	reservationsNoShow    *prometheus.CounterVec
+	mealsOrdered          prometheus.Counter
+	paymentsAttempted     prometheus.Counter
+	paymentsFailed        prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Latency of handled gRPC calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "gRPC calls that returned a non-OK status.",
+		}, []string{"method", "code"}),
+		redisCalls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "redis_command_duration_seconds",
+			Help:      "Latency of Redis commands.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command", "status"}),
+		reservationsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_created_total",
+			Help:      "Reservations created, per restaurant.",
+		}, []string{"restaurant_id"}),
+		reservationsCancelled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_cancelled_total",
+			Help:      "Reservations cancelled, per restaurant.",
+		}, []string{"restaurant_id"}),
+		reservationsNoShow: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_no_show_total",
+			Help:      "Reservations marked as no-show, per restaurant.",
+		}, []string{"restaurant_id"}),
+		mealsOrdered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "meals_ordered_total",
+			Help:      "Meal portions ordered with reservations.",
+		}),
+		paymentsAttempted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_attempted_total",
+			Help:      "Payments sent to the payment service.",
+		}),
+		paymentsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_failed_total",
+			Help:      "Payments rejected or not completed by the payment service.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rpcDuration, m.rpcErrors, m.redisCalls,
+		m.reservationsCreated, m.reservationsCancelled, m.reservationsNoShow,
+		m.mealsOrdered, m.paymentsAttempted, m.paymentsFailed,
+	)
+	return m
+}
+
+// RegisterDB exposes connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ReservationCreated(restaurantId string) {
+	if m != nil {
+		m.reservationsCreated.WithLabelValues(restaurantId).Inc()
+	}
+}
+
+func (m *Metrics) ReservationsCancelled(restaurantId string, n int) {
+	if m != nil && n > 0 {
+		m.reservationsCancelled.WithLabelValues(restaurantId).Add(float64(n))
+	}
+}
+
+func (m *Metrics) ReservationsNoShow(restaurantId string, n int) {
+	if m != nil && n > 0 {
+		m.reservationsNoShow.WithLabelValues(restaurantId).Add(float64(n))
+	}
+}
+
+func (m *Metrics) MealsOrdered(n int) {
+	if m != nil && n > 0 {
+		m.mealsOrdered.Add(float64(n))
+	}
+}
+
+// PaymentAttempted records one payment call and whether it failed.
+func (m *Metrics) PaymentAttempted(failed bool) {
+	if m == nil {
+		return
+	}
+	m.paymentsAttempted.Inc()
+	if failed {
+		m.paymentsFailed.Inc()
+	}
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNilMetricsIsNoop(t *testing.T) {
+	var m *Metrics
+	m.ReservationCreated("r1")
+	m.ReservationsCancelled("r1", 2)
+	m.MealsOrdered(3)
+	m.PaymentAttempted(true)
+}
+
+func TestHandlerExposesCounters(t *testing.T) {
+	m := New()
+	m.ReservationCreated("r1")
+	m.MealsOrdered(3)
+	m.PaymentAttempted(true)
+
+	interceptor := m.UnaryServerInterceptor()
+	interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Get"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	assert.Contains(t, string(body), `reservation_service_reservations_created_total{restaurant_id="r1"} 1`)
+	assert.Contains(t, string(body), `reservation_service_meals_ordered_total 3`)
+	assert.Contains(t, string(body), `reservation_service_payments_failed_total 1`)
+	assert.Contains(t, string(body), `reservation_service_rpc_errors_total{code="NotFound",method="/svc/Get"} 1`)
+}
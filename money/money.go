@@ -0,0 +1,105 @@
+// Package money works with exact amounts held as integer minor units of an
+// ISO 4217 currency, such as cents of USD.
+package money
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of restaurants created without one.
+const DefaultCurrency = "USD"
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// exponents lists currencies whose minor unit is not a hundredth.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// ValidCurrency reports whether code is a three-letter upper-case currency
+// code.
+func ValidCurrency(code string) bool {
+	return currencyCode.MatchString(code)
+}
+
+// Exponent returns the number of decimal digits of the currency's minor unit.
+func Exponent(currency string) int {
+	if e, ok := exponents[strings.ToUpper(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// FromMajor converts an amount in major units, as exchanged with the payment
+// service, to minor units, rounding half away from zero.
+func FromMajor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(Exponent(currency))))
+}
+
+// ToMajor converts minor units to an amount in major units.
+func ToMajor(minor int64, currency string) float64 {
+	return float64(minor) / math.Pow10(Exponent(currency))
+}
+
+// Percent returns percent of minor, rounded half away from zero to whole
+// minor units. The percentage is exact to a hundredth of a percent.
+func Percent(minor int64, percent float64) int64 {
+	basisPoints := int64(math.Round(percent * 100))
+	product := minor * basisPoints
+	if product < 0 {
+		return -((-product + 5000) / 10000)
+	}
+	return (product + 5000) / 10000
+}
+
+// Format renders minor units as a decimal amount followed by the currency,
+// for example "12.50 USD".
+func Format(minor int64, currency string) string {
+	return FormatAmount(minor, currency) + " " + currency
+}
+
+// FormatAmount renders minor units as a decimal amount without the currency,
+// for example "12.50".
+func FormatAmount(minor int64, currency string) string {
+	exp := Exponent(currency)
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, minor)
+	}
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/scale, exp, minor%scale)
+}
+
+// Parse converts a decimal amount in major units, such as "12.50", to minor
+// units of the currency. Amounts with more decimals than the currency's minor
+// unit are rejected rather than rounded.
+func Parse(amount, currency string) (int64, error) {
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || strings.Trim(whole, "0123456789") != "" || strings.Trim(frac, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	exp := Exponent(currency)
+	if len(frac) > exp {
+		return 0, fmt.Errorf("amount %q has more than %d decimals for %s", amount, exp, currency)
+	}
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
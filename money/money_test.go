@@ -0,0 +1,89 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMajor(t *testing.T) {
+	assert.Equal(t, int64(1999), FromMajor(19.99, "USD"))
+	// 0.1 + 0.2 is 0.30000000000000004 as a float.
+	assert.Equal(t, int64(30), FromMajor(0.1+0.2, "USD"))
+	assert.Equal(t, int64(1), FromMajor(0.005, "USD"))
+	assert.Equal(t, int64(-1), FromMajor(-0.005, "USD"))
+	assert.Equal(t, int64(1500), FromMajor(1500, "JPY"))
+	assert.Equal(t, int64(1250), FromMajor(1.25, "KWD"))
+}
+
+func TestToMajor(t *testing.T) {
+	assert.Equal(t, 19.99, ToMajor(1999, "USD"))
+	assert.Equal(t, 1500.0, ToMajor(1500, "JPY"))
+	assert.Equal(t, 1.25, ToMajor(1250, "KWD"))
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		percent  float64
+		expected int64
+	}{
+		{1000, 10, 100},
+		{1005, 10, 101},   // 100.5 rounds up
+		{1004, 10, 100},   // 100.4 rounds down
+		{-1005, 10, -101}, // half away from zero
+		{3097, 12, 372},   // 371.64
+		{250, 10.5, 26},   // 26.25
+		{999, 33.33, 333}, // 332.97
+		{0, 15, 0},
+		{1999, 100, 1999},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Percent(tt.minor, tt.percent), "%d%% of %d", int(tt.percent), tt.minor)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "12.50 USD", Format(1250, "USD"))
+	assert.Equal(t, "0.05 USD", Format(5, "USD"))
+	assert.Equal(t, "-3.07 EUR", Format(-307, "EUR"))
+	assert.Equal(t, "1500 JPY", Format(1500, "JPY"))
+	assert.Equal(t, "1.250 KWD", Format(1250, "KWD"))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		expected int64
+	}{
+		{"12.50", "USD", 1250},
+		{"12.5", "USD", 1250},
+		{" 12 ", "USD", 1200},
+		{"0.05", "USD", 5},
+		{"-3.07", "EUR", -307},
+		{"1500", "JPY", 1500},
+		{"1.25", "KWD", 1250},
+	}
+	for _, tt := range tests {
+		minor, err := Parse(tt.amount, tt.currency)
+		assert.NoError(t, err, tt.amount)
+		assert.Equal(t, tt.expected, minor, tt.amount)
+		back, err := Parse(FormatAmount(minor, tt.currency), tt.currency)
+		assert.NoError(t, err)
+		assert.Equal(t, minor, back)
+	}
+
+	for _, amount := range []string{"", "abc", "1,50", ".5", "12.505", "1e3"} {
+		_, err := Parse(amount, "USD")
+		assert.Error(t, err, amount)
+	}
+	_, err := Parse("15.5", "JPY")
+	assert.Error(t, err)
+}
+
+func TestValidCurrency(t *testing.T) {
+	assert.True(t, ValidCurrency("UZS"))
+	assert.False(t, ValidCurrency("usd"))
+	assert.False(t, ValidCurrency("US"))
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"os"
+	"reservation-service/config"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Channel delivers a message to a single address: an email address for the
+// email channel, a phone number for SMS.
+type Channel interface {
+	Send(ctx context.Context, to string, msg Message) error
+}
+
+// LogChannel writes messages to the logger instead of delivering them.
+type LogChannel struct {
+	Name   string
+	Logger *slog.Logger
+}
+
+func (c LogChannel) Send(ctx context.Context, to string, msg Message) error {
+	c.Logger.InfoContext(ctx, "Notification", "channel", c.Name, "to", to, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// FileChannel appends messages as JSON lines to a file. It is meant for
+// local development and tests.
+type FileChannel struct {
+	Name string
+	Path string
+
+	mu sync.Mutex
+}
+
+type fileRecord struct {
+	Channel string    `json:"channel"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+func (c *FileChannel) Send(ctx context.Context, to string, msg Message) error {
+	line, err := json.Marshal(fileRecord{Channel: c.Name, To: to, Subject: msg.Subject, Body: msg.Body, SentAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, err := os.OpenFile(c.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", c.Path, err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// SMTPChannel sends email through an SMTP server. Authentication is used
+// when Username is set.
+type SMTPChannel struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (c SMTPChannel) Send(ctx context.Context, to string, msg Message) error {
+	var auth smtp.Auth
+	if c.Username != "" {
+		host := c.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", c.Username, c.Password, host)
+	}
+
+	body := "From: " + headerValue(c.From) + "\r\n" +
+		"To: " + headerValue(to) + "\r\n" +
+		"Subject: " + headerValue(msg.Subject) + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + msg.Body + "\r\n"
+	return smtp.SendMail(c.Addr, auth, c.From, []string{to}, []byte(body))
+}
+
+// headerValue keeps user supplied values from adding their own headers.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(s)
+}
+
+// NewChannel builds the channel named by kind: none (nil), log, file or smtp.
+func NewChannel(name, kind string, cfg config.NotifyConfig, logger *slog.Logger) (Channel, error) {
+	switch kind {
+	case "none":
+		return nil, nil
+	case "log":
+		return LogChannel{Name: name, Logger: logger}, nil
+	case "file":
+		return &FileChannel{Name: name, Path: cfg.File}, nil
+	case "smtp":
+		return SMTPChannel{Addr: cfg.SMTP.Addr, From: cfg.SMTP.From, Username: cfg.SMTP.Username, Password: cfg.SMTP.Password}, nil
+	}
+	return nil, fmt.Errorf("unknown %s channel %q", name, kind)
+}
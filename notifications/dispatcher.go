@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reservation-service/storage/postgres"
+	"slices"
+	"time"
+)
+
+// Store is the part of the repository the dispatcher needs.
+type Store interface {
+	EnqueueReminders(ctx context.Context) (int64, error)
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]postgres.Notification, error)
+	MarkNotification(ctx context.Context, id, outcome, reason string) error
+	MarkChannelSent(ctx context.Context, id, channel string) error
+	RetryNotification(ctx context.Context, id string, retryIn time.Duration, reason string) error
+}
+
+// Dispatcher queues reminders as reservations approach and delivers due
+// notifications. A nil Email or SMS channel disables that channel.
+type Dispatcher struct {
+	Store         Store
+	Email         Channel
+	SMS           Channel
+	Logger        *slog.Logger
+	BatchSize     int
+	PollInterval  time.Duration
+	Lease         time.Duration
+	MaxAttempts   int
+	RetryInterval time.Duration
+}
+
+// Run enqueues reminders and delivers notifications every PollInterval until
+// ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.Store.EnqueueReminders(ctx); err != nil {
+			d.Logger.Error("Failed enqueue reminders", "error", err.Error())
+		} else if n > 0 {
+			d.Logger.Info("Reminders enqueued", "count", n)
+		}
+		if _, err := d.Flush(ctx); err != nil {
+			d.Logger.Error("Failed deliver notifications", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush delivers one batch of due notifications and returns how many were
+// claimed.
+func (d *Dispatcher) Flush(ctx context.Context) (int, error) {
+	notifications, err := d.Store.ClaimNotifications(ctx, d.BatchSize, d.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range notifications {
+		if err := d.deliver(ctx, n); err != nil {
+			return len(notifications), err
+		}
+	}
+	return len(notifications), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, n postgres.Notification) error {
+	if reason := stale(n); reason != "" {
+		return d.Store.MarkNotification(ctx, n.Id, postgres.NotificationSkipped, reason)
+	}
+
+	type target struct {
+		name    string
+		channel Channel
+		to      string
+	}
+	var targets []target
+	if d.Email != nil && n.EmailEnabled && n.Email != "" {
+		targets = append(targets, target{postgres.ChannelEmail, d.Email, n.Email})
+	}
+	if d.SMS != nil && n.SMSEnabled && n.PhoneNumber != "" {
+		targets = append(targets, target{postgres.ChannelSMS, d.SMS, n.PhoneNumber})
+	}
+	if len(targets) == 0 {
+		return d.Store.MarkNotification(ctx, n.Id, postgres.NotificationSkipped, "guest opted out or has no contact details")
+	}
+
+	msg, err := Render(n.Kind, Data{
+		ReservationId:     n.ReservationId,
+		RestaurantName:    n.RestaurantName,
+		RestaurantAddress: n.RestaurantAddress,
+		ReservationTime:   n.CurrentTime,
+		Status:            n.ReservationStatus,
+	})
+	if err != nil {
+		return d.Store.MarkNotification(ctx, n.Id, postgres.NotificationFailed, err.Error())
+	}
+
+	// A retry only goes out on the channels that failed before.
+	var errs []error
+	for _, t := range targets {
+		if slices.Contains(n.SentChannels, t.name) {
+			continue
+		}
+		if err := t.channel.Send(ctx, t.to, msg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := d.Store.MarkChannelSent(ctx, n.Id, t.name); err != nil {
+			return err
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		d.Logger.Warn("Failed send notification",
+			"id", n.Id,
+			"kind", n.Kind,
+			"reservation_id", n.ReservationId,
+			"attempts", n.Attempts+1,
+			"error", err.Error())
+		if n.Attempts+1 >= d.MaxAttempts {
+			return d.Store.MarkNotification(ctx, n.Id, postgres.NotificationFailed, err.Error())
+		}
+		// MaxAttempts keeps the doubling bounded.
+		retryIn := d.RetryInterval << n.Attempts
+		return d.Store.RetryNotification(ctx, n.Id, retryIn, err.Error())
+	}
+	return d.Store.MarkNotification(ctx, n.Id, postgres.NotificationSent, "")
+}
+
+// stale explains why a notification no longer applies, or returns "".
+func stale(n postgres.Notification) string {
+	if n.Orphaned {
+		return "restaurant no longer exists"
+	}
+	if n.Kind != postgres.NotificationReminder24h && n.Kind != postgres.NotificationReminder2h {
+		return ""
+	}
+	switch {
+	case n.Deleted:
+		return "reservation deleted"
+	case n.ReservationStatus != "Pending" && n.ReservationStatus != "Confirmed":
+		return "reservation is " + n.ReservationStatus
+	case !n.ReservationTime.Equal(n.CurrentTime):
+		return "reservation moved"
+	}
+	return ""
+}
@@ -0,0 +1,234 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reservation-service/storage/postgres"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type outcome struct {
+	status string
+	reason string
+}
+
+type fakeStore struct {
+	notifications []postgres.Notification
+	outcomes      map[string]outcome
+	retries       map[string]time.Duration
+	sent          map[string][]string
+}
+
+func (s *fakeStore) EnqueueReminders(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeStore) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]postgres.Notification, error) {
+	return s.notifications, nil
+}
+
+func (s *fakeStore) MarkNotification(ctx context.Context, id, status, reason string) error {
+	s.outcomes[id] = outcome{status, reason}
+	return nil
+}
+
+func (s *fakeStore) MarkChannelSent(ctx context.Context, id, channel string) error {
+	if s.sent == nil {
+		s.sent = map[string][]string{}
+	}
+	s.sent[id] = append(s.sent[id], channel)
+	return nil
+}
+
+func (s *fakeStore) RetryNotification(ctx context.Context, id string, retryIn time.Duration, reason string) error {
+	s.retries[id] = retryIn
+	return nil
+}
+
+type recordingChannel struct {
+	sent []string
+	err  error
+}
+
+func (c *recordingChannel) Send(ctx context.Context, to string, msg Message) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, to+": "+msg.Subject)
+	return nil
+}
+
+func TestDispatcherFlush(t *testing.T) {
+	at := time.Date(2030, 5, 1, 19, 30, 0, 0, time.UTC)
+	base := postgres.Notification{
+		ReservationStatus: "Confirmed",
+		ReservationTime:   at,
+		CurrentTime:       at,
+		RestaurantName:    "Rayhon",
+		RestaurantAddress: "Amir Temur 1",
+		Email:             "guest@example.com",
+		PhoneNumber:       "+998901234567",
+		EmailEnabled:      true,
+		SMSEnabled:        true,
+	}
+	with := func(id, kind string, change func(*postgres.Notification)) postgres.Notification {
+		n := base
+		n.Id, n.Kind = id, kind
+		if change != nil {
+			change(&n)
+		}
+		return n
+	}
+
+	store := &fakeStore{
+		notifications: []postgres.Notification{
+			with("confirm", postgres.NotificationConfirmation, nil),
+			with("opted-out", postgres.NotificationChange, func(n *postgres.Notification) {
+				n.EmailEnabled, n.SMSEnabled = false, false
+			}),
+			with("moved", postgres.NotificationReminder24h, func(n *postgres.Notification) {
+				n.CurrentTime = at.Add(time.Hour)
+			}),
+			with("cancelled", postgres.NotificationReminder2h, func(n *postgres.Notification) {
+				n.ReservationStatus = "Cancelled"
+			}),
+			with("sms-only", postgres.NotificationReminder2h, func(n *postgres.Notification) {
+				n.EmailEnabled = false
+			}),
+			with("orphaned", postgres.NotificationCancellation, func(n *postgres.Notification) {
+				n.Orphaned = true
+			}),
+		},
+		outcomes: map[string]outcome{},
+		retries:  map[string]time.Duration{},
+	}
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	d := &Dispatcher{
+		Store:         store,
+		Email:         email,
+		SMS:           sms,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BatchSize:     10,
+		MaxAttempts:   3,
+		RetryInterval: time.Minute,
+	}
+
+	n, err := d.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, []string{"guest@example.com: Your table at Rayhon"}, email.sent)
+	assert.Equal(t, []string{"+998901234567: Your table at Rayhon", "+998901234567: See you soon at Rayhon"}, sms.sent)
+	assert.Equal(t, postgres.NotificationSent, store.outcomes["confirm"].status)
+	assert.Equal(t, postgres.NotificationSkipped, store.outcomes["opted-out"].status)
+	assert.Equal(t, outcome{postgres.NotificationSkipped, "reservation moved"}, store.outcomes["moved"])
+	assert.Equal(t, outcome{postgres.NotificationSkipped, "reservation is Cancelled"}, store.outcomes["cancelled"])
+	assert.Equal(t, postgres.NotificationSent, store.outcomes["sms-only"].status)
+	assert.Equal(t, outcome{postgres.NotificationSkipped, "restaurant no longer exists"}, store.outcomes["orphaned"])
+	assert.Equal(t, []string{postgres.ChannelEmail, postgres.ChannelSMS}, store.sent["confirm"])
+}
+
+func TestDispatcherRetriesFailedChannelOnly(t *testing.T) {
+	n := postgres.Notification{
+		Id:           "a",
+		Kind:         postgres.NotificationConfirmation,
+		Email:        "guest@example.com",
+		PhoneNumber:  "+998901234567",
+		EmailEnabled: true,
+		SMSEnabled:   true,
+	}
+	store := &fakeStore{outcomes: map[string]outcome{}, retries: map[string]time.Duration{}}
+	email, sms := &recordingChannel{err: errors.New("smtp down")}, &recordingChannel{}
+	d := &Dispatcher{
+		Store:         store,
+		Email:         email,
+		SMS:           sms,
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BatchSize:     10,
+		MaxAttempts:   3,
+		RetryInterval: time.Minute,
+	}
+
+	store.notifications = []postgres.Notification{n}
+	_, err := d.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, store.retries["a"])
+	assert.Equal(t, []string{postgres.ChannelSMS}, store.sent["a"])
+	assert.Len(t, sms.sent, 1)
+
+	email.err = nil
+	n.Attempts, n.SentChannels = 1, store.sent["a"]
+	store.notifications = []postgres.Notification{n}
+	_, err = d.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, email.sent, 1)
+	assert.Len(t, sms.sent, 1)
+	assert.Equal(t, postgres.NotificationSent, store.outcomes["a"].status)
+}
+
+func TestDispatcherRetriesThenFails(t *testing.T) {
+	n := postgres.Notification{Id: "a", Kind: postgres.NotificationCancellation, Email: "guest@example.com", EmailEnabled: true}
+	store := &fakeStore{outcomes: map[string]outcome{}, retries: map[string]time.Duration{}}
+	d := &Dispatcher{
+		Store:         store,
+		Email:         &recordingChannel{err: errors.New("smtp down")},
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BatchSize:     10,
+		MaxAttempts:   3,
+		RetryInterval: time.Minute,
+	}
+
+	n.Attempts = 1
+	store.notifications = []postgres.Notification{n}
+	_, err := d.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, store.retries["a"])
+
+	n.Attempts = 2
+	store.notifications = []postgres.Notification{n}
+	_, err = d.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, outcome{postgres.NotificationFailed, "smtp down"}, store.outcomes["a"])
+}
+
+func TestRender(t *testing.T) {
+	msg, err := Render(postgres.NotificationReminder24h, Data{
+		ReservationId:     "r1",
+		RestaurantName:    "Rayhon",
+		RestaurantAddress: "Amir Temur 1",
+		ReservationTime:   time.Date(2030, 5, 1, 19, 30, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "See you tomorrow at Rayhon", msg.Subject)
+	assert.Equal(t, "Reminder: your table at Rayhon, Amir Temur 1 is booked for Wed, 01 May 2030 at 19:30. Reference: r1.", msg.Body)
+
+	_, err = Render("birthday", Data{})
+	assert.Error(t, err)
+}
+
+func TestFileChannel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	c := &FileChannel{Name: "email", Path: path}
+
+	assert.NoError(t, c.Send(context.Background(), "a@example.com", Message{Subject: "one", Body: "first"}))
+	assert.NoError(t, c.Send(context.Background(), "b@example.com", Message{Subject: "two", Body: "second"}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+
+	var rec fileRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, "email", rec.Channel)
+	assert.Equal(t, "b@example.com", rec.To)
+	assert.Equal(t, "two", rec.Subject)
+}
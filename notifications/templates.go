@@ -0,0 +1,75 @@
+// Package notifications delivers reservation confirmations, change and
+// cancellation notices and reminders to guests by email and SMS.
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"reservation-service/storage/postgres"
+	"text/template"
+	"time"
+)
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Data is what the templates can refer to.
+type Data struct {
+	ReservationId     string
+	RestaurantName    string
+	RestaurantAddress string
+	ReservationTime   time.Time
+	Status            string
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func mustTemplate(kind, subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New(kind + "_subject").Parse(subject)),
+		body:    template.Must(template.New(kind + "_body").Parse(body)),
+	}
+}
+
+const when = `{{.ReservationTime.Format "Mon, 02 Jan 2006 at 15:04"}}`
+
+var templates = map[string]messageTemplate{
+	postgres.NotificationConfirmation: mustTemplate(postgres.NotificationConfirmation,
+		`Your table at {{.RestaurantName}}`,
+		`Your reservation at {{.RestaurantName}}, {{.RestaurantAddress}} on `+when+` is {{.Status}}. Reference: {{.ReservationId}}.`),
+	postgres.NotificationChange: mustTemplate(postgres.NotificationChange,
+		`Your reservation at {{.RestaurantName}} has changed`,
+		`Your reservation at {{.RestaurantName}}, {{.RestaurantAddress}} is now on `+when+` and is {{.Status}}. Reference: {{.ReservationId}}.`),
+	postgres.NotificationCancellation: mustTemplate(postgres.NotificationCancellation,
+		`Your reservation at {{.RestaurantName}} was cancelled`,
+		`Your reservation at {{.RestaurantName}} on `+when+` has been cancelled. Reference: {{.ReservationId}}.`),
+	postgres.NotificationReminder24h: mustTemplate(postgres.NotificationReminder24h,
+		`See you tomorrow at {{.RestaurantName}}`,
+		`Reminder: your table at {{.RestaurantName}}, {{.RestaurantAddress}} is booked for `+when+`. Reference: {{.ReservationId}}.`),
+	postgres.NotificationReminder2h: mustTemplate(postgres.NotificationReminder2h,
+		`See you soon at {{.RestaurantName}}`,
+		`Reminder: your table at {{.RestaurantName}}, {{.RestaurantAddress}} is booked for `+when+`. Reference: {{.ReservationId}}.`),
+}
+
+// Render fills in the template for a notification kind.
+func Render(kind string, data Data) (Message, error) {
+	t, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s subject: %v", kind, err)
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s body: %v", kind, err)
+	}
+	return Message{Subject: subject.String(), Body: body.String()}, nil
+}
@@ -0,0 +1,29 @@
+package outbox
+
+import (
+	"context"
+	"reservation-service/storage/postgres"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreamSink appends events to a Redis stream.
+type RedisStreamSink struct {
+	Client *redis.Client
+	Stream string
+}
+
+func (s RedisStreamSink) Publish(ctx context.Context, event postgres.OutboxEvent) error {
+	return s.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.Stream,
+		Values: map[string]interface{}{
+			"id":             event.Id,
+			"event_type":     event.EventType,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateId,
+			"payload":        string(event.Payload),
+			"created_at":     event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		},
+	}).Err()
+}
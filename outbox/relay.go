@@ -0,0 +1,104 @@
+// Package outbox publishes events stored in the Outbox table to a message
+// broker. Events are written in the same transaction as the change they
+// describe, so delivery is at-least-once: consumers must deduplicate by id.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"reservation-service/storage/postgres"
+	"time"
+)
+
+// Store is the part of the repository the relay needs.
+type Store interface {
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]postgres.OutboxEvent, error)
+	MarkOutboxPublished(ctx context.Context, id string) error
+	MarkOutboxFailed(ctx context.Context, id string, retryIn time.Duration, reason string) error
+}
+
+// Sink delivers a single event to the broker.
+type Sink interface {
+	Publish(ctx context.Context, event postgres.OutboxEvent) error
+}
+
+// Relay polls the outbox and publishes due events.
+type Relay struct {
+	Store        Store
+	Sink         Sink
+	Logger       *slog.Logger
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxBackoff   time.Duration
+}
+
+// Run publishes events every PollInterval until ctx is cancelled. A full
+// batch is followed immediately by another one so a backlog drains quickly.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.Flush(ctx)
+			if err != nil {
+				r.Logger.Error("Failed outbox relay", "error", err.Error())
+				break
+			}
+			if n < r.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush claims one batch and publishes it, returning how many events were
+// claimed. Failed events are rescheduled with exponential backoff.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	events, err := r.Store.ClaimOutboxEvents(ctx, r.BatchSize, r.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if err := r.Sink.Publish(ctx, event); err != nil {
+			retryIn := Backoff(event.Attempts, r.PollInterval, r.MaxBackoff)
+			r.Logger.Warn("Failed publish outbox event",
+				"id", event.Id,
+				"event_type", event.EventType,
+				"attempts", event.Attempts+1,
+				"retry_in", retryIn.String(),
+				"error", err.Error())
+			if err := r.Store.MarkOutboxFailed(ctx, event.Id, retryIn, err.Error()); err != nil {
+				return len(events), err
+			}
+			continue
+		}
+		if err := r.Store.MarkOutboxPublished(ctx, event.Id); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+// Backoff returns base doubled for every previous attempt, capped at max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempts; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
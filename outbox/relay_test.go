@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reservation-service/storage/postgres"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	events    []postgres.OutboxEvent
+	published []string
+	failed    map[string]time.Duration
+}
+
+func (s *fakeStore) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]postgres.OutboxEvent, error) {
+	if len(s.events) < limit {
+		limit = len(s.events)
+	}
+	batch := s.events[:limit]
+	s.events = s.events[limit:]
+	return batch, nil
+}
+
+func (s *fakeStore) MarkOutboxPublished(ctx context.Context, id string) error {
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeStore) MarkOutboxFailed(ctx context.Context, id string, retryIn time.Duration, reason string) error {
+	s.failed[id] = retryIn
+	return nil
+}
+
+type fakeSink struct {
+	fail map[string]bool
+	sent []string
+}
+
+func (s *fakeSink) Publish(ctx context.Context, event postgres.OutboxEvent) error {
+	if s.fail[event.Id] {
+		return errors.New("broker unavailable")
+	}
+	s.sent = append(s.sent, event.Id)
+	return nil
+}
+
+func TestFlushPublishesInOrderAndReschedulesFailures(t *testing.T) {
+	store := &fakeStore{
+		events: []postgres.OutboxEvent{
+			{Id: "a", EventType: postgres.EventReservationCreated},
+			{Id: "b", EventType: postgres.EventMealsOrdered, Attempts: 2},
+			{Id: "c", EventType: postgres.EventReservationCancelled},
+		},
+		failed: map[string]time.Duration{},
+	}
+	sink := &fakeSink{fail: map[string]bool{"b": true}}
+	relay := &Relay{
+		Store:        store,
+		Sink:         sink,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BatchSize:    10,
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+		MaxBackoff:   time.Minute,
+	}
+
+	n, err := relay.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"a", "c"}, sink.sent)
+	assert.Equal(t, []string{"a", "c"}, store.published)
+	assert.Equal(t, map[string]time.Duration{"b": 4 * time.Second}, store.failed)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(0, time.Second, time.Minute))
+	assert.Equal(t, 8*time.Second, Backoff(3, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, Backoff(10, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, Backoff(1000, time.Second, time.Minute))
+}
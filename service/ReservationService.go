@@ -3,122 +3,169 @@ package service
 import (
 	"context"
 	"log/slog"
+	"reservation-service/clients"
+	"reservation-service/geo"
 	pb "reservation-service/generated/reservation_service"
+	"reservation-service/metrics"
 	"reservation-service/storage/postgres"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ReservationService struct{
 	pb.UnimplementedReservationServiceServer
 	Reservation postgres.ReservationRepo
+	Clients *clients.Clients
 	Logger *slog.Logger
+	Metrics *metrics.Metrics
+	Geocoder geo.Geocoder
 }
 
-func NewRRestaurantService(reservation postgres.ReservationRepo)*ReservationService{
-	return &ReservationService{Reservation: reservation}
+func NewRRestaurantService(reservation postgres.ReservationRepo, c *clients.Clients, logger *slog.Logger, m *metrics.Metrics)*ReservationService{
+	return &ReservationService{Reservation: reservation, Clients: c, Logger: logger, Metrics: m}
 }
 
 func (r *ReservationService) CreateRestaurant(ctx context.Context,restaurant *pb.CreateRestaurantRequest)(*pb.CreateRestaurantResponse,error){
-	r.Logger.Info("Create Restaurant")
-	res,err := r.Reservation.CreateRestaurant(restaurant)
-	if err != nil{
-		r.Logger.Error("Failed created to restaurant", "error", err.Error())
-		return nil,err
-	}
-	return res,nil
+	restaurant.Location = r.locate(ctx,restaurant.Address,restaurant.Location)
+	return r.Reservation.CreateRestaurant(ctx,restaurant)
 }
 
 func (r *ReservationService) ListRestaurants(ctx context.Context, listRestaurant *pb.ListRestaurantsRequest)(*pb.ListRestaurantsResponse,error){
-	r.Logger.Info("List Restaurant")
-	res,err := r.Reservation.ListRestaurants(listRestaurant)
-	if err != nil{
-		r.Logger.Error("Failed get restaurants","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.ListRestaurants(ctx,listRestaurant)
 }
 
 func (r *ReservationService) GetRestaurant(ctx context.Context, id *pb.GetRestaurantRequest)(*pb.GetRestaurantResponse,error){
-	r.Logger.Info("Get Restaurant")
-	res,err := r.Reservation.GetRestaurant(id)
-	if err != nil{
-		r.Logger.Error("Failed get restaurant","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.GetRestaurant(ctx,id)
 }
 
 func (r *ReservationService) UpdateRestaurant(ctx context.Context,updateRestaurant *pb.UpdateRestaurantRequest)(*pb.UpdateRestaurantResponse,error){
-	r.Logger.Info("Update to Restaurant")
-	res,err := r.Reservation.UpdateRestaurant(updateRestaurant)
+	// Only a changed address is looked up again.
+	if updateRestaurant.Location == nil && r.Geocoder != nil{
+		current,err := r.Reservation.GetRestaurant(ctx,&pb.GetRestaurantRequest{Id: updateRestaurant.Id})
+		if err != nil{
+			return nil,err
+		}
+		if current.Restaurant.Address != updateRestaurant.Address{
+			updateRestaurant.Location = r.locate(ctx,updateRestaurant.Address,nil)
+		}
+	}
+	return r.Reservation.UpdateRestaurant(ctx,updateRestaurant)
+}
+
+func (r *ReservationService) DeleteRestaurant(ctx context.Context, id *pb.DeleteRestaurantRequest)(*pb.DeleteRestaurantResponse,error){
+	// Guests of paid reservations get their money back before force cancels them.
+	if id.Force{
+		if err := r.refundUpcomingPayments(ctx,id.Id); err != nil{
+			return nil,err
+		}
+	}
+	res,err := r.Reservation.DeleteRestaurant(ctx,id)
 	if err != nil{
-		r.Logger.Error("Failed update to restaurant","error",err.Error())
 		return nil,err
 	}
+	r.Metrics.ReservationsCancelled(id.Id,int(res.CancelledReservations))
 	return res,nil
 }
 
-func (r *ReservationService) DeleteRestaurant(ctx context.Context, id *pb.DeleteRestaurantRequest)(*pb.DeleteRestaurantResponse,error){
-	r.Logger.Info("Delete for Restaurant")
-	res,err := r.Reservation.DeleteRestaurant(id)
+func (r *ReservationService) RestoreRestaurant(ctx context.Context, id *pb.RestoreRestaurantRequest)(*pb.RestoreRestaurantResponse,error){
+	return r.Reservation.RestoreRestaurant(ctx,id)
+}
+
+func (r *ReservationService) DeactivateRestaurant(ctx context.Context, req *pb.DeactivateRestaurantRequest)(*pb.DeactivateRestaurantResponse,error){
+	if req.Force{
+		if err := r.refundUpcomingPayments(ctx,req.Id); err != nil{
+			return nil,err
+		}
+	}
+	res,err := r.Reservation.DeactivateRestaurant(ctx,req)
 	if err != nil{
-		r.Logger.Error("Failed delete to restaurant","error",err.Error())
 		return nil,err
 	}
+	r.Metrics.ReservationsCancelled(req.Id,int(res.CancelledReservations))
 	return res,nil
 }
 
+func (r *ReservationService) ActivateRestaurant(ctx context.Context, req *pb.ActivateRestaurantRequest)(*pb.ActivateRestaurantResponse,error){
+	return r.Reservation.ActivateRestaurant(ctx,req)
+}
+
+func (r *ReservationService) Search(ctx context.Context, req *pb.SearchRequest)(*pb.SearchResponse,error){
+	return r.Reservation.Search(ctx,req)
+}
+
+func (r *ReservationService) ListNearbyRestaurants(ctx context.Context, req *pb.ListNearbyRestaurantsRequest)(*pb.ListNearbyRestaurantsResponse,error){
+	return r.Reservation.ListNearbyRestaurants(ctx,req)
+}
+
+func (r *ReservationService) SetOpeningHours(ctx context.Context, req *pb.SetOpeningHoursRequest)(*pb.SetOpeningHoursResponse,error){
+	return r.Reservation.SetOpeningHours(ctx,req)
+}
+
+func (r *ReservationService) SetRestaurantProfile(ctx context.Context, req *pb.SetRestaurantProfileRequest)(*pb.SetRestaurantProfileResponse,error){
+	return r.Reservation.SetRestaurantProfile(ctx,req)
+}
+
+func (r *ReservationService) ListRestaurantTags(ctx context.Context, req *pb.ListRestaurantTagsRequest)(*pb.ListRestaurantTagsResponse,error){
+	return r.Reservation.ListRestaurantTags(ctx,req)
+}
+
 
 
 
 
 
 func (r *ReservationService) CreateReservation(ctx context.Context,reservation *pb.CreateReservationRequest)(*pb.CreateReservationResponse,error){
-	r.Logger.Info("Create to Reservation")
-	res,err := r.Reservation.CreateReservation(reservation)
+	res,err := r.Reservation.CreateReservation(ctx,reservation)
 	if err != nil{
-		r.Logger.Error("Failed create to reservation","error",err.Error())
 		return nil,err
 	}
+	r.Metrics.ReservationCreated(res.Reservation.RestaurantId)
 	return res,nil
 }
 
 func (r *ReservationService) ListReservations(ctx context.Context,listReservation *pb.ListReservationsRequest)(*pb.ListReservationsResponse,error){
-	r.Logger.Info("List Reservation")
-	res,err :=r.Reservation.ListReservations(listReservation)
-	if err != nil{
-		r.Logger.Error("Failed get reservations","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.ListReservations(ctx,listReservation)
 }
 
 func (r *ReservationService) GetReservation(ctx context.Context,Reservation *pb.GetReservationRequest)(*pb.GetReservationResponse,error){
-	r.Logger.Info("Get Reservation")
-	res,err := r.Reservation.GetReservation(Reservation)
-	if err != nil{
-		r.Logger.Error("Failed get reservation","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.GetReservation(ctx,Reservation)
 }
 
 func (r *ReservationService) UpdateReservation(ctx context.Context, updateReservation *pb.UpdateReservationRequest)(*pb.UpdateReservationResponse,error){
-	r.Logger.Error("Update Reservation")
-	res,err := r.Reservation.UpdateReservation(updateReservation)
-	if err != nil{
-		r.Logger.Error("Failed update to reservation","error",err.Error())
+	// Cancellations go through CancelReservation, which counts them.
+	return r.Reservation.UpdateReservation(ctx,updateReservation)
+}
+
+func (r *ReservationService) DeleteReservation(ctx context.Context, id *pb.DeleteReservationRequest)(*pb.DeleteReservationResponse,error){
+	// Deleting an upcoming reservation cancels it under the restaurant's policy
+	// first. One that has started can't be cancelled, and the repository
+	// refuses to delete it.
+	_,err := r.CancelReservation(ctx,&pb.CancelReservationRequest{Id: id.Id,Reason: "Reservation deleted"})
+	if err != nil && status.Code(err) != codes.FailedPrecondition{
 		return nil,err
 	}
-	return res,nil
+	return r.Reservation.DeleteReservation(ctx,id)
 }
 
-func (r *ReservationService) DeleteReservation(ctx context.Context, id *pb.DeleteReservationRequest)(*pb.DeleteReservationResponse,error){
-	r.Logger.Info("Delete Rservation")
-	res,err := r.Reservation.DeleteReservation(id)
+func (r *ReservationService) RestoreReservation(ctx context.Context, id *pb.RestoreReservationRequest)(*pb.RestoreReservationResponse,error){
+	return r.Reservation.RestoreReservation(ctx,id)
+}
+
+func (r *ReservationService) CheckReservation(ctx context.Context, req *pb.CheckReservationRequest)(*pb.CheckReservationResponse,error){
+	return r.Reservation.CheckReservation(ctx,req)
+}
+
+func (r *ReservationService) OrderMeals(ctx context.Context, req *pb.OrderMealsRequest)(*pb.OrderMealsResponse,error){
+	res,err := r.Reservation.OrderMeals(ctx,req)
 	if err != nil{
-		r.Logger.Error("Failed delete to reservation","error",err.Error())
 		return nil,err
 	}
+	quantity := 0
+	for _,meal := range req.Meals{
+		quantity += int(meal.Quantity)
+	}
+	r.Metrics.MealsOrdered(quantity)
 	return res,nil
 }
 
@@ -129,51 +176,152 @@ func (r *ReservationService) DeleteReservation(ctx context.Context, id *pb.Delet
 
 
 func (r * ReservationService) CreateMenuItem(ctx context.Context, menu *pb.CreateMenuItemRequest)(*pb.CreateMenuItemResponse,error){
-	r.Logger.Info("Create MenuItem")
-	res,err := r.Reservation.CreateMenuItem(menu)
-	if err != nil{
-		r.Logger.Error("Failed create to menuitem","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.CreateMenuItem(ctx,menu)
 }
 
 func (r *ReservationService) ListMenuItems(ctx context.Context, listMenu *pb.ListMenuItemsRequest)(*pb.ListMenuItemsResponse,error){
-	r.Logger.Info("List MenuItem")
-	res,err := r.Reservation.ListMenuItems(listMenu)
-	if err != nil{
-		r.Logger.Error("Failed get menuitems","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.ListMenuItems(ctx,listMenu)
 }
 
 func (r *ReservationService)  GetMenuItem(ctx context.Context,id *pb.GetMenuItemRequest)(*pb.GetMenuItemResponse,error){
-	r.Logger.Info("Get MenuItem")
-	res,err := r.Reservation.GetMenuItem(id)
-	if err != nil{
-		r.Logger.Error("Failed get menuitem","error",err.Error())
-		return nil,err
-	}
-	return res,nil
+	return r.Reservation.GetMenuItem(ctx,id)
 }
 
 func (r *ReservationService) UpdateMenuItem(ctx context.Context, menu *pb.UpdateMenuItemRequest)(*pb.UpdateMenuItemResponse,error){
-	r.Logger.Info("Update MenuItem")
-	res,err := r.Reservation.UpdateMenuItem(menu)
-	if err != nil{
-		r.Logger.Error("Failed update menuitem","error",err.Error())
-		 return nil,err
-	}
-	return res,nil
+	return r.Reservation.UpdateMenuItem(ctx,menu)
 }
 
 func	(r *ReservationService) DeleteMenuItem(ctx context.Context, id *pb.DeleteMenuItemRequest)(*pb.DeleteMenuItemResponse,error){
-	r.Logger.Info("Delete MenuItem")
-	res,err := r.Reservation.DeleteMenuItem(id)
-	if err != nil{
-		r.Logger.Error("Failed delete to menuitem","error",err.Error())
+	return r.Reservation.DeleteMenuItem(ctx,id)
+}
+
+func (r *ReservationService) RestoreMenuItem(ctx context.Context, id *pb.RestoreMenuItemRequest)(*pb.RestoreMenuItemResponse,error){
+	return r.Reservation.RestoreMenuItem(ctx,id)
+}
+
+func (r *ReservationService) MoveMenuItem(ctx context.Context, req *pb.MoveMenuItemRequest)(*pb.MoveMenuItemResponse,error){
+	return r.Reservation.MoveMenuItem(ctx,req)
+}
+
+func (r *ReservationService) SetMenuItemModifiers(ctx context.Context, req *pb.SetMenuItemModifiersRequest)(*pb.SetMenuItemModifiersResponse,error){
+	return r.Reservation.SetMenuItemModifiers(ctx,req)
+}
+
+func (r *ReservationService) SetMenuItemTags(ctx context.Context, req *pb.SetMenuItemTagsRequest)(*pb.SetMenuItemTagsResponse,error){
+	return r.Reservation.SetMenuItemTags(ctx,req)
+}
+
+func (r *ReservationService) ListMenuTags(ctx context.Context, req *pb.ListMenuTagsRequest)(*pb.ListMenuTagsResponse,error){
+	return r.Reservation.ListMenuTags(ctx,req)
+}
+
+func (r *ReservationService) SetMenuItemAvailability(ctx context.Context, req *pb.SetMenuItemAvailabilityRequest)(*pb.SetMenuItemAvailabilityResponse,error){
+	return r.Reservation.SetMenuItemAvailability(ctx,req)
+}
+
+func (r *ReservationService) SetMenuItemSoldOut(ctx context.Context, req *pb.SetMenuItemSoldOutRequest)(*pb.SetMenuItemSoldOutResponse,error){
+	return r.Reservation.SetMenuItemSoldOut(ctx,req)
+}
+
+func (r *ReservationService) CreateMenuCategory(ctx context.Context, req *pb.CreateMenuCategoryRequest)(*pb.CreateMenuCategoryResponse,error){
+	return r.Reservation.CreateMenuCategory(ctx,req)
+}
+
+func (r *ReservationService) ListMenuCategories(ctx context.Context, req *pb.ListMenuCategoriesRequest)(*pb.ListMenuCategoriesResponse,error){
+	return r.Reservation.ListMenuCategories(ctx,req)
+}
+
+func (r *ReservationService) UpdateMenuCategory(ctx context.Context, req *pb.UpdateMenuCategoryRequest)(*pb.UpdateMenuCategoryResponse,error){
+	return r.Reservation.UpdateMenuCategory(ctx,req)
+}
+
+func (r *ReservationService) DeleteMenuCategory(ctx context.Context, req *pb.DeleteMenuCategoryRequest)(*pb.DeleteMenuCategoryResponse,error){
+	return r.Reservation.DeleteMenuCategory(ctx,req)
+}
+
+func (r *ReservationService) PurgeDeleted(ctx context.Context, req *pb.PurgeDeletedRequest)(*pb.PurgeDeletedResponse,error){
+	if err := requireAdmin(ctx); err != nil{
 		return nil,err
 	}
-	return res,nil
-}
\ No newline at end of file
+	return r.Reservation.PurgeDeleted(ctx,req)
+}
+
+func (r *ReservationService) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest)(*pb.GetNotificationPreferencesResponse,error){
+	return r.Reservation.GetNotificationPreferences(ctx,req)
+}
+
+func (r *ReservationService) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest)(*pb.UpdateNotificationPreferencesResponse,error){
+	return r.Reservation.UpdateNotificationPreferences(ctx,req)
+}
+
+func (r *ReservationService) SeatReservation(ctx context.Context, req *pb.SeatReservationRequest)(*pb.SeatReservationResponse,error){
+	return r.Reservation.SeatReservation(ctx,req)
+}
+
+func (r *ReservationService) GetNoShowPolicy(ctx context.Context, req *pb.GetNoShowPolicyRequest)(*pb.GetNoShowPolicyResponse,error){
+	return r.Reservation.GetNoShowPolicy(ctx,req)
+}
+
+func (r *ReservationService) SetNoShowPolicy(ctx context.Context, req *pb.SetNoShowPolicyRequest)(*pb.SetNoShowPolicyResponse,error){
+	return r.Reservation.SetNoShowPolicy(ctx,req)
+}
+
+func (r *ReservationService) GetGuestNoShows(ctx context.Context, req *pb.GetGuestNoShowsRequest)(*pb.GetGuestNoShowsResponse,error){
+	return r.Reservation.GetGuestNoShows(ctx,req)
+}
+
+func (r *ReservationService) GetBill(ctx context.Context, req *pb.GetBillRequest)(*pb.GetBillResponse,error){
+	return r.Reservation.GetBill(ctx,req)
+}
+
+func (r *ReservationService) ApplyPromoCode(ctx context.Context, req *pb.ApplyPromoCodeRequest)(*pb.ApplyPromoCodeResponse,error){
+	return r.Reservation.ApplyPromoCode(ctx,req)
+}
+
+func (r *ReservationService) GetBillingSettings(ctx context.Context, req *pb.GetBillingSettingsRequest)(*pb.GetBillingSettingsResponse,error){
+	return r.Reservation.GetBillingSettings(ctx,req)
+}
+
+func (r *ReservationService) SetBillingSettings(ctx context.Context, req *pb.SetBillingSettingsRequest)(*pb.SetBillingSettingsResponse,error){
+	return r.Reservation.SetBillingSettings(ctx,req)
+}
+
+func (r *ReservationService) SetPromoCode(ctx context.Context, req *pb.SetPromoCodeRequest)(*pb.SetPromoCodeResponse,error){
+	return r.Reservation.SetPromoCode(ctx,req)
+}
+
+func (r *ReservationService) ListPromoCodes(ctx context.Context, req *pb.ListPromoCodesRequest)(*pb.ListPromoCodesResponse,error){
+	return r.Reservation.ListPromoCodes(ctx,req)
+}
+
+func (r *ReservationService) ListRefunds(ctx context.Context, req *pb.ListRefundsRequest)(*pb.ListRefundsResponse,error){
+	return r.Reservation.ListRefunds(ctx,req)
+}
+
+func (r *ReservationService) SetRestaurantStaff(ctx context.Context, req *pb.SetRestaurantStaffRequest)(*pb.SetRestaurantStaffResponse,error){
+	// Roles are granted by administrators and the restaurant's own managers.
+	if err := r.requireManager(ctx,req.GetMember().GetRestaurantId()); err != nil{
+		return nil,err
+	}
+	return r.Reservation.SetRestaurantStaff(ctx,req)
+}
+
+func (r *ReservationService) ListRestaurantStaff(ctx context.Context, req *pb.ListRestaurantStaffRequest)(*pb.ListRestaurantStaffResponse,error){
+	return r.Reservation.ListRestaurantStaff(ctx,req)
+}
+
+func (r *ReservationService) ListDepositRules(ctx context.Context, req *pb.ListDepositRulesRequest)(*pb.ListDepositRulesResponse,error){
+	return r.Reservation.ListDepositRules(ctx,req)
+}
+
+func (r *ReservationService) SetDepositRules(ctx context.Context, req *pb.SetDepositRulesRequest)(*pb.SetDepositRulesResponse,error){
+	return r.Reservation.SetDepositRules(ctx,req)
+}
+
+func (r *ReservationService) GetCancellationPolicy(ctx context.Context, req *pb.GetCancellationPolicyRequest)(*pb.GetCancellationPolicyResponse,error){
+	return r.Reservation.GetCancellationPolicy(ctx,req)
+}
+
+func (r *ReservationService) SetCancellationPolicy(ctx context.Context, req *pb.SetCancellationPolicyRequest)(*pb.SetCancellationPolicyResponse,error){
+	return r.Reservation.SetCancellationPolicy(ctx,req)
+}
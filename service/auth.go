@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"reservation-service/auth"
+	"reservation-service/storage/postgres"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callerID returns the id of the authenticated caller, or "" if unknown.
+func callerID(ctx context.Context) string {
+	caller, _ := auth.FromContext(ctx)
+	return caller.UserID
+}
+
+// requireAdmin fails unless the caller is a platform administrator.
+func requireAdmin(ctx context.Context) error {
+	caller, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+	if !caller.IsAdmin() {
+		return status.Error(codes.PermissionDenied, "only administrators can do this")
+	}
+	return nil
+}
+
+// requireManager fails unless the caller is an administrator or a manager of
+// the restaurant.
+func (r *ReservationService) requireManager(ctx context.Context, restaurantId string) error {
+	caller, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+	if caller.IsAdmin() {
+		return nil
+	}
+	role, err := r.Reservation.StaffRole(ctx, restaurantId, caller.UserID)
+	if err != nil {
+		return err
+	}
+	if role != postgres.RoleManager {
+		return status.Error(codes.PermissionDenied, "only the restaurant's managers can do this")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	paymentpb "reservation-service/generated/payment_service"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"reservation-service/storage/postgres"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CancelReservation cancels a pending or confirmed reservation under its
+// restaurant's cancellation policy. If the reservation was paid, everything
+// but the fee is refunded, of the deposit as well as of the bill. Each refund
+// is recorded under an idempotency key before it is issued, so retrying a
+// failed call resumes those refunds instead of issuing others.
+func (r *ReservationService) CancelReservation(ctx context.Context, req *pb.CancelReservationRequest) (*pb.CancelReservationResponse, error) {
+	quote, err := r.Reservation.QuoteCancellation(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := quote.Applied
+	if len(quote.PaymentIds) > 0 {
+		applied.Fee = &pb.Money{Currency: quote.Currency}
+		applied.Refund = &pb.Money{Currency: quote.Currency}
+	}
+	for _, paymentId := range quote.PaymentIds {
+		fee, refund, err := r.refundCancelledPayment(ctx, quote, paymentId)
+		if err != nil {
+			return nil, err
+		}
+		applied.Fee.MinorUnits += fee
+		applied.Refund.MinorUnits += refund
+	}
+
+	reservation, err := r.Reservation.ApplyCancellation(ctx, req.Id, req.Reason, applied)
+	if err != nil {
+		return nil, err
+	}
+	r.Metrics.ReservationsCancelled(reservation.RestaurantId, 1)
+	return &pb.CancelReservationResponse{Reservation: reservation, AppliedPolicy: applied}, nil
+}
+
+// refundCancelledPayment refunds one payment of a reservation being
+// cancelled less the policy's fee, and returns the fee and the refund.
+func (r *ReservationService) refundCancelledPayment(ctx context.Context, quote *postgres.CancellationQuote, paymentId string) (int64, int64, error) {
+	// A retry finds the refund of the first attempt and quotes the fee on
+	// the amount paid before it.
+	key := "cancel:" + quote.ReservationId + ":" + paymentId
+	rec, err := r.Reservation.FindRefund(ctx, key)
+	if err != nil {
+		return 0, 0, err
+	}
+	var balance int64
+	if rec != nil {
+		balance = rec.BalanceBefore
+	} else {
+		res, err := r.Clients.Payment.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: paymentId})
+		if err != nil {
+			return 0, 0, status.Errorf(codes.Unavailable, "payment service: %v", err)
+		}
+		balance = money.FromMajor(res.Payment.GetAmount(), quote.Currency)
+	}
+	fee := money.Percent(balance, float64(quote.Applied.FeePercent))
+	refund := balance - fee
+	if refund <= 0 {
+		return fee, 0, nil
+	}
+
+	if rec == nil {
+		rec, err = r.Reservation.BeginRefund(ctx, &pb.Refund{
+			ReservationId:  quote.ReservationId,
+			PaymentId:      paymentId,
+			Amount:         &pb.Money{MinorUnits: refund, Currency: quote.Currency},
+			Reason:         "Reservation cancelled",
+			RequestedBy:    callerID(ctx),
+			IdempotencyKey: key,
+		}, balance)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if _, err = r.issueRefund(ctx, rec); err != nil {
+		return 0, 0, err
+	}
+	return fee, refund, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	paymentpb "reservation-service/generated/payment_service"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PayDeposit charges the deposit a reservation is held for and confirms it.
+// Paying an already paid deposit succeeds without charging again. If the hold
+// expired while the payment was made, the payment is refunded.
+func (r *ReservationService) PayDeposit(ctx context.Context, req *pb.PayDepositRequest) (*pb.PayDepositResponse, error) {
+	deposit, err := r.Reservation.GetDeposit(ctx, req.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	if deposit.Amount == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "reservation does not require a deposit")
+	}
+	amount := &pb.Money{MinorUnits: deposit.Amount, Currency: deposit.Currency}
+	if deposit.Paid {
+		return &pb.PayDepositResponse{Status: "Paid", Amount: amount}, nil
+	}
+	if deposit.Status != "Pending" {
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %s and no longer held for a deposit", deposit.Status)
+	}
+
+	res, err := r.Clients.Payment.CreatePayment(ctx, &paymentpb.CreatePaymentRequest{
+		ReservationId: req.ReservationId,
+		Amount:        money.ToMajor(deposit.Amount, deposit.Currency),
+		PaymentMethod: req.PaymentMethod,
+		PaymentStatus: "Pending",
+	})
+	failed := err != nil || res.Payment.GetPaymentStatus() == PaymentStatusFailed
+	r.Metrics.PaymentAttempted(failed)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "payment service: %v", err)
+	}
+	if failed {
+		return &pb.PayDepositResponse{Status: res.Payment.GetPaymentStatus(), Amount: amount}, nil
+	}
+
+	payment := res.Payment
+	err = r.Reservation.SetDepositPaid(ctx, req.ReservationId, payment.GetId(), amount, req.PaymentMethod)
+	if status.Code(err) == codes.FailedPrecondition {
+		_, refundErr := r.Clients.Payment.UpdatePayment(ctx, &paymentpb.UpdatePaymentRequest{
+			Id:            payment.GetId(),
+			ReservationId: payment.GetReservationId(),
+			Amount:        0,
+			PaymentMethod: payment.GetPaymentMethod(),
+			PaymentStatus: PaymentStatusRefunded,
+		})
+		if refundErr != nil {
+			r.Logger.Error("Failed to refund deposit of released hold", "reservation_id", req.ReservationId,
+				"payment_id", payment.GetId(), "error", refundErr.Error())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PayDepositResponse{Status: payment.GetPaymentStatus(), Amount: amount}, nil
+}
+
+// RunDepositExpiryJob cancels reservations whose deposit was not paid within
+// ttl of booking, every interval until ctx is cancelled.
+func (r *ReservationService) RunDepositExpiryJob(ctx context.Context, ttl, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := r.Reservation.ExpireDepositHolds(ctx, ttl)
+			if err != nil {
+				r.Logger.Error("Failed deposit expiry job", "error", err.Error())
+				continue
+			}
+			perRestaurant := make(map[string]int)
+			for _, e := range expired {
+				perRestaurant[e.RestaurantId]++
+			}
+			for restaurantId, n := range perRestaurant {
+				r.Metrics.ReservationsCancelled(restaurantId, n)
+			}
+			if len(expired) > 0 {
+				r.Logger.Info("Deposit expiry job finished", "reservations", len(expired))
+			}
+		}
+	}
+}
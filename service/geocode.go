@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"errors"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/geo"
+)
+
+// locate returns location, or the location of address when none was given
+// and a geocoder is set up. A failed lookup is logged and leaves the
+// location unset rather than failing the request.
+func (r *ReservationService) locate(ctx context.Context, address string, location *pb.GeoPoint) *pb.GeoPoint {
+	if location != nil || r.Geocoder == nil || address == "" {
+		return location
+	}
+	point, err := r.Geocoder.Geocode(ctx, address)
+	if err != nil {
+		level := r.Logger.Error
+		if errors.Is(err, geo.ErrNotFound) {
+			level = r.Logger.Warn
+		}
+		level("Failed to geocode restaurant address", "address", address, "error", err.Error())
+		return nil
+	}
+	return &pb.GeoPoint{Latitude: point.Lat, Longitude: point.Lng}
+}
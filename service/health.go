@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	pb "reservation-service/generated/reservation_service"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Health check service names reported next to the overall ("") status.
+const (
+	HealthPostgres = "postgres"
+	HealthRedis    = "redis"
+)
+
+// WatchHealth probes Postgres and Redis every interval and publishes their
+// readiness on hs until ctx is cancelled. The overall status and the
+// ReservationService status are SERVING only when both dependencies are.
+func WatchHealth(ctx context.Context, hs *health.Server, db *sql.DB, r *redis.Client, interval time.Duration) {
+	check := func() {
+		probeCtx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+
+		pgOK := db.PingContext(probeCtx) == nil
+		redisOK := r.Ping(probeCtx).Err() == nil
+
+		hs.SetServingStatus(HealthPostgres, servingStatus(pgOK))
+		hs.SetServingStatus(HealthRedis, servingStatus(redisOK))
+		hs.SetServingStatus("", servingStatus(pgOK && redisOK))
+		hs.SetServingStatus(pb.ReservationService_ServiceDesc.ServiceName, servingStatus(pgOK && redisOK))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	check()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	pb "reservation-service/generated/reservation_service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxImportRows bounds how many rows one ImportMenu call may send, since the
+// rows are held in memory until the stream ends.
+const maxImportRows = 10000
+
+// ImportMenu collects the rows streamed by the client and imports them in one
+// transaction once the stream ends.
+func (r *ReservationService) ImportMenu(stream pb.ReservationService_ImportMenuServer) error {
+	var req *pb.ImportMenuRequest
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			req = &pb.ImportMenuRequest{RestaurantId: msg.RestaurantId, DryRun: msg.DryRun}
+		}
+		if len(req.Rows)+len(msg.Rows) > maxImportRows {
+			return status.Errorf(codes.InvalidArgument, "an import may have at most %d rows", maxImportRows)
+		}
+		req.Rows = append(req.Rows, msg.Rows...)
+	}
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "restaurant_id is required")
+	}
+
+	res, err := r.Reservation.ImportMenu(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(res)
+}
+
+func (r *ReservationService) ExportMenu(ctx context.Context, req *pb.ExportMenuRequest) (*pb.ExportMenuResponse, error) {
+	return r.Reservation.ExportMenu(ctx, req)
+}
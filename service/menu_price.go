@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	pb "reservation-service/generated/reservation_service"
+	"time"
+)
+
+func (r *ReservationService) GetMenuItemPriceHistory(ctx context.Context, req *pb.GetMenuItemPriceHistoryRequest) (*pb.GetMenuItemPriceHistoryResponse, error) {
+	return r.Reservation.GetMenuItemPriceHistory(ctx, req)
+}
+
+func (r *ReservationService) CancelMenuPriceChange(ctx context.Context, req *pb.CancelMenuPriceChangeRequest) (*pb.CancelMenuPriceChangeResponse, error) {
+	return r.Reservation.CancelMenuPriceChange(ctx, req)
+}
+
+// RunPriceChangeJob applies scheduled menu prices once they take effect,
+// every interval until ctx is cancelled.
+func (r *ReservationService) RunPriceChangeJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := r.Reservation.ApplyDuePriceChanges(ctx)
+			if err != nil {
+				r.Logger.Error("Failed price change job", "error", err.Error())
+				continue
+			}
+			if n > 0 {
+				r.Logger.Info("Price change job finished", "menu_items", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RunNoShowJob marks reservations as no-shows once grace has passed since
+// their time, every interval until ctx is cancelled.
+func (r *ReservationService) RunNoShowJob(ctx context.Context, grace, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			noShows, err := r.Reservation.MarkNoShows(ctx, grace)
+			if err != nil {
+				r.Logger.Error("Failed no-show job", "error", err.Error())
+				continue
+			}
+			perRestaurant := make(map[string]int)
+			for _, n := range noShows {
+				perRestaurant[n.RestaurantId]++
+			}
+			for restaurantId, n := range perRestaurant {
+				r.Metrics.ReservationsNoShow(restaurantId, n)
+			}
+			if len(noShows) > 0 {
+				r.Logger.Info("No-show job finished", "reservations", len(noShows))
+			}
+		}
+	}
+}
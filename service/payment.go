@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	paymentpb "reservation-service/generated/payment_service"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Payment statuses used with the payment service.
+const (
+	// PaymentStatusFailed is reported by the payment service for declined payments.
+	PaymentStatusFailed            = "Failed"
+	PaymentStatusRefunded          = "Refunded"
+	PaymentStatusPartiallyRefunded = "PartiallyRefunded"
+)
+
+// PayReservation pays the amount due on the reservation's bill: its total
+// less a paid deposit. A payment of any other amount is rejected, and a bill
+// the deposit covers entirely is settled without charging again. A bill is
+// paid once, and only while the reservation is pending or confirmed. If the
+// reservation was paid, its bill changed or its promo code used up while the
+// payment was made, the payment is refunded.
+func (r *ReservationService) PayReservation(ctx context.Context, req *pb.MakePaymentRequest) (*pb.MakePaymentResponse, error) {
+	reservation, err := r.Reservation.GetReservation(ctx, &pb.GetReservationRequest{Id: req.ReservationId})
+	if err != nil {
+		return nil, err
+	}
+	if state := reservation.Reservation.Status; state != "Pending" && state != "Confirmed" {
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %s and cannot be paid", state)
+	}
+	bill, err := r.Reservation.GetBill(ctx, &pb.GetBillRequest{ReservationId: req.ReservationId})
+	if err != nil {
+		return nil, err
+	}
+	if bill.Bill.Paid {
+		return nil, status.Error(codes.FailedPrecondition, "reservation is already paid")
+	}
+	amount := bill.Bill.AmountDue
+	if req.Amount.GetMinorUnits() != amount.MinorUnits || (req.Amount.GetCurrency() != "" && req.Amount.Currency != amount.Currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "amount %s does not match the amount due of %s",
+			money.Format(req.Amount.GetMinorUnits(), req.Amount.GetCurrency()), money.Format(amount.MinorUnits, amount.Currency))
+	}
+	if amount.MinorUnits == 0 {
+		if bill.Bill.DepositPaid.GetMinorUnits() == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "nothing to pay: no meals were ordered")
+		}
+		deposit, err := r.Reservation.GetDeposit(ctx, req.ReservationId)
+		if err != nil {
+			return nil, err
+		}
+		err = r.Reservation.SetReservationPayment(ctx, req.ReservationId, deposit.PaymentId, amount, req.PaymentMethod)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.MakePaymentResponse{Status: "Paid"}, nil
+	}
+
+	res, err := r.Clients.Payment.CreatePayment(ctx, &paymentpb.CreatePaymentRequest{
+		ReservationId: req.ReservationId,
+		Amount:        money.ToMajor(amount.MinorUnits, amount.Currency),
+		PaymentMethod: req.PaymentMethod,
+		PaymentStatus: "Pending",
+	})
+	failed := err != nil || res.Payment.GetPaymentStatus() == PaymentStatusFailed
+	r.Metrics.PaymentAttempted(failed)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "payment service: %v", err)
+	}
+	if failed {
+		return &pb.MakePaymentResponse{Status: res.Payment.GetPaymentStatus()}, nil
+	}
+
+	payment := res.Payment
+	err = r.Reservation.SetReservationPayment(ctx, req.ReservationId, payment.GetId(), amount, req.PaymentMethod)
+	if status.Code(err) == codes.FailedPrecondition {
+		_, refundErr := r.Clients.Payment.UpdatePayment(ctx, &paymentpb.UpdatePaymentRequest{
+			Id:            payment.GetId(),
+			ReservationId: payment.GetReservationId(),
+			Amount:        0,
+			PaymentMethod: payment.GetPaymentMethod(),
+			PaymentStatus: PaymentStatusRefunded,
+		})
+		if refundErr != nil {
+			r.Logger.Error("Failed to refund rejected bill payment", "reservation_id", req.ReservationId,
+				"payment_id", payment.GetId(), "error", refundErr.Error())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MakePaymentResponse{Status: payment.GetPaymentStatus()}, nil
+}
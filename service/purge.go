@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	pb "reservation-service/generated/reservation_service"
+	"time"
+)
+
+// RunPurgeJob purges soft-deleted rows older than retentionDays every interval
+// until ctx is cancelled.
+func (r *ReservationService) RunPurgeJob(ctx context.Context, retentionDays int32, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := r.Reservation.PurgeDeleted(ctx, &pb.PurgeDeletedRequest{RetentionDays: retentionDays})
+			if err != nil {
+				r.Logger.Error("Failed purge job", "error", err.Error())
+				continue
+			}
+			r.Logger.Info("Purge job finished",
+				"restaurants", res.Restaurants,
+				"reservations", res.Reservations,
+				"menu_items", res.MenuItems,
+				"menu_categories", res.MenuCategories,
+				"reservation_orders", res.ReservationOrders)
+		}
+	}
+}
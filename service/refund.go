@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	paymentpb "reservation-service/generated/payment_service"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"reservation-service/storage/postgres"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RefundReservation refunds part or all of a reservation's payment. Only the
+// restaurant's staff may refund: staff within the cancellation policy and
+// managers up to the whole payment. Retrying with the same idempotency key
+// finishes or returns the original refund instead of refunding twice.
+func (r *ReservationService) RefundReservation(ctx context.Context, req *pb.RefundReservationRequest) (*pb.RefundReservationResponse, error) {
+	if req.IdempotencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "idempotency_key is required")
+	}
+	if req.Amount.GetMinorUnits() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must not be negative")
+	}
+	caller := callerID(ctx)
+	if caller == "" {
+		return nil, status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+
+	quote, err := r.Reservation.QuoteRefund(ctx, req.ReservationId, req.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount.GetCurrency() != "" && req.Amount.Currency != quote.Currency {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be in the payment's currency %s", quote.Currency)
+	}
+	role, err := r.Reservation.StaffRole(ctx, quote.RestaurantId, caller)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, status.Error(codes.PermissionDenied, "only restaurant staff can refund reservations")
+	}
+
+	rec, err := r.Reservation.FindRefund(ctx, req.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		res, err := r.Clients.Payment.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: quote.PaymentId})
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "payment service: %v", err)
+		}
+		balance := money.FromMajor(res.Payment.GetAmount(), quote.Currency)
+		limit := quote.Limit(balance, role == postgres.RoleManager)
+
+		amount := req.Amount.GetMinorUnits()
+		if amount == 0 {
+			amount = limit
+		}
+		if amount == 0 {
+			return nil, status.Error(codes.FailedPrecondition, "nothing left to refund")
+		}
+		if amount > limit {
+			if role == postgres.RoleManager {
+				return nil, status.Errorf(codes.FailedPrecondition, "only %s of the payment is left to refund", money.Format(limit, quote.Currency))
+			}
+			return nil, status.Errorf(codes.PermissionDenied, "the cancellation policy allows refunding at most %s", money.Format(limit, quote.Currency))
+		}
+
+		rec, err = r.Reservation.BeginRefund(ctx, &pb.Refund{
+			ReservationId:  req.ReservationId,
+			PaymentId:      quote.PaymentId,
+			Amount:         &pb.Money{MinorUnits: amount, Currency: quote.Currency},
+			Reason:         req.Reason,
+			RequestedBy:    caller,
+			IdempotencyKey: req.IdempotencyKey,
+		}, balance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rec.Refund.ReservationId != req.ReservationId {
+		return nil, status.Error(codes.InvalidArgument, "idempotency_key was already used for another reservation")
+	}
+
+	refund, err := r.issueRefund(ctx, rec)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RefundReservationResponse{Refund: refund}, nil
+}
+
+// refundUpcomingPayments refunds in full the payments of a restaurant's
+// upcoming reservations before a forced delete or deactivation cancels them.
+// Each refund is recorded under an idempotency key, so a failed call can be
+// retried without refunding twice.
+func (r *ReservationService) refundUpcomingPayments(ctx context.Context, restaurantId string) error {
+	payments, err := r.Reservation.UpcomingPayments(ctx, restaurantId)
+	if err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		key := "restaurant-cancel:" + payment.ReservationId + ":" + payment.PaymentId
+		rec, err := r.Reservation.FindRefund(ctx, key)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			res, err := r.Clients.Payment.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: payment.PaymentId})
+			if err != nil {
+				return status.Errorf(codes.Unavailable, "payment service: %v", err)
+			}
+			balance := money.FromMajor(res.Payment.GetAmount(), payment.Currency)
+			if balance == 0 {
+				continue
+			}
+			rec, err = r.Reservation.BeginRefund(ctx, &pb.Refund{
+				ReservationId:  payment.ReservationId,
+				PaymentId:      payment.PaymentId,
+				Amount:         &pb.Money{MinorUnits: balance, Currency: payment.Currency},
+				Reason:         "Reservation cancelled by the restaurant",
+				RequestedBy:    callerID(ctx),
+				IdempotencyKey: key,
+			}, balance)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err = r.issueRefund(ctx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueRefund reduces the payment by a pending refund and completes it. The
+// payment is set to an absolute amount, so issuing the same refund again is
+// harmless.
+func (r *ReservationService) issueRefund(ctx context.Context, rec *postgres.RefundRecord) (*pb.Refund, error) {
+	refund := rec.Refund
+	if refund.Status == postgres.RefundCompleted {
+		return refund, nil
+	}
+
+	res, err := r.Clients.Payment.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: refund.PaymentId})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "payment service: %v", err)
+	}
+	remaining := rec.BalanceBefore - refund.Amount.GetMinorUnits()
+	paymentStatus := PaymentStatusPartiallyRefunded
+	if remaining == 0 {
+		paymentStatus = PaymentStatusRefunded
+	}
+	_, err = r.Clients.Payment.UpdatePayment(ctx, &paymentpb.UpdatePaymentRequest{
+		Id:            refund.PaymentId,
+		ReservationId: res.Payment.GetReservationId(),
+		Amount:        money.ToMajor(remaining, refund.Amount.GetCurrency()),
+		PaymentMethod: res.Payment.GetPaymentMethod(),
+		PaymentStatus: paymentStatus,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "payment service: %v", err)
+	}
+	return r.Reservation.CompleteRefund(ctx, refund.Id)
+}
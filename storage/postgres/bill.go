@@ -0,0 +1,583 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceChargeName names the service charge on bills.
+const ServiceChargeName = "Service charge"
+
+// promoValid filters PromoCodes p down to codes that can still be redeemed.
+const promoValid = `p.active
+			AND (p.valid_until IS NULL OR p.valid_until > CURRENT_TIMESTAMP)
+			AND (p.max_redemptions IS NULL OR p.redemptions < p.max_redemptions)`
+
+// ComputeBill prices the lines and applies the promo code, service charge
+// and taxes in that order: the service charge is taken on the discounted
+// subtotal and taxes on the discounted subtotal plus service charge. Each
+// percentage is rounded half away from zero to whole minor units. The promo
+// code may be nil.
+func ComputeBill(lines []*pb.BillLine, settings *pb.BillingSettings, promo *pb.PromoCode, depositPaid int64, currency string) *pb.Bill {
+	amount := func(minor int64) *pb.Money {
+		return &pb.Money{MinorUnits: minor, Currency: currency}
+	}
+
+	var subtotal int64
+	for _, line := range lines {
+		line.Amount = amount(line.UnitPrice.GetMinorUnits() * int64(line.Quantity))
+		subtotal += line.Amount.MinorUnits
+	}
+
+	bill := &pb.Bill{Lines: lines, Subtotal: amount(subtotal), DepositPaid: amount(depositPaid)}
+	var discount int64
+	if promo != nil && subtotal > 0 && subtotal >= promo.MinSubtotal.GetMinorUnits() {
+		discount = promo.AmountOff.GetMinorUnits()
+		if promo.PercentOff > 0 {
+			discount = money.Percent(subtotal, promo.PercentOff)
+		}
+		discount = min(discount, subtotal)
+		bill.PromoCode = promo.Code
+	}
+	bill.Discount = amount(discount)
+
+	base := subtotal - discount
+	if settings.GetServiceChargePercent() > 0 {
+		charge := money.Percent(base, settings.ServiceChargePercent)
+		bill.ServiceCharge = &pb.BillCharge{
+			Name:    ServiceChargeName,
+			Percent: settings.ServiceChargePercent,
+			Amount:  amount(charge),
+		}
+		base += charge
+	}
+
+	total := base
+	for _, tax := range settings.GetTaxes() {
+		charge := money.Percent(base, tax.Percent)
+		bill.Taxes = append(bill.Taxes, &pb.BillCharge{Name: tax.Name, Percent: tax.Percent, Amount: amount(charge)})
+		total += charge
+	}
+	bill.Total = amount(total)
+	bill.AmountDue = amount(max(total-depositPaid, 0))
+	return bill
+}
+
+// loadBill gathers the ordered lines, billing settings, applied promo code and
+// paid deposit of a reservation and computes its bill. A paid bill has
+// nothing left due.
+func loadBill(ctx context.Context, q queryer, reservationId string) (*pb.Bill, error) {
+	var (
+		restaurantId, currency, promoId string
+		depositPaid                     int64
+		paid                            bool
+	)
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			r.restaurant_id,
+			rs.currency,
+			CASE WHEN r.deposit_paid_at IS NOT NULL THEN r.deposit_minor ELSE 0 END,
+			COALESCE(r.promo_code_id::text, ''),
+			r.payment_id IS NOT NULL
+		FROM
+			reservations r
+			JOIN Restaurants rs ON rs.id = r.restaurant_id
+		WHERE
+			r.id = $1 AND r.deleted_at = 0
+	`, reservationId).Scan(&restaurantId, &currency, &depositPaid, &promoId, &paid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to get bill: %v", err)
+	}
+
+	lines, err := loadBillLines(ctx, q, reservationId, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadBillingSettings(ctx, q, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	var promo *pb.PromoCode
+	if promoId != "" {
+		promo = &pb.PromoCode{AmountOff: &pb.Money{}, MinSubtotal: &pb.Money{}}
+		err = q.QueryRowContext(ctx, `
+			SELECT
+				p.code,
+				p.percent_off,
+				p.amount_off_minor,
+				p.min_subtotal_minor
+			FROM
+				PromoCodes p
+			WHERE
+				p.id = $1 AND `+promoValid+`
+		`, promoId).Scan(&promo.Code, &promo.PercentOff, &promo.AmountOff.MinorUnits, &promo.MinSubtotal.MinorUnits)
+		if errors.Is(err, sql.ErrNoRows) {
+			promo = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get promo code: %v", err)
+		}
+	}
+
+	bill := ComputeBill(lines, settings, promo, depositPaid, currency)
+	bill.ReservationId = reservationId
+	if paid {
+		bill.Paid = true
+		bill.AmountDue = &pb.Money{Currency: currency}
+	}
+	return bill, nil
+}
+
+// loadBillLines returns a line per ordered item and set of options, priced at
+// the item's price when it was ordered plus the price deltas its options were
+// ordered with.
+func loadBillLines(ctx context.Context, q queryer, reservationId, currency string) ([]*pb.BillLine, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			o.id,
+			o.menu_item_id,
+			m.name,
+			o.quantity,
+			o.unit_price_minor + COALESCE((
+				SELECT SUM(oo.price_delta_minor) FROM ReservationOrderOptions oo WHERE oo.order_id = o.id
+			), 0)
+		FROM
+			ReservationOrders o
+			JOIN Menu m ON m.id = o.menu_item_id
+		WHERE
+			o.reservation_id = $1 AND o.deleted_at = 0
+		ORDER BY
+			m.name, o.options_key
+	`, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bill lines: %v", err)
+	}
+	defer rows.Close()
+
+	var (
+		lines    []*pb.BillLine
+		orderIds []string
+		byOrder  = make(map[string]*pb.BillLine)
+	)
+	for rows.Next() {
+		var orderId string
+		line := &pb.BillLine{UnitPrice: &pb.Money{Currency: currency}}
+		if err := rows.Scan(&orderId, &line.MenuItemId, &line.Name, &line.Quantity, &line.UnitPrice.MinorUnits); err != nil {
+			return nil, fmt.Errorf("failed to scan bill line: %v", err)
+		}
+		lines = append(lines, line)
+		orderIds = append(orderIds, orderId)
+		byOrder[orderId] = line
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bill lines: %v", err)
+	}
+	if len(orderIds) == 0 {
+		return lines, nil
+	}
+
+	options, err := q.QueryContext(ctx, `
+		SELECT
+			order_id,
+			COALESCE(option_id::text, ''),
+			name,
+			price_delta_minor
+		FROM
+			ReservationOrderOptions
+		WHERE
+			order_id = ANY($1::uuid[])
+		ORDER BY
+			order_id, position
+	`, pq.Array(orderIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bill line options: %v", err)
+	}
+	defer options.Close()
+
+	for options.Next() {
+		var orderId string
+		option := &pb.ModifierOption{PriceDelta: &pb.Money{Currency: currency}}
+		if err := options.Scan(&orderId, &option.Id, &option.Name, &option.PriceDelta.MinorUnits); err != nil {
+			return nil, fmt.Errorf("failed to scan bill line option: %v", err)
+		}
+		line := byOrder[orderId]
+		line.Options = append(line.Options, option)
+	}
+	if err := options.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bill line options: %v", err)
+	}
+	return lines, nil
+}
+
+func (r *ReservationRepo) GetBill(ctx context.Context, req *pb.GetBillRequest) (_ *pb.GetBillResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetBill", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	bill, err := loadBill(ctx, r.DB, req.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetBillResponse{Bill: bill}, nil
+}
+
+// ApplyPromoCode applies a promo code of the reservation's restaurant to its
+// bill, replacing any applied before. The discount shows on the bill once the
+// subtotal reaches the code's minimum.
+func (r *ReservationRepo) ApplyPromoCode(ctx context.Context, req *pb.ApplyPromoCodeRequest) (_ *pb.ApplyPromoCodeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ApplyPromoCode", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply promo code: %v", err)
+	}
+	defer tx.Rollback()
+
+	var paid bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			payment_id IS NOT NULL
+		FROM
+			reservations
+		WHERE
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, req.ReservationId).Scan(&paid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to apply promo code: %v", err)
+	}
+	if paid {
+		return nil, status.Error(codes.FailedPrecondition, "reservation is already paid")
+	}
+
+	if req.Code == "" {
+		_, err = tx.ExecContext(ctx, `UPDATE reservations SET promo_code_id = NULL WHERE id = $1`, req.ReservationId)
+	} else {
+		var res sql.Result
+		res, err = tx.ExecContext(ctx, `
+			UPDATE
+				reservations r
+			SET
+				promo_code_id = p.id,
+				updated_at = CURRENT_TIMESTAMP
+			FROM
+				PromoCodes p
+			WHERE
+				r.id = $1
+				AND p.restaurant_id = r.restaurant_id
+				AND p.code = $2
+				AND `+promoValid+`
+		`, req.ReservationId, strings.ToUpper(req.Code))
+		if err == nil {
+			if n, _ := res.RowsAffected(); n == 0 {
+				return nil, status.Error(codes.NotFound, "promo code is not valid")
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply promo code: %v", err)
+	}
+
+	bill, err := loadBill(ctx, tx, req.ReservationId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to apply promo code: %v", err)
+	}
+	return &pb.ApplyPromoCodeResponse{Bill: bill}, nil
+}
+
+func loadBillingSettings(ctx context.Context, q queryer, restaurantId string) (*pb.BillingSettings, error) {
+	settings := &pb.BillingSettings{RestaurantId: restaurantId}
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			service_charge_percent
+		FROM
+			BillingSettings
+		WHERE
+			restaurant_id = $1
+	`, restaurantId).Scan(&settings.ServiceChargePercent)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get billing settings: %v", err)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			name,
+			percent
+		FROM
+			RestaurantTaxes
+		WHERE
+			restaurant_id = $1
+		ORDER BY
+			name
+	`, restaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get taxes: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tax := &pb.Tax{}
+		if err := rows.Scan(&tax.Name, &tax.Percent); err != nil {
+			return nil, fmt.Errorf("failed to scan tax: %v", err)
+		}
+		settings.Taxes = append(settings.Taxes, tax)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get taxes: %v", err)
+	}
+	return settings, nil
+}
+
+func (r *ReservationRepo) GetBillingSettings(ctx context.Context, req *pb.GetBillingSettingsRequest) (_ *pb.GetBillingSettingsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetBillingSettings", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	settings, err := loadBillingSettings(ctx, r.DB, req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetBillingSettingsResponse{Settings: settings}, nil
+}
+
+// SetBillingSettings replaces the restaurant's service charge and taxes.
+func (r *ReservationRepo) SetBillingSettings(ctx context.Context, req *pb.SetBillingSettingsRequest) (_ *pb.SetBillingSettingsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetBillingSettings", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetSettings()
+	if in.GetRestaurantId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "settings.restaurant_id is required")
+	}
+	if in.ServiceChargePercent < 0 || in.ServiceChargePercent > 100 {
+		return nil, status.Error(codes.InvalidArgument, "service_charge_percent must be between 0 and 100")
+	}
+	for _, tax := range in.Taxes {
+		if tax.Name == "" {
+			return nil, status.Error(codes.InvalidArgument, "tax name is required")
+		}
+		if tax.Percent <= 0 || tax.Percent > 100 {
+			return nil, status.Errorf(codes.InvalidArgument, "percent of tax %q must be above 0 and at most 100", tax.Name)
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set billing settings: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO BillingSettings (
+			restaurant_id,
+			service_charge_percent
+		)
+		SELECT
+			id,
+			$2
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		ON CONFLICT (restaurant_id) DO UPDATE SET
+			service_charge_percent = EXCLUDED.service_charge_percent,
+			updated_at = CURRENT_TIMESTAMP
+	`, in.RestaurantId, in.ServiceChargePercent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set billing settings: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "restaurant not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM RestaurantTaxes WHERE restaurant_id = $1`, in.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set taxes: %v", err)
+	}
+	for _, tax := range in.Taxes {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO RestaurantTaxes (
+				restaurant_id,
+				name,
+				percent
+			)
+			VALUES (
+				$1,
+				$2,
+				$3
+			)
+		`, in.RestaurantId, tax.Name, tax.Percent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set taxes: %v", err)
+		}
+	}
+
+	settings, err := loadBillingSettings(ctx, tx, in.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set billing settings: %v", err)
+	}
+	return &pb.SetBillingSettingsResponse{Settings: settings}, nil
+}
+
+// SetPromoCode creates a promo code or replaces the one with the same code at
+// the restaurant. Codes are case-insensitive.
+func (r *ReservationRepo) SetPromoCode(ctx context.Context, req *pb.SetPromoCodeRequest) (_ *pb.SetPromoCodeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetPromoCode", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetPromoCode()
+	if err = validatePromoCode(in); err != nil {
+		return nil, err
+	}
+	if err = checkMoney(ctx, r.DB, in.RestaurantId, "amount_off", in.AmountOff); err != nil {
+		return nil, err
+	}
+	if err = checkMoney(ctx, r.DB, in.RestaurantId, "min_subtotal", in.MinSubtotal); err != nil {
+		return nil, err
+	}
+
+	promo, err := scanPromoCode(r.DB.QueryRowContext(ctx, `
+		INSERT INTO PromoCodes (
+			restaurant_id,
+			code,
+			percent_off,
+			amount_off_minor,
+			min_subtotal_minor,
+			valid_until,
+			max_redemptions,
+			active
+		)
+		SELECT
+			id,
+			$2,
+			$3,
+			$4,
+			$5,
+			NULLIF($6, '')::timestamp,
+			NULLIF($7, 0),
+			$8
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		ON CONFLICT (restaurant_id, code) DO UPDATE SET
+			percent_off = EXCLUDED.percent_off,
+			amount_off_minor = EXCLUDED.amount_off_minor,
+			min_subtotal_minor = EXCLUDED.min_subtotal_minor,
+			valid_until = EXCLUDED.valid_until,
+			max_redemptions = EXCLUDED.max_redemptions,
+			active = EXCLUDED.active,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING
+			`+promoCodeColumns+`
+	`, in.RestaurantId, strings.ToUpper(in.Code), in.PercentOff, in.AmountOff.GetMinorUnits(), in.MinSubtotal.GetMinorUnits(),
+		in.ValidUntil, in.MaxRedemptions, in.Active))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		return nil, fmt.Errorf("failed to set promo code: %v", err)
+	}
+	return &pb.SetPromoCodeResponse{PromoCode: promo}, nil
+}
+
+func (r *ReservationRepo) ListPromoCodes(ctx context.Context, req *pb.ListPromoCodesRequest) (_ *pb.ListPromoCodesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListPromoCodes", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			`+promoCodeColumns+`
+		FROM
+			PromoCodes
+		WHERE
+			restaurant_id = $1
+		ORDER BY
+			code
+	`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %v", err)
+	}
+	defer rows.Close()
+
+	var promos []*pb.PromoCode
+	for rows.Next() {
+		promo, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promo code: %v", err)
+		}
+		promos = append(promos, promo)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %v", err)
+	}
+	return &pb.ListPromoCodesResponse{PromoCodes: promos}, nil
+}
+
+// promoCodeColumns lists the columns read by scanPromoCode.
+const promoCodeColumns = `id,
+			restaurant_id,
+			code,
+			percent_off,
+			amount_off_minor,
+			min_subtotal_minor,
+			(SELECT currency FROM Restaurants rs WHERE rs.id = restaurant_id),
+			COALESCE(valid_until::text, ''),
+			COALESCE(max_redemptions, 0),
+			redemptions,
+			active`
+
+func scanPromoCode(row rowScanner) (*pb.PromoCode, error) {
+	promo := &pb.PromoCode{AmountOff: &pb.Money{}, MinSubtotal: &pb.Money{}}
+	err := row.Scan(&promo.Id, &promo.RestaurantId, &promo.Code, &promo.PercentOff, &promo.AmountOff.MinorUnits,
+		&promo.MinSubtotal.MinorUnits, &promo.AmountOff.Currency, &promo.ValidUntil, &promo.MaxRedemptions,
+		&promo.Redemptions, &promo.Active)
+	promo.MinSubtotal.Currency = promo.AmountOff.Currency
+	return promo, err
+}
+
+func validatePromoCode(p *pb.PromoCode) error {
+	if p.GetRestaurantId() == "" || p.GetCode() == "" {
+		return status.Error(codes.InvalidArgument, "promo_code.restaurant_id and promo_code.code are required")
+	}
+	if (p.PercentOff > 0) == (p.AmountOff.GetMinorUnits() > 0) {
+		return status.Error(codes.InvalidArgument, "set exactly one of percent_off and amount_off")
+	}
+	if p.PercentOff < 0 || p.PercentOff > 100 {
+		return status.Error(codes.InvalidArgument, "percent_off must be between 0 and 100")
+	}
+	if p.MaxRedemptions < 0 {
+		return status.Error(codes.InvalidArgument, "max_redemptions must not be negative")
+	}
+	if p.ValidUntil != "" {
+		if _, err := time.Parse("2006-01-02 15:04:05", p.ValidUntil); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid valid_until %q, use YYYY-MM-DD HH:MM:SS", p.ValidUntil)
+		}
+	}
+	return nil
+}
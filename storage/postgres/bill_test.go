@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestComputeBill(t *testing.T) {
+	lines := func() []*pb.BillLine {
+		return []*pb.BillLine{
+			{MenuItemId: "a", Name: "Plov", Quantity: 2, UnitPrice: &pb.Money{MinorUnits: 1250, Currency: "USD"}},
+			{MenuItemId: "b", Name: "Tea", Quantity: 3, UnitPrice: &pb.Money{MinorUnits: 199, Currency: "USD"}},
+		}
+	}
+	settings := &pb.BillingSettings{
+		ServiceChargePercent: 10,
+		Taxes:                []*pb.Tax{{Name: "VAT", Percent: 12}},
+	}
+
+	bill := ComputeBill(lines(), settings, nil, 0, "USD")
+	assert.Equal(t, int64(597), bill.Lines[1].Amount.MinorUnits)
+	assert.Equal(t, int64(3097), bill.Subtotal.MinorUnits)
+	assert.Equal(t, int64(310), bill.ServiceCharge.Amount.MinorUnits)
+	assert.Equal(t, int64(409), bill.Taxes[0].Amount.MinorUnits)
+	assert.Equal(t, int64(3816), bill.Total.MinorUnits)
+	assert.Equal(t, int64(3816), bill.AmountDue.MinorUnits)
+	assert.Equal(t, "USD", bill.Total.Currency)
+
+	percent := &pb.PromoCode{Code: "TENOFF", PercentOff: 10}
+	bill = ComputeBill(lines(), settings, percent, 2000, "USD")
+	assert.Equal(t, "TENOFF", bill.PromoCode)
+	assert.Equal(t, int64(310), bill.Discount.MinorUnits)
+	assert.Equal(t, int64(3434), bill.Total.MinorUnits)
+	assert.Equal(t, int64(1434), bill.AmountDue.MinorUnits)
+
+	fixed := &pb.PromoCode{Code: "BIG", AmountOff: &pb.Money{MinorUnits: 5000}}
+	bill = ComputeBill(lines(), &pb.BillingSettings{}, fixed, 0, "USD")
+	assert.Equal(t, int64(3097), bill.Discount.MinorUnits)
+	assert.Equal(t, int64(0), bill.Total.MinorUnits)
+
+	minimum := &pb.PromoCode{Code: "MIN", AmountOff: &pb.Money{MinorUnits: 500}, MinSubtotal: &pb.Money{MinorUnits: 4000}}
+	bill = ComputeBill(lines(), &pb.BillingSettings{}, minimum, 0, "USD")
+	assert.Empty(t, bill.PromoCode)
+	assert.Equal(t, int64(3097), bill.Total.MinorUnits)
+
+	bill = ComputeBill(nil, settings, percent, 2500, "USD")
+	assert.Equal(t, int64(0), bill.Total.MinorUnits)
+	assert.Equal(t, int64(0), bill.AmountDue.MinorUnits)
+
+	// Half a minor unit rounds away from zero on every charge.
+	half := []*pb.BillLine{{MenuItemId: "c", Name: "Mint", Quantity: 1, UnitPrice: &pb.Money{MinorUnits: 5}}}
+	bill = ComputeBill(half, &pb.BillingSettings{ServiceChargePercent: 10, Taxes: []*pb.Tax{{Name: "VAT", Percent: 25}}}, nil, 0, "USD")
+	assert.Equal(t, int64(1), bill.ServiceCharge.Amount.MinorUnits)
+	assert.Equal(t, int64(2), bill.Taxes[0].Amount.MinorUnits)
+	assert.Equal(t, int64(8), bill.Total.MinorUnits)
+
+	bill = ComputeBill(lines(), settings, nil, 0, "JPY")
+	assert.Equal(t, "JPY", bill.AmountDue.Currency)
+}
+
+func TestBill(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	_, err = repo.SetBillingSettings(context.Background(), &pb.SetBillingSettingsRequest{
+		Settings: &pb.BillingSettings{RestaurantId: restaurantId, ServiceChargePercent: 120},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	settings, err := repo.SetBillingSettings(context.Background(), &pb.SetBillingSettingsRequest{
+		Settings: &pb.BillingSettings{
+			RestaurantId:         restaurantId,
+			ServiceChargePercent: 10,
+			Taxes:                []*pb.Tax{{Name: "VAT", Percent: 12}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, settings.Settings.Taxes, 1)
+
+	_, err = repo.SetPromoCode(context.Background(), &pb.SetPromoCodeRequest{
+		PromoCode: &pb.PromoCode{RestaurantId: restaurantId, Code: "welcome", PercentOff: 10, Active: true},
+	})
+	assert.NoError(t, err)
+
+	item, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Bill test dish",
+		Description:  "Priced for the bill test",
+		Price:        &pb.Money{MinorUnits: 2000},
+	})
+	assert.NoError(t, err)
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-01 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	err = saveTestOrder(&repo, res.Reservation.Id, restaurantId, item.MenuItem.Id, 3)
+	assert.NoError(t, err)
+
+	_, err = repo.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{ReservationId: res.Reservation.Id, Code: "nope"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	applied, err := repo.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{ReservationId: res.Reservation.Id, Code: "welcome"})
+	assert.NoError(t, err)
+	assert.Equal(t, "WELCOME", applied.Bill.PromoCode)
+
+	bill, err := repo.GetBill(context.Background(), &pb.GetBillRequest{ReservationId: res.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6000), bill.Bill.Subtotal.MinorUnits)
+	assert.Equal(t, int64(600), bill.Bill.Discount.MinorUnits)
+	assert.Equal(t, int64(6653), bill.Bill.Total.MinorUnits)
+
+	_, err = repo.SetBillingSettings(context.Background(), &pb.SetBillingSettingsRequest{
+		Settings: &pb.BillingSettings{RestaurantId: restaurantId},
+	})
+	assert.NoError(t, err)
+}
+
+func TestPayReservationOnce(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	book := func() string {
+		res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+			UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+			RestaurantId:    restaurantId,
+			ReservationTime: "2030-08-02 19:00:00",
+			Status:          "Confirmed",
+		})
+		assert.NoError(t, err)
+		return res.Reservation.Id
+	}
+	first, second, third := book(), book(), book()
+
+	// A payment for another amount than the bill comes to is refused, as
+	// when meals were ordered while it was being made.
+	err = repo.SetReservationPayment(context.Background(), third, "6e1b4f5a-7c8d-4e9f-9a0b-2c3d4e5f6a71", &pb.Money{MinorUnits: 500, Currency: "USD"}, "card")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	bill, err := repo.GetBill(context.Background(), &pb.GetBillRequest{ReservationId: third})
+	assert.NoError(t, err)
+	assert.False(t, bill.Bill.Paid)
+
+	code := "ONCE" + first[:8]
+	_, err = repo.SetPromoCode(context.Background(), &pb.SetPromoCodeRequest{
+		PromoCode: &pb.PromoCode{RestaurantId: restaurantId, Code: code, PercentOff: 10, MaxRedemptions: 1, Active: true},
+	})
+	assert.NoError(t, err)
+	for _, id := range []string{first, second} {
+		_, err = repo.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{ReservationId: id, Code: code})
+		assert.NoError(t, err)
+	}
+
+	amount := &pb.Money{MinorUnits: 0, Currency: "USD"}
+	err = repo.SetReservationPayment(context.Background(), first, "3b8e1c2d-4f5a-4b6c-8d7e-9f0a1b2c3d4e", amount, "card")
+	assert.NoError(t, err)
+	bill, err = repo.GetBill(context.Background(), &pb.GetBillRequest{ReservationId: first})
+	assert.NoError(t, err)
+	assert.True(t, bill.Bill.Paid)
+	assert.Equal(t, int64(0), bill.Bill.AmountDue.MinorUnits)
+
+	// Paying again neither replaces the payment nor redeems the code twice.
+	err = repo.SetReservationPayment(context.Background(), first, "4c9f2d3e-5a6b-4c7d-9e8f-0a1b2c3d4e5f", amount, "card")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = repo.OrderMeals(context.Background(), &pb.OrderMealsRequest{ReservationId: first})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The code's only redemption is taken.
+	err = repo.SetReservationPayment(context.Background(), second, "5d0a3e4f-6b7c-4d8e-8f9a-1b2c3d4e5f60", amount, "card")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func saveTestOrder(repo *ReservationRepo, reservationId, restaurantId, menuItemId string, quantity int32) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = saveOrderLines(context.Background(), tx, reservationId, restaurantId, []*pb.MealOrder{{MenuItemId: menuItemId, Quantity: quantity}})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
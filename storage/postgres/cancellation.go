@@ -0,0 +1,351 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Cancellation rules recorded in AppliedCancellationPolicy.Rule.
+const (
+	CancellationNoPolicy      = "no_policy"
+	CancellationFree          = "free"
+	CancellationLateFee       = "late_fee"
+	CancellationNonRefundable = "non_refundable"
+)
+
+const slotTimeLayout = "15:04"
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// EvaluateCancellation decides what cancelling a reservation at
+// reservationTime costs when it is until away. A nil policy is free.
+func EvaluateCancellation(policy *pb.CancellationPolicy, reservationTime time.Time, until time.Duration) *pb.AppliedCancellationPolicy {
+	if policy == nil {
+		return &pb.AppliedCancellationPolicy{Rule: CancellationNoPolicy}
+	}
+
+	applied := &pb.AppliedCancellationPolicy{FreeUntilHours: policy.FreeUntilHours}
+	clock := reservationTime.Format(slotTimeLayout)
+	for _, slot := range policy.NonRefundableSlots {
+		if slot.DayOfWeek >= 0 && time.Weekday(slot.DayOfWeek) != reservationTime.Weekday() {
+			continue
+		}
+		if clock >= slot.StartTime && clock < slot.EndTime {
+			applied.Rule = CancellationNonRefundable
+			applied.FeePercent = 100
+			return applied
+		}
+	}
+
+	if until >= time.Duration(policy.FreeUntilHours)*time.Hour {
+		applied.Rule = CancellationFree
+		return applied
+	}
+	applied.Rule = CancellationLateFee
+	applied.FeePercent = policy.LateFeePercent
+	return applied
+}
+
+// loadCancellationPolicy returns the restaurant's policy, or nil if it has none.
+func loadCancellationPolicy(ctx context.Context, q queryer, restaurantId string) (*pb.CancellationPolicy, error) {
+	policy := &pb.CancellationPolicy{RestaurantId: restaurantId}
+	err := q.QueryRowContext(ctx, `
+		SELECT
+			free_until_hours,
+			late_fee_percent
+		FROM
+			CancellationPolicies
+		WHERE
+			restaurant_id = $1
+	`, restaurantId).Scan(&policy.FreeUntilHours, &policy.LateFeePercent)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cancellation policy: %v", err)
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			COALESCE(day_of_week, -1),
+			to_char(start_time, 'HH24:MI'),
+			to_char(end_time, 'HH24:MI')
+		FROM
+			NonRefundableSlots
+		WHERE
+			restaurant_id = $1
+		ORDER BY
+			day_of_week NULLS FIRST, start_time
+	`, restaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get non-refundable slots: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		slot := &pb.CancellationSlot{}
+		if err := rows.Scan(&slot.DayOfWeek, &slot.StartTime, &slot.EndTime); err != nil {
+			return nil, fmt.Errorf("failed to scan non-refundable slot: %v", err)
+		}
+		policy.NonRefundableSlots = append(policy.NonRefundableSlots, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get non-refundable slots: %v", err)
+	}
+	return policy, nil
+}
+
+func (r *ReservationRepo) GetCancellationPolicy(ctx context.Context, req *pb.GetCancellationPolicyRequest) (_ *pb.GetCancellationPolicyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetCancellationPolicy", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	policy, err := loadCancellationPolicy(ctx, r.DB, req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &pb.CancellationPolicy{RestaurantId: req.RestaurantId}
+	}
+	return &pb.GetCancellationPolicyResponse{Policy: policy}, nil
+}
+
+// SetCancellationPolicy replaces the restaurant's policy and its
+// non-refundable slots.
+func (r *ReservationRepo) SetCancellationPolicy(ctx context.Context, req *pb.SetCancellationPolicyRequest) (_ *pb.SetCancellationPolicyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetCancellationPolicy", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetPolicy()
+	if err = validateCancellationPolicy(in); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set cancellation policy: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO CancellationPolicies (
+			restaurant_id,
+			free_until_hours,
+			late_fee_percent
+		)
+		SELECT
+			id,
+			$2,
+			$3
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		ON CONFLICT (restaurant_id) DO UPDATE SET
+			free_until_hours = EXCLUDED.free_until_hours,
+			late_fee_percent = EXCLUDED.late_fee_percent,
+			updated_at = CURRENT_TIMESTAMP
+	`, in.RestaurantId, in.FreeUntilHours, in.LateFeePercent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set cancellation policy: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "restaurant not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM NonRefundableSlots WHERE restaurant_id = $1`, in.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set non-refundable slots: %v", err)
+	}
+	for _, slot := range in.NonRefundableSlots {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO NonRefundableSlots (
+				restaurant_id,
+				day_of_week,
+				start_time,
+				end_time
+			)
+			VALUES (
+				$1,
+				NULLIF($2, -1),
+				$3,
+				$4
+			)
+		`, in.RestaurantId, slot.DayOfWeek, slot.StartTime, slot.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set non-refundable slots: %v", err)
+		}
+	}
+
+	policy, err := loadCancellationPolicy(ctx, tx, in.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set cancellation policy: %v", err)
+	}
+	return &pb.SetCancellationPolicyResponse{Policy: policy}, nil
+}
+
+func validateCancellationPolicy(p *pb.CancellationPolicy) error {
+	if p.GetRestaurantId() == "" {
+		return status.Error(codes.InvalidArgument, "policy.restaurant_id is required")
+	}
+	if p.FreeUntilHours < 0 {
+		return status.Error(codes.InvalidArgument, "free_until_hours must not be negative")
+	}
+	if p.LateFeePercent < 0 || p.LateFeePercent > 100 {
+		return status.Error(codes.InvalidArgument, "late_fee_percent must be between 0 and 100")
+	}
+	for _, slot := range p.NonRefundableSlots {
+		if slot.DayOfWeek < -1 || slot.DayOfWeek > 6 {
+			return status.Error(codes.InvalidArgument, "day_of_week must be between 0 and 6, or -1 for every day")
+		}
+		start, err := time.Parse(slotTimeLayout, slot.StartTime)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid start_time %q, use HH:MM", slot.StartTime)
+		}
+		end, err := time.Parse(slotTimeLayout, slot.EndTime)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid end_time %q, use HH:MM", slot.EndTime)
+		}
+		if !end.After(start) {
+			return status.Error(codes.InvalidArgument, "end_time must be after start_time")
+		}
+	}
+	return nil
+}
+
+// CancellationQuote is what cancelling a reservation would cost under its
+// restaurant's current policy.
+type CancellationQuote struct {
+	ReservationId string
+	RestaurantId  string
+	// PaymentIds are the bill and deposit payments the reservation was paid
+	// with, each refunded under the policy.
+	PaymentIds []string
+	Currency   string
+	Applied    *pb.AppliedCancellationPolicy
+}
+
+// QuoteCancellation evaluates the cancellation policy for a reservation that
+// is still pending or confirmed and has not started yet.
+func (r *ReservationRepo) QuoteCancellation(ctx context.Context, id string) (_ *CancellationQuote, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.QuoteCancellation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		quote                = &CancellationQuote{ReservationId: id}
+		state                string
+		billPayment, deposit string
+		reservationTime      time.Time
+		secondsUntil         float64
+	)
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			restaurant_id,
+			status,
+			reservation_time,
+			COALESCE(payment_id::text, ''),
+			COALESCE(deposit_payment_id::text, ''),
+			(SELECT currency FROM Restaurants rs WHERE rs.id = restaurant_id),
+			EXTRACT(EPOCH FROM reservation_time - CURRENT_TIMESTAMP)
+		FROM
+			reservations
+		WHERE
+			id = $1 AND deleted_at = 0
+	`, id).Scan(&quote.RestaurantId, &state, &reservationTime, &billPayment, &deposit, &quote.Currency, &secondsUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to quote cancellation: %v", err)
+	}
+	quote.PaymentIds = paymentIds(billPayment, deposit)
+	if state != "Pending" && state != "Confirmed" {
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %s and cannot be cancelled", state)
+	}
+	if secondsUntil <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "reservation has already started")
+	}
+
+	policy, err := loadCancellationPolicy(ctx, r.DB, quote.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	quote.Applied = EvaluateCancellation(policy, reservationTime, time.Duration(secondsUntil*float64(time.Second)))
+	return quote, nil
+}
+
+// ApplyCancellation cancels the reservation and records the applied policy.
+func (r *ReservationRepo) ApplyCancellation(ctx context.Context, id, reason string, applied *pb.AppliedCancellationPolicy) (_ *pb.Reservation, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ApplyCancellation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	policy, err := protojson.Marshal(applied)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cancellation policy: %v", err)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel reservation: %v", err)
+	}
+	defer tx.Rollback()
+
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, `
+		UPDATE
+			reservations
+		SET
+			status = 'Cancelled',
+			cancellation_reason = NULLIF($2, ''),
+			cancellation_policy = $3,
+			cancellation_fee_minor = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0 AND status IN ('Pending', 'Confirmed')
+		RETURNING
+			`+reservationColumns+`
+	`, id, reason, policy, applied.Fee.GetMinorUnits()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.FailedPrecondition, "reservation cannot be cancelled")
+		}
+		return nil, fmt.Errorf("failed to cancel reservation: %v", err)
+	}
+	if err = releaseMenuStock(ctx, tx, reservation.Id); err != nil {
+		return nil, err
+	}
+
+	err = addOutboxEvent(ctx, tx, "reservation", reservation.Id, EventReservationCancelled, ReservationCancelledEvent{
+		ReservationId:   reservation.Id,
+		UserId:          reservation.UserId,
+		RestaurantId:    reservation.RestaurantId,
+		ReservationTime: reservation.ReservationTime,
+		Reason:          reservation.CancellationReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = queueNotification(ctx, tx, reservation.Id, NotificationCancellation); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to cancel reservation: %v", err)
+	}
+	return reservation, nil
+}
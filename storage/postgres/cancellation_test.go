@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEvaluateCancellation(t *testing.T) {
+	policy := &pb.CancellationPolicy{
+		FreeUntilHours: 24,
+		LateFeePercent: 50,
+		NonRefundableSlots: []*pb.CancellationSlot{
+			{DayOfWeek: int32(time.Friday), StartTime: "18:00", EndTime: "22:00"},
+			{DayOfWeek: -1, StartTime: "23:00", EndTime: "23:59"},
+		},
+	}
+	tuesday := time.Date(2030, 5, 7, 19, 0, 0, 0, time.UTC)
+	friday := time.Date(2030, 5, 10, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		policy *pb.CancellationPolicy
+		at     time.Time
+		until  time.Duration
+		rule   string
+		fee    int32
+	}{
+		{"no policy", nil, tuesday, time.Hour, CancellationNoPolicy, 0},
+		{"before deadline", policy, tuesday, 48 * time.Hour, CancellationFree, 0},
+		{"on deadline", policy, tuesday, 24 * time.Hour, CancellationFree, 0},
+		{"after deadline", policy, tuesday, 3 * time.Hour, CancellationLateFee, 50},
+		{"peak slot", policy, friday, 72 * time.Hour, CancellationNonRefundable, 100},
+		{"slot end is exclusive", policy, friday.Add(3 * time.Hour), 72 * time.Hour, CancellationFree, 0},
+		{"every day slot", policy, tuesday.Add(4*time.Hour + 30*time.Minute), 72 * time.Hour, CancellationNonRefundable, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := EvaluateCancellation(tt.policy, tt.at, tt.until)
+			assert.Equal(t, tt.rule, applied.Rule)
+			assert.Equal(t, tt.fee, applied.FeePercent)
+		})
+	}
+}
+
+func TestCancellationPolicy(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	_, err = repo.SetCancellationPolicy(context.Background(), &pb.SetCancellationPolicyRequest{
+		Policy: &pb.CancellationPolicy{RestaurantId: restaurantId, LateFeePercent: 150},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err := repo.SetCancellationPolicy(context.Background(), &pb.SetCancellationPolicyRequest{
+		Policy: &pb.CancellationPolicy{
+			RestaurantId:       restaurantId,
+			FreeUntilHours:     12,
+			LateFeePercent:     25,
+			NonRefundableSlots: []*pb.CancellationSlot{{DayOfWeek: 5, StartTime: "18:00", EndTime: "22:00"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(12), resp.Policy.FreeUntilHours)
+	assert.Len(t, resp.Policy.NonRefundableSlots, 1)
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: time.Now().Add(2 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	quote, err := repo.QuoteCancellation(context.Background(), res.Reservation.Id)
+	assert.NoError(t, err)
+	assert.Contains(t, []string{CancellationLateFee, CancellationNonRefundable}, quote.Applied.Rule)
+
+	cancelled, err := repo.ApplyCancellation(context.Background(), res.Reservation.Id, "Plans changed", quote.Applied)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cancelled", cancelled.Status)
+
+	_, err = repo.QuoteCancellation(context.Background(), res.Reservation.Id)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = repo.SetCancellationPolicy(context.Background(), &pb.SetCancellationPolicyRequest{
+		Policy: &pb.CancellationPolicy{RestaurantId: restaurantId},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,392 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DepositExpiredReason is the cancellation reason of reservations whose
+// deposit was not paid in time.
+const DepositExpiredReason = "Deposit not paid in time"
+
+// EvaluateDeposit returns the deposit per person asked for a party of
+// partySize at reservationTime: the highest amount among the matching rules,
+// or 0 if none match.
+func EvaluateDeposit(rules []*pb.DepositRule, reservationTime time.Time, partySize int32) int64 {
+	var perPerson int64
+	clock := reservationTime.Format(slotTimeLayout)
+	for _, rule := range rules {
+		if partySize < rule.MinPartySize {
+			continue
+		}
+		if rule.DayOfWeek >= 0 && time.Weekday(rule.DayOfWeek) != reservationTime.Weekday() {
+			continue
+		}
+		if rule.StartTime != "" && (clock < rule.StartTime || clock >= rule.EndTime) {
+			continue
+		}
+		perPerson = max(perPerson, rule.AmountPerPerson.GetMinorUnits())
+	}
+	return perPerson
+}
+
+// depositFor computes the deposit of a new reservation. A guest the no-show
+// policy asks a deposit from pays the restaurant's highest rate even when no
+// rule matches the booking.
+func depositFor(ctx context.Context, tx *sql.Tx, restaurantId, reservationTime string, partySize int32, required bool) (int64, error) {
+	rules, err := loadDepositRules(ctx, tx, restaurantId)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	var at time.Time
+	if err := tx.QueryRowContext(ctx, `SELECT $1::timestamp`, reservationTime).Scan(&at); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid reservation_time %q", reservationTime)
+	}
+	perPerson := EvaluateDeposit(rules, at, partySize)
+	if perPerson == 0 && required {
+		for _, rule := range rules {
+			perPerson = max(perPerson, rule.AmountPerPerson.GetMinorUnits())
+		}
+	}
+	return perPerson * int64(partySize), nil
+}
+
+func loadDepositRules(ctx context.Context, q queryer, restaurantId string) ([]*pb.DepositRule, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			id,
+			amount_per_person_minor,
+			(SELECT currency FROM Restaurants rs WHERE rs.id = restaurant_id),
+			COALESCE(day_of_week, -1),
+			COALESCE(to_char(start_time, 'HH24:MI'), ''),
+			COALESCE(to_char(end_time, 'HH24:MI'), ''),
+			min_party_size
+		FROM
+			DepositRules
+		WHERE
+			restaurant_id = $1
+		ORDER BY
+			day_of_week NULLS FIRST, start_time NULLS FIRST, min_party_size
+	`, restaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposit rules: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []*pb.DepositRule
+	for rows.Next() {
+		rule := &pb.DepositRule{AmountPerPerson: &pb.Money{}}
+		err := rows.Scan(&rule.Id, &rule.AmountPerPerson.MinorUnits, &rule.AmountPerPerson.Currency, &rule.DayOfWeek,
+			&rule.StartTime, &rule.EndTime, &rule.MinPartySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deposit rule: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get deposit rules: %v", err)
+	}
+	return rules, nil
+}
+
+func (r *ReservationRepo) ListDepositRules(ctx context.Context, req *pb.ListDepositRulesRequest) (_ *pb.ListDepositRulesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListDepositRules", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rules, err := loadDepositRules(ctx, r.DB, req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListDepositRulesResponse{Rules: rules}, nil
+}
+
+// SetDepositRules replaces the restaurant's deposit rules. An empty list
+// turns deposits off, which is refused while the no-show policy asks for
+// deposits.
+func (r *ReservationRepo) SetDepositRules(ctx context.Context, req *pb.SetDepositRulesRequest) (_ *pb.SetDepositRulesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetDepositRules", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.RestaurantId == "" {
+		return nil, status.Error(codes.InvalidArgument, "restaurant_id is required")
+	}
+	for _, rule := range req.Rules {
+		if err = validateDepositRule(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM Restaurants WHERE id = $1 AND deleted_at = 0)
+	`, req.RestaurantId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, "restaurant not found")
+	}
+	if len(req.Rules) == 0 {
+		var asksDeposit bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM NoShowPolicies WHERE restaurant_id = $1 AND action = $2)
+		`, req.RestaurantId, NoShowActionDeposit).Scan(&asksDeposit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+		}
+		if asksDeposit {
+			return nil, status.Error(codes.FailedPrecondition, "the no-show policy asks for deposits, change it before removing every rule")
+		}
+	}
+	for _, rule := range req.Rules {
+		if err = checkMoney(ctx, tx, req.RestaurantId, "amount_per_person", rule.AmountPerPerson); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM DepositRules WHERE restaurant_id = $1`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+	}
+	for _, rule := range req.Rules {
+		minPartySize := rule.MinPartySize
+		if minPartySize == 0 {
+			minPartySize = 1
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO DepositRules (
+				restaurant_id,
+				amount_per_person_minor,
+				day_of_week,
+				start_time,
+				end_time,
+				min_party_size
+			)
+			VALUES (
+				$1,
+				$2,
+				NULLIF($3, -1),
+				NULLIF($4, '')::time,
+				NULLIF($5, '')::time,
+				$6
+			)
+		`, req.RestaurantId, rule.AmountPerPerson.GetMinorUnits(), rule.DayOfWeek, rule.StartTime, rule.EndTime, minPartySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+		}
+	}
+
+	rules, err := loadDepositRules(ctx, tx, req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set deposit rules: %v", err)
+	}
+	return &pb.SetDepositRulesResponse{Rules: rules}, nil
+}
+
+func validateDepositRule(rule *pb.DepositRule) error {
+	if rule.AmountPerPerson.GetMinorUnits() <= 0 {
+		return status.Error(codes.InvalidArgument, "amount_per_person must be positive")
+	}
+	if rule.DayOfWeek < -1 || rule.DayOfWeek > 6 {
+		return status.Error(codes.InvalidArgument, "day_of_week must be between 0 and 6, or -1 for every day")
+	}
+	if rule.MinPartySize < 0 {
+		return status.Error(codes.InvalidArgument, "min_party_size must not be negative")
+	}
+	if rule.StartTime == "" && rule.EndTime == "" {
+		return nil
+	}
+	start, err := time.Parse(slotTimeLayout, rule.StartTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid start_time %q, use HH:MM", rule.StartTime)
+	}
+	end, err := time.Parse(slotTimeLayout, rule.EndTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid end_time %q, use HH:MM", rule.EndTime)
+	}
+	if !end.After(start) {
+		return status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	}
+	return nil
+}
+
+// Deposit is the deposit held for a reservation.
+type Deposit struct {
+	ReservationId string
+	Status        string
+	Amount        int64
+	Currency      string
+	PaymentId     string
+	Paid          bool
+}
+
+// GetDeposit returns the deposit of a reservation in minor units of the
+// restaurant's currency. Amount is 0 if none is asked.
+func (r *ReservationRepo) GetDeposit(ctx context.Context, reservationId string) (_ *Deposit, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetDeposit", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	deposit := &Deposit{ReservationId: reservationId}
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			status,
+			deposit_minor,
+			(SELECT currency FROM Restaurants rs WHERE rs.id = restaurant_id),
+			COALESCE(deposit_payment_id::text, ''),
+			deposit_paid_at IS NOT NULL
+		FROM
+			reservations
+		WHERE
+			id = $1 AND deleted_at = 0
+	`, reservationId).Scan(&deposit.Status, &deposit.Amount, &deposit.Currency, &deposit.PaymentId, &deposit.Paid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to get deposit: %v", err)
+	}
+	return deposit, nil
+}
+
+// SetDepositPaid links the deposit payment to a reservation held for it,
+// confirms the reservation and records a PaymentCompleted event. It fails
+// with FailedPrecondition if the hold was released meanwhile.
+func (r *ReservationRepo) SetDepositPaid(ctx context.Context, reservationId, paymentId string, amount *pb.Money, paymentMethod string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetDepositPaid", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set deposit payment: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE
+			reservations
+		SET
+			deposit_payment_id = $2,
+			deposit_paid_at = CURRENT_TIMESTAMP,
+			status = 'Confirmed',
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1
+			AND deleted_at = 0
+			AND status = 'Pending'
+			AND deposit_paid_at IS NULL
+	`, reservationId, paymentId)
+	if err != nil {
+		return fmt.Errorf("failed to set deposit payment: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return status.Error(codes.FailedPrecondition, "reservation is no longer held for a deposit")
+	}
+
+	err = addOutboxEvent(ctx, tx, "reservation", reservationId, EventPaymentCompleted, PaymentCompletedEvent{
+		ReservationId: reservationId,
+		PaymentId:     paymentId,
+		AmountMinor:   amount.GetMinorUnits(),
+		Currency:      amount.GetCurrency(),
+		PaymentMethod: paymentMethod,
+		Deposit:       true,
+	})
+	if err != nil {
+		return err
+	}
+	if err = queueNotification(ctx, tx, reservationId, NotificationChange); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to set deposit payment: %v", err)
+	}
+	return nil
+}
+
+// ExpireDepositHolds cancels pending reservations whose deposit was not paid
+// within ttl of booking, which frees their slot.
+func (r *ReservationRepo) ExpireDepositHolds(ctx context.Context, ttl time.Duration) (_ []ReservationCancelledEvent, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ExpireDepositHolds", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire deposit holds: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE
+			reservations
+		SET
+			status = 'Cancelled',
+			cancellation_reason = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			deleted_at = 0
+			AND status = 'Pending'
+			AND deposit_required
+			AND deposit_paid_at IS NULL
+			AND created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 millisecond'
+		RETURNING
+			id,
+			user_id,
+			restaurant_id,
+			reservation_time
+	`, ttl.Milliseconds(), DepositExpiredReason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire deposit holds: %v", err)
+	}
+
+	var expired []ReservationCancelledEvent
+	for rows.Next() {
+		event := ReservationCancelledEvent{Reason: DepositExpiredReason}
+		if err := rows.Scan(&event.ReservationId, &event.UserId, &event.RestaurantId, &event.ReservationTime); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expired deposit hold: %v", err)
+		}
+		expired = append(expired, event)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to expire deposit holds: %v", err)
+	}
+
+	for _, event := range expired {
+		if err = releaseMenuStock(ctx, tx, event.ReservationId); err != nil {
+			return nil, err
+		}
+		if err = addOutboxEvent(ctx, tx, "reservation", event.ReservationId, EventReservationCancelled, event); err != nil {
+			return nil, err
+		}
+		if err = queueNotification(ctx, tx, event.ReservationId, NotificationCancellation); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to expire deposit holds: %v", err)
+	}
+	return expired, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEvaluateDeposit(t *testing.T) {
+	rules := []*pb.DepositRule{
+		{AmountPerPerson: &pb.Money{MinorUnits: 1000}, DayOfWeek: -1, MinPartySize: 6},
+		{AmountPerPerson: &pb.Money{MinorUnits: 2500}, DayOfWeek: int32(time.Saturday), StartTime: "19:00", EndTime: "23:00", MinPartySize: 1},
+	}
+	tuesday := time.Date(2030, 5, 7, 20, 0, 0, 0, time.UTC)
+	saturday := time.Date(2030, 5, 11, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		partySize int32
+		want      int64
+	}{
+		{"small party", tuesday, 2, 0},
+		{"large party", tuesday, 8, 1000},
+		{"peak slot", saturday, 2, 2500},
+		{"highest rate wins", saturday, 8, 2500},
+		{"slot end is exclusive", saturday.Add(3 * time.Hour), 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EvaluateDeposit(rules, tt.at, tt.partySize))
+		})
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	_, err = repo.SetDepositRules(context.Background(), &pb.SetDepositRulesRequest{
+		RestaurantId: restaurantId,
+		Rules:        []*pb.DepositRule{{DayOfWeek: -1}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	rules, err := repo.SetDepositRules(context.Background(), &pb.SetDepositRulesRequest{
+		RestaurantId: restaurantId,
+		Rules:        []*pb.DepositRule{{AmountPerPerson: &pb.Money{MinorUnits: 1250}, DayOfWeek: -1, MinPartySize: 4}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rules.Rules, 1)
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: time.Now().Add(48 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+		PartySize:       4,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pending", res.Reservation.Status)
+	assert.True(t, res.Reservation.DepositRequired)
+	assert.Equal(t, int64(5000), res.Reservation.DepositAmount.MinorUnits)
+
+	update := &pb.UpdateReservationRequest{
+		Id:              res.Reservation.Id,
+		UserId:          res.Reservation.UserId,
+		RestaurantId:    restaurantId,
+		ReservationTime: res.Reservation.ReservationTime,
+		PartySize:       6,
+	}
+	updated, err := repo.UpdateReservation(context.Background(), update)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7500), updated.Reservation.DepositAmount.MinorUnits)
+	update.Status = "Confirmed"
+	_, err = repo.UpdateReservation(context.Background(), update)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	err = repo.SetDepositPaid(context.Background(), res.Reservation.Id, "3f2c9c1e-8a4e-4c8e-9d8a-3b1f6b1d2e4f", res.Reservation.DepositAmount, "card")
+	assert.NoError(t, err)
+
+	deposit, err := repo.GetDeposit(context.Background(), res.Reservation.Id)
+	assert.NoError(t, err)
+	assert.True(t, deposit.Paid)
+	assert.Equal(t, "Confirmed", deposit.Status)
+
+	err = repo.SetDepositPaid(context.Background(), res.Reservation.Id, "3f2c9c1e-8a4e-4c8e-9d8a-3b1f6b1d2e4f", res.Reservation.DepositAmount, "card")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Once the bill is paid too, cancelling and refunding cover both payments.
+	billPayment := "7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e5f"
+	err = repo.SetReservationPayment(context.Background(), res.Reservation.Id, billPayment, &pb.Money{}, "card")
+	assert.NoError(t, err)
+	cancellation, err := repo.QuoteCancellation(context.Background(), res.Reservation.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{billPayment, "3f2c9c1e-8a4e-4c8e-9d8a-3b1f6b1d2e4f"}, cancellation.PaymentIds)
+	refund, err := repo.QuoteRefund(context.Background(), res.Reservation.Id, "3f2c9c1e-8a4e-4c8e-9d8a-3b1f6b1d2e4f")
+	assert.NoError(t, err)
+	assert.Equal(t, "3f2c9c1e-8a4e-4c8e-9d8a-3b1f6b1d2e4f", refund.PaymentId)
+	_, err = repo.QuoteRefund(context.Background(), res.Reservation.Id, "00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = repo.SetDepositRules(context.Background(), &pb.SetDepositRulesRequest{RestaurantId: restaurantId})
+	assert.NoError(t, err)
+}
@@ -1,102 +1,224 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func (r *ReservationRepo) CreateMenuItem(menuItem *pb.CreateMenuItemRequest) (*pb.CreateMenuItemResponse, error) {
+func (r *ReservationRepo) CreateMenuItem(ctx context.Context, menuItem *pb.CreateMenuItemRequest) (_ *pb.CreateMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CreateMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if err = checkMoney(ctx, r.DB, menuItem.RestaurantId, "price", menuItem.Price); err != nil {
+		return nil, err
+	}
+	if err = checkMenuCategory(ctx, r.DB, menuItem.RestaurantId, menuItem.CategoryId); err != nil {
+		return nil, err
+	}
+	translations, err := normalizeTranslations(menuItem.Translations)
+	if err != nil {
+		return nil, err
+	}
 
-	menu := pb.MenuItem{}
-	err := r.DB.QueryRow(`
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	menu, err := scanMenuItem(tx.QueryRowContext(ctx, `
 		INSERT INTO Menu (
 			restaurant_id,
 			name,
 			description,
-			price
+			price_minor,
+			category_id,
+			position
 		)
 		VALUES(
 			$1,
 			$2,
 			$3,
-			$4
+			$4,
+			NULLIF($5, '')::uuid,
+			$6
 		)
 		returning
-			id,
-			restaurant_id,
-			name,
-			description,
-			price`,
-		menuItem.RestaurantId, menuItem.Name, menuItem.Description, menuItem.Price,
-	).Scan(&menu.Id, &menu.RestaurantId, &menu.Name, &menu.Description, &menu.Price)
+			`+menuItemColumns,
+		menuItem.RestaurantId, menuItem.Name, menuItem.Description, menuItem.Price.GetMinorUnits(),
+		menuItem.CategoryId, menuItem.Position,
+	))
+	if err != nil {
+		return nil, err
+	}
+	menu.Allergens, menu.DietaryTags, err = saveMenuItemTags(ctx, tx, menu.Id, menuItem.Allergens, menuItem.DietaryTags)
 	if err != nil {
 		return nil, err
 	}
+	if err = recordMenuPrice(ctx, tx, menu.Id); err != nil {
+		return nil, err
+	}
+	if err = saveTranslations(ctx, tx, menuItemTranslations, menu.Id, translations); err != nil {
+		return nil, err
+	}
+	if err = attachMenuTranslations(ctx, tx, menu); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &pb.CreateMenuItemResponse{
-		MenuItem: &menu,
+		MenuItem: menu,
 	}, nil
 }
 
-func (r *ReservationRepo) ListMenuItems(listMenu *pb.ListMenuItemsRequest) (*pb.ListMenuItemsResponse, error) {
+func (r *ReservationRepo) ListMenuItems(ctx context.Context, listMenu *pb.ListMenuItemsRequest) (_ *pb.ListMenuItemsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListMenuItems", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		params = make(map[string]interface{})
 		args   []interface{}
 		filter string
 	)
 
+	availableAt := time.Now()
+	if listMenu.AvailableAt != "" {
+		if availableAt, err = time.Parse(menuTimeLayout, listMenu.AvailableAt); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid available_at %q, use YYYY-MM-DD HH:MM:SS", listMenu.AvailableAt)
+		}
+	}
+
 	query := `SELECT
-					id,
-					restaurant_id,
-					name,
-					description,
-					price
+					`+menuItemColumns+`
 				FROM
 					Menu
 					WHERE
-						true `
+						deleted_at = 0
+						AND restaurant_id IN (
+							SELECT id FROM Restaurants WHERE deleted_at = 0 AND is_active
+						) `
 	if listMenu.RestaurantId != "" {
 		params["restaurant_id"] = listMenu.RestaurantId
-		filter += "AND restaurant_id = :restaurant_id"
+		filter += " AND restaurant_id = :restaurant_id "
 	}
 
 	if listMenu.Name != "" {
 		params["name"] = listMenu.Name
-		filter += "AND name = :name"
+		filter += " AND name = :name "
+	}
+
+	if listMenu.Price.GetMinorUnits() > 0 {
+		params["price"] = listMenu.Price.GetMinorUnits()
+		filter += " AND price_minor = :price "
+	}
+
+	if listMenu.CategoryId != "" {
+		params["category_id"] = listMenu.CategoryId
+		filter += " AND category_id = :category_id "
+	}
+
+	if include := normalizeTags(listMenu.IncludeTags); len(include) > 0 {
+		params["include_tags"] = pq.Array(include)
+		filter += ` AND (
+						SELECT COUNT(*) FROM MenuItemTags t WHERE t.menu_item_id = Menu.id AND t.tag_code = ANY(:include_tags::text[])
+					) = cardinality(:include_tags::text[]) `
+	}
+
+	if exclude := normalizeTags(listMenu.ExcludeTags); len(exclude) > 0 {
+		params["exclude_tags"] = pq.Array(exclude)
+		filter += ` AND NOT EXISTS (
+						SELECT 1 FROM MenuItemTags t WHERE t.menu_item_id = Menu.id AND t.tag_code = ANY(:exclude_tags::text[])
+					) `
 	}
 
-	if listMenu.Price > 0 {
-		params["price"] = listMenu.Price
-		filter += "AND price = :price"
+	if listMenu.HideUnavailable {
+		params["available_at"] = availableAt.Format(menuTimeLayout)
+		filter += ` AND NOT sold_out
+					AND (
+						NOT EXISTS (SELECT 1 FROM MenuItemAvailability a WHERE a.menu_item_id = Menu.id)
+						OR EXISTS (
+							SELECT 1 FROM MenuItemAvailability a
+							WHERE a.menu_item_id = Menu.id
+								AND a.day_of_week IN (-1, EXTRACT(DOW FROM :available_at::timestamp))
+								AND :available_at::timestamp::time >= a.start_time
+								AND :available_at::timestamp::time < a.end_time
+						)
+					)
+					AND (
+						daily_stock IS NULL
+						OR daily_stock > COALESCE((
+							SELECT s.ordered FROM MenuItemStock s
+							WHERE s.menu_item_id = Menu.id AND s.stock_date = :available_at::timestamp::date
+						), 0)
+					) `
 	}
 
+	// Items are listed category by category so that a page never splits
+	// the sections out of order.
+	filter += `
+				ORDER BY
+					(SELECT c.position FROM MenuCategories c WHERE c.id = Menu.category_id) NULLS LAST,
+					category_id,
+					position,
+					name `
+
 	if listMenu.Limit > 0{
 		params["limit"] = listMenu.Limit
-		filter += "AND limit = :limit"
+		filter += " LIMIT :limit "
 	}
 	if listMenu.Offset > 0{
 		params["offset"] = listMenu.Offset
-		filter += "AND offset = :offset"
+		filter += " OFFSET :offset "
 	}
 
 	query += filter
 
 	query, args = ReplaceQueryParams(query, params)
 
-	rows, err := r.DB.Query(query, args...)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	ListMenu := []*pb.MenuItem{}
 	for rows.Next() {
-		menu := pb.MenuItem{}
-		err := rows.Scan(&menu.Id, &menu.RestaurantId, &menu.Name, &menu.Description, &menu.Price)
+		menu, err := scanMenuItem(rows)
 		if err != nil {
 			return nil, err
 		}
-		ListMenu = append(ListMenu, &menu)
+		ListMenu = append(ListMenu, menu)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if err = attachModifierGroups(ctx, r.DB, ListMenu...); err != nil {
+		return nil, err
+	}
+	if err = attachAvailability(ctx, r.DB, availableAt, ListMenu...); err != nil {
+		return nil, err
+	}
+	if err = attachMenuTranslations(ctx, r.DB, ListMenu...); err != nil {
+		return nil, err
+	}
+
+	categories, err := loadMenuCategoriesOf(ctx, r.DB, ListMenu)
+	if err != nil {
+		return nil, err
 	}
-	return &pb.ListMenuItemsResponse{MenuItems: ListMenu}, nil
+	return &pb.ListMenuItemsResponse{MenuItems: ListMenu, Sections: GroupMenuItems(categories, ListMenu)}, nil
 }
 
 func ReplaceQueryParams(namedQuery string, params map[string]interface{}) (string, []interface{}) {
@@ -116,72 +238,253 @@ func ReplaceQueryParams(namedQuery string, params map[string]interface{}) (strin
 	return namedQuery, args
 }
 
-func (r *ReservationRepo) GetMenuItem(id *pb.GetMenuItemRequest) (*pb.GetMenuItemResponse, error) {
-	itemMenu := pb.MenuItem{}
-	err := r.DB.QueryRow(`	SELECT
-								id,
-								restaurant_id,
-								name,
-								description,
-								price
+func (r *ReservationRepo) GetMenuItem(ctx context.Context, id *pb.GetMenuItemRequest) (_ *pb.GetMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	itemMenu, err := scanMenuItem(r.DB.QueryRowContext(ctx, `	SELECT
+								`+menuItemColumns+`
 							FROM
 								Menu
 							WHERE
-								id = $1`,
-		id.Id).
-		Scan(
-			&itemMenu.Id,
-			&itemMenu.RestaurantId,
-			&itemMenu.Name,
-			&itemMenu.Description,
-			&itemMenu.Price,
-		)
+								id = $1 AND deleted_at = 0
+								AND restaurant_id IN (
+									SELECT id FROM Restaurants WHERE deleted_at = 0 AND is_active
+								)`,
+		id.Id))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
 		return nil, err
 	}
-	return &pb.GetMenuItemResponse{MenuItem: &itemMenu}, nil
+	if err = attachModifierGroups(ctx, r.DB, itemMenu); err != nil {
+		return nil, err
+	}
+	if err = attachAvailability(ctx, r.DB, time.Now(), itemMenu); err != nil {
+		return nil, err
+	}
+	if err = attachMenuTranslations(ctx, r.DB, itemMenu); err != nil {
+		return nil, err
+	}
+	return &pb.GetMenuItemResponse{MenuItem: itemMenu}, nil
 }
 
-func (r *ReservationRepo) UpdateMenuItem(updateMenu *pb.UpdateMenuItemRequest) (*pb.UpdateMenuItemResponse, error) {
-	menu := pb.MenuItem{}
-	err := r.DB.QueryRow(`	
+// UpdateMenuItem changes a menu item. A price_effective_at in the future
+// schedules the new price instead of applying it, leaving the current one in
+// place until then.
+func (r *ReservationRepo) UpdateMenuItem(ctx context.Context, updateMenu *pb.UpdateMenuItemRequest) (_ *pb.UpdateMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpdateMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if err = checkMoney(ctx, r.DB, updateMenu.RestaurantId, "price", updateMenu.Price); err != nil {
+		return nil, err
+	}
+	if err = checkMenuCategory(ctx, r.DB, updateMenu.RestaurantId, updateMenu.CategoryId); err != nil {
+		return nil, err
+	}
+	if err = parsePriceEffectiveAt(updateMenu.PriceEffectiveAt); err != nil {
+		return nil, err
+	}
+	translations, err := normalizeTranslations(updateMenu.Translations)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	scheduled, err := priceScheduled(ctx, tx, updateMenu.PriceEffectiveAt)
+	if err != nil {
+		return nil, err
+	}
+
+	menu, err := scanMenuItem(tx.QueryRowContext(ctx, `	
 						UPDATE 
 						MENU
 					SET
-						restaurant_id = $1,
 						name = $2,
 						description = $3,
-						price = $4
+						price_minor = CASE WHEN $7 THEN price_minor ELSE $4 END,
+						category_id = COALESCE(NULLIF($6, '')::uuid, category_id),
+						updated_at = CURRENT_TIMESTAMP
 					WHERE
-						id = $5
+						id = $5 AND restaurant_id = $1 AND deleted_at = 0
 					returning
-						id,
-						restaurant_id,
-						name,
-						description,
-						price
-					`, updateMenu.RestaurantId, updateMenu.Name, updateMenu.Description, updateMenu.Price, updateMenu.Id).
-		Scan(&menu.Id, &menu.RestaurantId, &menu.Name, &menu.Description, &menu.Price)
+						`+menuItemColumns+`
+					`, updateMenu.RestaurantId, updateMenu.Name, updateMenu.Description, updateMenu.Price.GetMinorUnits(), updateMenu.Id,
+		updateMenu.CategoryId, scheduled))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
+		return nil, err
+	}
+	if scheduled {
+		err = scheduleMenuPrice(ctx, tx, menu.Id, updateMenu.Price.GetMinorUnits(), updateMenu.PriceEffectiveAt)
+	} else {
+		err = recordMenuPrice(ctx, tx, menu.Id)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if err = saveTranslations(ctx, tx, menuItemTranslations, menu.Id, translations); err != nil {
+		return nil, err
+	}
+	if err = attachMenuTranslations(ctx, tx, menu); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &pb.UpdateMenuItemResponse{
-		MenuItem: &menu,
+		MenuItem: menu,
 	}, nil
 }
 
-func (r *ReservationRepo) DeleteMenuItem(id *pb.DeleteMenuItemRequest) (*pb.DeleteMenuItemResponse, error) {
-	_,err := r.DB.Exec(`	DELETE
-				FROM
+func (r *ReservationRepo) DeleteMenuItem(ctx context.Context, id *pb.DeleteMenuItemRequest) (_ *pb.DeleteMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.DeleteMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := r.DB.ExecContext(ctx, `	UPDATE
 					Menu
+				SET
+					deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
 				WHERE
-					id = $1`,id.Id)
-	if err != nil{
+					id = $1 AND deleted_at = 0`, id.Id)
+	if err != nil {
 		return &pb.DeleteMenuItemResponse{
 			Message: "FAILD TO DELETED MENU ITEM",
-		},err
+		}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "menu item not found")
 	}
 	return &pb.DeleteMenuItemResponse{
 		Message: "DELETED SUCCESFULLY MENU ITEM",
-	},nil
+	}, nil
+}
+
+func (r *ReservationRepo) RestoreMenuItem(ctx context.Context, id *pb.RestoreMenuItemRequest) (_ *pb.RestoreMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.RestoreMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	menu, err := scanMenuItem(r.DB.QueryRowContext(ctx, `
+					UPDATE
+						Menu
+					SET
+						deleted_at = 0,
+						updated_at = CURRENT_TIMESTAMP
+					WHERE
+						id = $1 AND deleted_at <> 0
+					returning
+						`+menuItemColumns+`
+					`, id.Id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "deleted menu item not found")
+		}
+		return nil, err
+	}
+	return &pb.RestoreMenuItemResponse{
+		MenuItem: menu,
+	}, nil
+}
+
+// menuCurrency selects the currency of a Menu row's restaurant, which all its
+// prices are in.
+const menuCurrency = `(SELECT currency FROM Restaurants WHERE Restaurants.id = Menu.restaurant_id)`
+
+// menuItemColumns lists the Menu columns read by scanMenuItem.
+const menuItemColumns = `id,
+			restaurant_id,
+			name,
+			description,
+			price_minor,
+			` + menuCurrency + `,
+			COALESCE(category_id::text, ''),
+			position,
+			ARRAY(
+				SELECT t.tag_code FROM MenuItemTags t JOIN MenuTags k ON k.code = t.tag_code
+				WHERE t.menu_item_id = Menu.id AND k.kind = 'allergen' ORDER BY t.tag_code
+			),
+			ARRAY(
+				SELECT t.tag_code FROM MenuItemTags t JOIN MenuTags k ON k.code = t.tag_code
+				WHERE t.menu_item_id = Menu.id AND k.kind = 'dietary' ORDER BY t.tag_code
+			),
+			sold_out,
+			COALESCE(daily_stock, 0)`
+
+func scanMenuItem(row rowScanner) (*pb.MenuItem, error) {
+	item := &pb.MenuItem{Price: &pb.Money{}}
+	err := row.Scan(&item.Id, &item.RestaurantId, &item.Name, &item.Description, &item.Price.MinorUnits,
+		&item.Price.Currency, &item.CategoryId, &item.Position, pq.Array(&item.Allergens), pq.Array(&item.DietaryTags),
+		&item.SoldOut, &item.DailyStock)
+	return item, err
+}
+
+// loadMenuItems returns the given menu items, deleted or not.
+func loadMenuItems(ctx context.Context, q queryer, ids []string) ([]*pb.MenuItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			`+menuItemColumns+`
+		FROM
+			Menu
+		WHERE
+			id = ANY($1::uuid[])
+		ORDER BY
+			name
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu items: %v", err)
+	}
+	defer rows.Close()
+
+	var items []*pb.MenuItem
+	for rows.Next() {
+		item, err := scanMenuItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan menu item: %v", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get menu items: %v", err)
+	}
+	return items, nil
+}
+
+// checkMoney rejects negative amounts and amounts in another currency than
+// the restaurant's. An amount without a currency is taken to be in the
+// restaurant's.
+func checkMoney(ctx context.Context, q queryer, restaurantId, field string, amount *pb.Money) error {
+	if amount.GetMinorUnits() < 0 {
+		return status.Errorf(codes.InvalidArgument, "%s must not be negative", field)
+	}
+	if amount.GetCurrency() == "" {
+		return nil
+	}
+	currency, err := restaurantCurrency(ctx, q, restaurantId)
+	if err != nil {
+		return err
+	}
+	if amount.Currency != currency {
+		return status.Errorf(codes.InvalidArgument, "%s must be in the restaurant's currency %s", field, currency)
+	}
+	return nil
+}
+
+func restaurantCurrency(ctx context.Context, q queryer, restaurantId string) (string, error) {
+	var currency string
+	err := q.QueryRowContext(ctx, `SELECT currency FROM Restaurants WHERE id = $1 AND deleted_at = 0`, restaurantId).Scan(&currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", status.Error(codes.NotFound, "restaurant not found")
+		}
+		return "", fmt.Errorf("failed to get restaurant currency: %v", err)
+	}
+	return currency, nil
 }
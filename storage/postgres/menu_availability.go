@@ -0,0 +1,426 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// menuTimeLayout is the layout of ListMenuItemsRequest.available_at.
+const menuTimeLayout = "2006-01-02 15:04:05"
+
+// MenuItemAvailable reports whether an item can be ordered at the given time:
+// it is not sold out, at falls in one of its availability windows if it has
+// any, and it has stock left if its stock is limited. RemainingStock must
+// already be set for the day of at.
+func MenuItemAvailable(item *pb.MenuItem, at time.Time) bool {
+	if item.SoldOut || (item.DailyStock > 0 && item.RemainingStock <= 0) {
+		return false
+	}
+	return servedAt(item.Availability, at)
+}
+
+// servedAt reports whether at falls in one of the windows, or there are no
+// windows.
+func servedAt(windows []*pb.AvailabilityWindow, at time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	clock := at.Format(slotTimeLayout)
+	for _, window := range windows {
+		if window.DayOfWeek >= 0 && time.Weekday(window.DayOfWeek) != at.Weekday() {
+			continue
+		}
+		if clock >= window.StartTime && clock < window.EndTime {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAvailabilityWindow(window *pb.AvailabilityWindow) error {
+	if window.DayOfWeek < -1 || window.DayOfWeek > 6 {
+		return status.Error(codes.InvalidArgument, "day_of_week must be between 0 and 6, or -1 for every day")
+	}
+	start, err := time.Parse(slotTimeLayout, window.StartTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid start_time %q, use HH:MM", window.StartTime)
+	}
+	end, err := time.Parse(slotTimeLayout, window.EndTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid end_time %q, use HH:MM", window.EndTime)
+	}
+	if !end.After(start) {
+		return status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	}
+	return nil
+}
+
+// attachAvailability fills in the availability windows of the items and
+// whether they can be ordered at the given time.
+func attachAvailability(ctx context.Context, q queryer, at time.Time, items ...*pb.MenuItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+
+	windows := make(map[string][]*pb.AvailabilityWindow)
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			menu_item_id,
+			day_of_week,
+			to_char(start_time, 'HH24:MI'),
+			to_char(end_time, 'HH24:MI')
+		FROM
+			MenuItemAvailability
+		WHERE
+			menu_item_id = ANY($1::uuid[])
+		ORDER BY
+			menu_item_id, day_of_week, start_time
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get menu availability: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var menuItemId string
+		window := &pb.AvailabilityWindow{}
+		if err := rows.Scan(&menuItemId, &window.DayOfWeek, &window.StartTime, &window.EndTime); err != nil {
+			return fmt.Errorf("failed to scan menu availability: %v", err)
+		}
+		windows[menuItemId] = append(windows[menuItemId], window)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get menu availability: %v", err)
+	}
+
+	ordered := make(map[string]int32)
+	rows, err = q.QueryContext(ctx, `
+		SELECT
+			menu_item_id,
+			ordered
+		FROM
+			MenuItemStock
+		WHERE
+			menu_item_id = ANY($1::uuid[]) AND stock_date = $2::date
+	`, pq.Array(ids), at.Format(time.DateOnly))
+	if err != nil {
+		return fmt.Errorf("failed to get menu stock: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			menuItemId string
+			n          int32
+		)
+		if err := rows.Scan(&menuItemId, &n); err != nil {
+			return fmt.Errorf("failed to scan menu stock: %v", err)
+		}
+		ordered[menuItemId] = n
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get menu stock: %v", err)
+	}
+
+	for _, item := range items {
+		item.Availability = windows[item.Id]
+		item.RemainingStock = 0
+		if item.DailyStock > 0 {
+			item.RemainingStock = max(item.DailyStock-ordered[item.Id], 0)
+		}
+		item.Available = MenuItemAvailable(item, at)
+	}
+	return nil
+}
+
+// SetMenuItemAvailability replaces the availability windows and daily stock
+// of a menu item.
+func (r *ReservationRepo) SetMenuItemAvailability(ctx context.Context, req *pb.SetMenuItemAvailabilityRequest) (_ *pb.SetMenuItemAvailabilityResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetMenuItemAvailability", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.DailyStock < 0 {
+		return nil, status.Error(codes.InvalidArgument, "daily_stock must not be negative")
+	}
+	for _, window := range req.Windows {
+		if err = validateAvailabilityWindow(window); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set menu availability: %v", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanMenuItem(tx.QueryRowContext(ctx, `
+		UPDATE
+			Menu
+		SET
+			daily_stock = NULLIF($2, 0),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0
+		RETURNING
+			`+menuItemColumns+`
+	`, req.MenuItemId, req.DailyStock))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
+		return nil, fmt.Errorf("failed to set menu availability: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM MenuItemAvailability WHERE menu_item_id = $1`, req.MenuItemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set menu availability: %v", err)
+	}
+	for _, window := range req.Windows {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO MenuItemAvailability (
+				menu_item_id,
+				day_of_week,
+				start_time,
+				end_time
+			)
+			VALUES (
+				$1,
+				$2,
+				$3,
+				$4
+			)
+		`, req.MenuItemId, window.DayOfWeek, window.StartTime, window.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set menu availability: %v", err)
+		}
+	}
+
+	if err = attachModifierGroups(ctx, tx, item); err != nil {
+		return nil, err
+	}
+	if err = attachAvailability(ctx, tx, time.Now(), item); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set menu availability: %v", err)
+	}
+	return &pb.SetMenuItemAvailabilityResponse{MenuItem: item}, nil
+}
+
+// SetMenuItemSoldOut marks a menu item as sold out, or back in stock.
+func (r *ReservationRepo) SetMenuItemSoldOut(ctx context.Context, req *pb.SetMenuItemSoldOutRequest) (_ *pb.SetMenuItemSoldOutResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetMenuItemSoldOut", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	item, err := scanMenuItem(r.DB.QueryRowContext(ctx, `
+		UPDATE
+			Menu
+		SET
+			sold_out = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0
+		RETURNING
+			`+menuItemColumns+`
+	`, req.MenuItemId, req.SoldOut))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
+		return nil, fmt.Errorf("failed to set sold out: %v", err)
+	}
+	if err = attachModifierGroups(ctx, r.DB, item); err != nil {
+		return nil, err
+	}
+	if err = attachAvailability(ctx, r.DB, time.Now(), item); err != nil {
+		return nil, err
+	}
+	return &pb.SetMenuItemSoldOutResponse{MenuItem: item}, nil
+}
+
+// reserveMenuStock takes the stock of the ordered items for the day of the
+// reservation, or gives it back for items ordered fewer times than before.
+// Items ordered more times than before must be available at reservationTime.
+// The stock is counted with a conditional update, so concurrent orders
+// cannot take more than the daily stock between them.
+func reserveMenuStock(ctx context.Context, tx *sql.Tx, reservationId string, reservationTime time.Time, meals []*pb.MealOrder) error {
+	type line struct{ menuItemId, key string }
+	var (
+		lines      []line
+		quantities = make(map[line]int32)
+	)
+	for _, meal := range meals {
+		l := line{meal.MenuItemId, optionsKey(meal.OptionIds)}
+		if _, ok := quantities[l]; !ok {
+			lines = append(lines, l)
+		}
+		quantities[l] = meal.Quantity
+	}
+
+	day := reservationTime.Format(time.DateOnly)
+	for _, l := range lines {
+		var previous int32
+		err := tx.QueryRowContext(ctx, `
+			SELECT
+				COALESCE(SUM(quantity), 0)
+			FROM
+				ReservationOrders
+			WHERE
+				reservation_id = $1 AND menu_item_id = $2 AND options_key = $3 AND deleted_at = 0
+		`, reservationId, l.menuItemId, l.key).Scan(&previous)
+		if err != nil {
+			return fmt.Errorf("failed to reserve stock: %v", err)
+		}
+		delta := quantities[l] - previous
+		if delta == 0 {
+			continue
+		}
+
+		name := l.menuItemId
+		if delta > 0 {
+			items, err := loadMenuItems(ctx, tx, []string{l.menuItemId})
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				// saveOrderLines rejects items that are not on the menu.
+				continue
+			}
+			item := items[0]
+			name = item.Name
+			if item.SoldOut {
+				return status.Errorf(codes.FailedPrecondition, "%s is sold out", item.Name)
+			}
+			if err = attachAvailability(ctx, tx, reservationTime, item); err != nil {
+				return err
+			}
+			if !servedAt(item.Availability, reservationTime) {
+				return status.Errorf(codes.FailedPrecondition, "%s is not served at the time of the reservation", item.Name)
+			}
+		}
+
+		ok, err := takeMenuStock(ctx, tx, l.menuItemId, day, delta)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return status.Errorf(codes.FailedPrecondition, "not enough %s left for that day", name)
+		}
+	}
+	return nil
+}
+
+// takeMenuStock adds delta to the item's ordered count for day, or takes it
+// off when delta is negative. It reports false if the daily stock does not
+// cover a positive delta.
+func takeMenuStock(ctx context.Context, tx *sql.Tx, menuItemId, day string, delta int32) (bool, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO MenuItemStock (
+			menu_item_id,
+			stock_date
+		)
+		SELECT
+			id,
+			$2
+		FROM
+			Menu
+		WHERE
+			id = $1
+		ON CONFLICT (menu_item_id, stock_date) DO NOTHING
+	`, menuItemId, day)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve stock: %v", err)
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE
+			MenuItemStock s
+		SET
+			ordered = GREATEST(s.ordered + $3, 0)
+		FROM
+			Menu m
+		WHERE
+			s.menu_item_id = $1 AND s.stock_date = $2 AND m.id = s.menu_item_id
+			AND ($3 <= 0 OR m.daily_stock IS NULL OR s.ordered + $3 <= m.daily_stock)
+	`, menuItemId, day, delta)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve stock: %v", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0 || delta <= 0, nil
+}
+
+// releaseMenuStock gives back the stock taken by a reservation's orders on
+// the day it is booked for. It is called when the reservation stops being
+// pending or confirmed, is deleted, or before it moves to another day.
+func releaseMenuStock(ctx context.Context, tx *sql.Tx, reservationId string) error {
+	return adjustMenuStock(ctx, tx, reservationId, -1)
+}
+
+// holdMenuStock takes the stock of a reservation's orders again on the day
+// it is booked for, after it was restored or moved to another day.
+func holdMenuStock(ctx context.Context, tx *sql.Tx, reservationId string) error {
+	return adjustMenuStock(ctx, tx, reservationId, 1)
+}
+
+func adjustMenuStock(ctx context.Context, tx *sql.Tx, reservationId string, sign int32) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			o.menu_item_id,
+			m.name,
+			to_char(r.reservation_time, 'YYYY-MM-DD'),
+			SUM(o.quantity)
+		FROM
+			ReservationOrders o
+			JOIN reservations r ON r.id = o.reservation_id
+			JOIN Menu m ON m.id = o.menu_item_id
+		WHERE
+			o.reservation_id = $1 AND o.deleted_at = 0
+		GROUP BY
+			o.menu_item_id, m.name, r.reservation_time
+	`, reservationId)
+	if err != nil {
+		return fmt.Errorf("failed to get ordered stock: %v", err)
+	}
+	type held struct {
+		menuItemId, name, day string
+		quantity              int32
+	}
+	var lines []held
+	for rows.Next() {
+		var h held
+		if err := rows.Scan(&h.menuItemId, &h.name, &h.day, &h.quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan ordered stock: %v", err)
+		}
+		lines = append(lines, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get ordered stock: %v", err)
+	}
+
+	for _, h := range lines {
+		ok, err := takeMenuStock(ctx, tx, h.menuItemId, h.day, sign*h.quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return status.Errorf(codes.FailedPrecondition, "not enough %s left for that day", h.name)
+		}
+	}
+	return nil
+}
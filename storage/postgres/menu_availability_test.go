@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMenuItemAvailable(t *testing.T) {
+	lunch := []*pb.AvailabilityWindow{{DayOfWeek: -1, StartTime: "11:00", EndTime: "15:00"}}
+	weekend := []*pb.AvailabilityWindow{
+		{DayOfWeek: int32(time.Saturday), StartTime: "00:00", EndTime: "23:59"},
+		{DayOfWeek: int32(time.Sunday), StartTime: "00:00", EndTime: "23:59"},
+	}
+	// 2030-08-03 is a Saturday.
+	saturdayNoon := time.Date(2030, 8, 3, 12, 0, 0, 0, time.UTC)
+	mondayNoon := time.Date(2030, 8, 5, 12, 0, 0, 0, time.UTC)
+	mondayEvening := time.Date(2030, 8, 5, 19, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		item *pb.MenuItem
+		at   time.Time
+		want bool
+	}{
+		{"always", &pb.MenuItem{}, mondayEvening, true},
+		{"sold out", &pb.MenuItem{SoldOut: true}, mondayNoon, false},
+		{"stock left", &pb.MenuItem{DailyStock: 5, RemainingStock: 1}, mondayNoon, true},
+		{"no stock left", &pb.MenuItem{DailyStock: 5}, mondayNoon, false},
+		{"lunch at noon", &pb.MenuItem{Availability: lunch}, mondayNoon, true},
+		{"lunch in the evening", &pb.MenuItem{Availability: lunch}, mondayEvening, false},
+		{"lunch at closing", &pb.MenuItem{Availability: lunch}, time.Date(2030, 8, 5, 15, 0, 0, 0, time.UTC), false},
+		{"weekend on saturday", &pb.MenuItem{Availability: weekend}, saturdayNoon, true},
+		{"weekend on monday", &pb.MenuItem{Availability: weekend}, mondayNoon, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MenuItemAvailable(tt.item, tt.at))
+		})
+	}
+
+	assert.Error(t, validateAvailabilityWindow(&pb.AvailabilityWindow{DayOfWeek: 7, StartTime: "11:00", EndTime: "15:00"}))
+	assert.Error(t, validateAvailabilityWindow(&pb.AvailabilityWindow{DayOfWeek: 1, StartTime: "15:00", EndTime: "11:00"}))
+	assert.NoError(t, validateAvailabilityWindow(lunch[0]))
+}
+
+func TestMenuItemStock(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	ctx := context.Background()
+
+	item, err := repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Stock test lunch special",
+		Price:        &pb.Money{MinorUnits: 1100},
+	})
+	assert.NoError(t, err)
+	set, err := repo.SetMenuItemAvailability(ctx, &pb.SetMenuItemAvailabilityRequest{
+		MenuItemId: item.MenuItem.Id,
+		Windows:    []*pb.AvailabilityWindow{{DayOfWeek: -1, StartTime: "11:00", EndTime: "15:00"}},
+		DailyStock: 3,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, set.MenuItem.Availability, 1)
+	assert.Equal(t, int32(3), set.MenuItem.DailyStock)
+
+	res, err := repo.CreateReservation(ctx, &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-05 12:30:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	lunchTime := time.Date(2030, 8, 5, 12, 30, 0, 0, time.UTC)
+	order := func(quantity int32, at time.Time) error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		meals := []*pb.MealOrder{{MenuItemId: item.MenuItem.Id, Quantity: quantity}}
+		if err := reserveMenuStock(ctx, tx, res.Reservation.Id, at, meals); err != nil {
+			return err
+		}
+		if err := saveOrderLines(ctx, tx, res.Reservation.Id, restaurantId, meals); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(order(1, time.Date(2030, 8, 5, 19, 0, 0, 0, time.UTC))))
+	assert.NoError(t, order(2, lunchTime))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(order(4, lunchTime)))
+	assert.NoError(t, order(3, lunchTime))
+
+	list, err := repo.ListMenuItems(ctx, &pb.ListMenuItemsRequest{RestaurantId: restaurantId, Name: "Stock test lunch special", AvailableAt: "2030-08-05 12:00:00"})
+	assert.NoError(t, err)
+	if assert.Len(t, list.MenuItems, 1) {
+		assert.False(t, list.MenuItems[0].Available)
+		assert.Zero(t, list.MenuItems[0].RemainingStock)
+	}
+	list, err = repo.ListMenuItems(ctx, &pb.ListMenuItemsRequest{RestaurantId: restaurantId, Name: "Stock test lunch special", AvailableAt: "2030-08-05 12:00:00", HideUnavailable: true})
+	assert.NoError(t, err)
+	assert.Empty(t, list.MenuItems)
+
+	assert.NoError(t, order(1, lunchTime))
+	sold, err := repo.SetMenuItemSoldOut(ctx, &pb.SetMenuItemSoldOutRequest{MenuItemId: item.MenuItem.Id, SoldOut: true})
+	assert.NoError(t, err)
+	assert.True(t, sold.MenuItem.SoldOut)
+	assert.False(t, sold.MenuItem.Available)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(order(2, lunchTime)))
+
+	_, err = repo.DeleteMenuItem(ctx, &pb.DeleteMenuItemRequest{Id: item.MenuItem.Id})
+	assert.NoError(t, err)
+}
+
+func TestMenuStockRelease(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+	r := connectTestRedis()
+
+	repo := ReservationRepo{DB: db, R: r}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	ctx := context.Background()
+
+	item, err := repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Stock release test plov",
+		Price:        &pb.Money{MinorUnits: 900},
+	})
+	assert.NoError(t, err)
+	_, err = repo.SetMenuItemAvailability(ctx, &pb.SetMenuItemAvailabilityRequest{MenuItemId: item.MenuItem.Id, DailyStock: 2})
+	assert.NoError(t, err)
+
+	res, err := repo.CreateReservation(ctx, &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-09-02 13:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	_, err = repo.OrderMeals(ctx, &pb.OrderMealsRequest{
+		ReservationId: res.Reservation.Id,
+		Meals:         []*pb.MealOrder{{MenuItemId: item.MenuItem.Id, Quantity: 2}},
+	})
+	assert.NoError(t, err)
+
+	remaining := func(day string) int32 {
+		list, err := repo.ListMenuItems(ctx, &pb.ListMenuItemsRequest{RestaurantId: restaurantId, Name: "Stock release test plov", AvailableAt: day})
+		assert.NoError(t, err)
+		if !assert.Len(t, list.MenuItems, 1) {
+			return -1
+		}
+		return list.MenuItems[0].RemainingStock
+	}
+	assert.Zero(t, remaining("2030-09-02 13:00:00"))
+
+	// Moving the reservation to another day moves its stock along.
+	_, err = repo.UpdateReservation(ctx, &pb.UpdateReservationRequest{
+		Id:              res.Reservation.Id,
+		UserId:          res.Reservation.UserId,
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-09-03 13:00:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), remaining("2030-09-02 13:00:00"))
+	assert.Zero(t, remaining("2030-09-03 13:00:00"))
+
+	// Cancelling gives it back, and a cancelled reservation takes no orders.
+	_, err = repo.ApplyCancellation(ctx, res.Reservation.Id, "Plans changed", &pb.AppliedCancellationPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), remaining("2030-09-03 13:00:00"))
+	_, err = repo.OrderMeals(ctx, &pb.OrderMealsRequest{
+		ReservationId: res.Reservation.Id,
+		Meals:         []*pb.MealOrder{{MenuItemId: item.MenuItem.Id, Quantity: 1}},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = repo.DeleteMenuItem(ctx, &pb.DeleteMenuItemRequest{Id: item.MenuItem.Id})
+	assert.NoError(t, err)
+}
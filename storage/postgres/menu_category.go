@@ -0,0 +1,350 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// menuCategoryColumns lists the columns read by scanMenuCategory.
+const menuCategoryColumns = `id,
+			restaurant_id,
+			name,
+			position`
+
+func scanMenuCategory(row rowScanner) (*pb.MenuCategory, error) {
+	category := &pb.MenuCategory{}
+	err := row.Scan(&category.Id, &category.RestaurantId, &category.Name, &category.Position)
+	return category, err
+}
+
+// isUniqueViolation reports whether err comes from a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// GroupMenuItems splits items into one section per category, in the order of
+// categories, keeping the order of the items within each. Categories without
+// items are left out, and items outside the given categories form a last
+// section without a category.
+func GroupMenuItems(categories []*pb.MenuCategory, items []*pb.MenuItem) []*pb.MenuSection {
+	sections := make([]*pb.MenuSection, 0, len(categories)+1)
+	byCategory := make(map[string]*pb.MenuSection, len(categories))
+	for _, category := range categories {
+		section := &pb.MenuSection{Category: category}
+		byCategory[category.Id] = section
+		sections = append(sections, section)
+	}
+
+	other := &pb.MenuSection{}
+	for _, item := range items {
+		section, ok := byCategory[item.CategoryId]
+		if !ok {
+			section = other
+		}
+		section.MenuItems = append(section.MenuItems, item)
+	}
+
+	grouped := sections[:0]
+	for _, section := range sections {
+		if len(section.MenuItems) > 0 {
+			grouped = append(grouped, section)
+		}
+	}
+	if len(other.MenuItems) > 0 {
+		grouped = append(grouped, other)
+	}
+	return grouped
+}
+
+// loadMenuCategoriesOf returns the categories of the given items by position.
+func loadMenuCategoriesOf(ctx context.Context, q queryer, items []*pb.MenuItem) ([]*pb.MenuCategory, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, item := range items {
+		if item.CategoryId != "" && !seen[item.CategoryId] {
+			seen[item.CategoryId] = true
+			ids = append(ids, item.CategoryId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			`+menuCategoryColumns+`
+		FROM
+			MenuCategories
+		WHERE
+			id = ANY($1::uuid[]) AND deleted_at = 0
+		ORDER BY
+			position, name
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu categories: %v", err)
+	}
+	defer rows.Close()
+
+	var categories []*pb.MenuCategory
+	for rows.Next() {
+		category, err := scanMenuCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan menu category: %v", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get menu categories: %v", err)
+	}
+	return categories, nil
+}
+
+// checkMenuCategory rejects a category that is not one of the restaurant's.
+// An empty categoryId is accepted.
+func checkMenuCategory(ctx context.Context, q queryer, restaurantId, categoryId string) error {
+	if categoryId == "" {
+		return nil
+	}
+	var exists bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM MenuCategories WHERE id::text = $1 AND restaurant_id::text = $2 AND deleted_at = 0
+		)
+	`, categoryId, restaurantId).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check menu category: %v", err)
+	}
+	if !exists {
+		return status.Error(codes.InvalidArgument, "category_id is not a category of the restaurant")
+	}
+	return nil
+}
+
+// CreateMenuCategory adds a category to a restaurant's menu. Without a
+// position it is put after the existing categories.
+func (r *ReservationRepo) CreateMenuCategory(ctx context.Context, req *pb.CreateMenuCategoryRequest) (_ *pb.CreateMenuCategoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CreateMenuCategory", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.RestaurantId == "" || req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "restaurant_id and name are required")
+	}
+	if req.Position < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must not be negative")
+	}
+
+	category, err := scanMenuCategory(r.DB.QueryRowContext(ctx, `
+		INSERT INTO MenuCategories (
+			restaurant_id,
+			name,
+			position
+		)
+		SELECT
+			id,
+			$2,
+			COALESCE(NULLIF($3, 0), (
+				SELECT COALESCE(MAX(position), 0) + 1 FROM MenuCategories WHERE restaurant_id = $1 AND deleted_at = 0
+			))
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		RETURNING
+			`+menuCategoryColumns+`
+	`, req.RestaurantId, req.Name, req.Position))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "category %q already exists", req.Name)
+		}
+		return nil, fmt.Errorf("failed to create menu category: %v", err)
+	}
+	return &pb.CreateMenuCategoryResponse{Category: category}, nil
+}
+
+func (r *ReservationRepo) ListMenuCategories(ctx context.Context, req *pb.ListMenuCategoriesRequest) (_ *pb.ListMenuCategoriesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListMenuCategories", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			`+menuCategoryColumns+`
+		FROM
+			MenuCategories
+		WHERE
+			restaurant_id = $1 AND deleted_at = 0
+		ORDER BY
+			position, name
+	`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list menu categories: %v", err)
+	}
+	defer rows.Close()
+
+	var categories []*pb.MenuCategory
+	for rows.Next() {
+		category, err := scanMenuCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan menu category: %v", err)
+		}
+		categories = append(categories, category)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list menu categories: %v", err)
+	}
+	return &pb.ListMenuCategoriesResponse{Categories: categories}, nil
+}
+
+func (r *ReservationRepo) UpdateMenuCategory(ctx context.Context, req *pb.UpdateMenuCategoryRequest) (_ *pb.UpdateMenuCategoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpdateMenuCategory", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.Position < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must not be negative")
+	}
+
+	category, err := scanMenuCategory(r.DB.QueryRowContext(ctx, `
+		UPDATE
+			MenuCategories
+		SET
+			name = COALESCE(NULLIF($2, ''), name),
+			position = COALESCE(NULLIF($3, 0), position),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0
+		RETURNING
+			`+menuCategoryColumns+`
+	`, req.Id, req.Name, req.Position))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu category not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "category %q already exists", req.Name)
+		}
+		return nil, fmt.Errorf("failed to update menu category: %v", err)
+	}
+	return &pb.UpdateMenuCategoryResponse{Category: category}, nil
+}
+
+// DeleteMenuCategory deletes a category and takes its items out of it, so
+// they stay on the menu outside any category.
+func (r *ReservationRepo) DeleteMenuCategory(ctx context.Context, req *pb.DeleteMenuCategoryRequest) (_ *pb.DeleteMenuCategoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.DeleteMenuCategory", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete menu category: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE
+			MenuCategories
+		SET
+			deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+		WHERE
+			id = $1 AND deleted_at = 0
+	`, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete menu category: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "menu category not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE
+			Menu
+		SET
+			category_id = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			category_id = $1
+	`, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete menu category: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to delete menu category: %v", err)
+	}
+	return &pb.DeleteMenuCategoryResponse{Message: "menu category deleted"}, nil
+}
+
+// MoveMenuItem moves an item to another category of its restaurant, or out of
+// any category, keeping its id. Without a position it is put after the items
+// already there.
+func (r *ReservationRepo) MoveMenuItem(ctx context.Context, req *pb.MoveMenuItemRequest) (_ *pb.MoveMenuItemResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MoveMenuItem", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.Position < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must not be negative")
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move menu item: %v", err)
+	}
+	defer tx.Rollback()
+
+	var restaurantId string
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			restaurant_id
+		FROM
+			Menu
+		WHERE
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, req.MenuItemId).Scan(&restaurantId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
+		return nil, fmt.Errorf("failed to move menu item: %v", err)
+	}
+	if err = checkMenuCategory(ctx, tx, restaurantId, req.CategoryId); err != nil {
+		return nil, err
+	}
+
+	item, err := scanMenuItem(tx.QueryRowContext(ctx, `
+		UPDATE
+			Menu
+		SET
+			category_id = NULLIF($2, '')::uuid,
+			position = COALESCE(NULLIF($3, 0), (
+				SELECT COALESCE(MAX(m.position), 0) + 1 FROM Menu m
+				WHERE m.restaurant_id = Menu.restaurant_id
+					AND m.category_id IS NOT DISTINCT FROM NULLIF($2, '')::uuid
+					AND m.id <> Menu.id
+					AND m.deleted_at = 0
+			)),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1
+		RETURNING
+			`+menuItemColumns+`
+	`, req.MenuItemId, req.CategoryId, req.Position))
+	if err != nil {
+		return nil, fmt.Errorf("failed to move menu item: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to move menu item: %v", err)
+	}
+	return &pb.MoveMenuItemResponse{MenuItem: item}, nil
+}
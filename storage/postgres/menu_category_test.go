@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGroupMenuItems(t *testing.T) {
+	starters := &pb.MenuCategory{Id: "starters", Name: "Starters", Position: 1}
+	mains := &pb.MenuCategory{Id: "mains", Name: "Mains", Position: 2}
+	drinks := &pb.MenuCategory{Id: "drinks", Name: "Drinks", Position: 3}
+	items := []*pb.MenuItem{
+		{Id: "samsa", CategoryId: "starters"},
+		{Id: "plov", CategoryId: "mains"},
+		{Id: "lagman", CategoryId: "mains"},
+		{Id: "bread"},
+		{Id: "gone", CategoryId: "deleted"},
+	}
+
+	sections := GroupMenuItems([]*pb.MenuCategory{starters, mains, drinks}, items)
+	assert.Len(t, sections, 3)
+	assert.Equal(t, starters, sections[0].Category)
+	assert.Equal(t, []*pb.MenuItem{items[0]}, sections[0].MenuItems)
+	assert.Equal(t, mains, sections[1].Category)
+	assert.Equal(t, []*pb.MenuItem{items[1], items[2]}, sections[1].MenuItems)
+	assert.Nil(t, sections[2].Category)
+	assert.Equal(t, []*pb.MenuItem{items[3], items[4]}, sections[2].MenuItems)
+
+	assert.Empty(t, GroupMenuItems([]*pb.MenuCategory{drinks}, nil))
+}
+
+func TestMenuCategories(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	starters, err := repo.CreateMenuCategory(context.Background(), &pb.CreateMenuCategoryRequest{RestaurantId: restaurantId, Name: "Starters"})
+	assert.NoError(t, err)
+	mains, err := repo.CreateMenuCategory(context.Background(), &pb.CreateMenuCategoryRequest{RestaurantId: restaurantId, Name: "Mains"})
+	assert.NoError(t, err)
+	assert.Greater(t, mains.Category.Position, starters.Category.Position)
+
+	_, err = repo.CreateMenuCategory(context.Background(), &pb.CreateMenuCategoryRequest{RestaurantId: restaurantId, Name: "starters"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	item, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Category test dish",
+		Price:        &pb.Money{MinorUnits: 1500},
+		CategoryId:   starters.Category.Id,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, starters.Category.Id, item.MenuItem.CategoryId)
+
+	moved, err := repo.MoveMenuItem(context.Background(), &pb.MoveMenuItemRequest{MenuItemId: item.MenuItem.Id, CategoryId: mains.Category.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, item.MenuItem.Id, moved.MenuItem.Id)
+	assert.Equal(t, mains.Category.Id, moved.MenuItem.CategoryId)
+
+	list, err := repo.ListMenuItems(context.Background(), &pb.ListMenuItemsRequest{RestaurantId: restaurantId, CategoryId: mains.Category.Id})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Sections, 1) {
+		assert.Equal(t, mains.Category.Id, list.Sections[0].Category.Id)
+	}
+
+	_, err = repo.MoveMenuItem(context.Background(), &pb.MoveMenuItemRequest{
+		MenuItemId: item.MenuItem.Id,
+		CategoryId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Nor can an update move the item to another restaurant's menu.
+	_, err = repo.UpdateMenuItem(context.Background(), &pb.UpdateMenuItemRequest{
+		Id:           item.MenuItem.Id,
+		RestaurantId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
+		Name:         item.MenuItem.Name,
+		Price:        item.MenuItem.Price,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = repo.DeleteMenuCategory(context.Background(), &pb.DeleteMenuCategoryRequest{Id: mains.Category.Id})
+	assert.NoError(t, err)
+	got, err := repo.GetMenuItem(context.Background(), &pb.GetMenuItemRequest{Id: item.MenuItem.Id})
+	assert.NoError(t, err)
+	assert.Empty(t, got.MenuItem.CategoryId)
+
+	_, err = repo.DeleteMenuCategory(context.Background(), &pb.DeleteMenuCategoryRequest{Id: starters.Category.Id})
+	assert.NoError(t, err)
+	_, err = repo.DeleteMenuItem(context.Background(), &pb.DeleteMenuItemRequest{Id: item.MenuItem.Id})
+	assert.NoError(t, err)
+
+	// Deleted categories are purged like the rest of the menu.
+	_, err = db.Exec(`UPDATE MenuCategories SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE id = ANY($1)`,
+		pq.Array([]string{starters.Category.Id, mains.Category.Id}))
+	assert.NoError(t, err)
+	purged, err := repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged.MenuCategories, int64(2))
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// menuKey is how imported rows are matched to menu items and categories.
+func menuKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// validateMenuRow checks a row before anything is written, so that one bad
+// row does not abort the import's transaction.
+func validateMenuRow(ctx context.Context, q queryer, restaurantId string, row *pb.MenuRow) error {
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return status.Error(codes.InvalidArgument, "name is longer than 255 characters")
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(row.Category)) > 100 {
+		return status.Error(codes.InvalidArgument, "category is longer than 100 characters")
+	}
+	if row.Price == nil {
+		return status.Error(codes.InvalidArgument, "price is required")
+	}
+	if err := checkMoney(ctx, q, restaurantId, "price", row.Price); err != nil {
+		return err
+	}
+	if row.DailyStock < 0 {
+		return status.Error(codes.InvalidArgument, "daily_stock must not be negative")
+	}
+	if _, err := checkMenuTags(ctx, q, TagAllergen, row.Allergens); err != nil {
+		return err
+	}
+	if _, err := checkMenuTags(ctx, q, TagDietary, row.DietaryTags); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadMenuKeys returns the ids of a restaurant's menu items or categories
+// keyed by menuKey. When names repeat, the oldest one is kept.
+func loadMenuKeys(ctx context.Context, q queryer, table, restaurantId string) (map[string]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			name,
+			id
+		FROM
+			`+table+`
+		WHERE
+			restaurant_id = $1 AND deleted_at = 0
+		ORDER BY
+			created_at DESC
+	`, restaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %v", table, err)
+		}
+		ids[menuKey(name)] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get %s: %v", table, err)
+	}
+	return ids, nil
+}
+
+// ImportMenu creates or updates the items of a restaurant's menu from rows,
+// all in one transaction. Every row is validated first, and nothing is
+// written if any row is rejected or the import is a dry run.
+func (r *ReservationRepo) ImportMenu(ctx context.Context, req *pb.ImportMenuRequest) (_ *pb.ImportMenuResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ImportMenu", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.RestaurantId == "" {
+		return nil, status.Error(codes.InvalidArgument, "restaurant_id is required")
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import menu: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = restaurantCurrency(ctx, tx, req.RestaurantId); err != nil {
+		return nil, err
+	}
+	items, err := loadMenuKeys(ctx, tx, "Menu", req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := loadMenuKeys(ctx, tx, "MenuCategories", req.RestaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ImportMenuResponse{}
+	seen := make(map[string]int32)
+	newCategories := make(map[string]bool)
+	for i, row := range req.Rows {
+		n := int32(i + 1)
+		if err = validateMenuRow(ctx, tx, req.RestaurantId, row); err != nil {
+			if status.Code(err) != codes.InvalidArgument {
+				return nil, err
+			}
+			res.Errors = append(res.Errors, &pb.MenuRowError{Row: n, Name: row.Name, Message: status.Convert(err).Message()})
+			continue
+		}
+		key := menuKey(row.Name)
+		if first, ok := seen[key]; ok {
+			res.Errors = append(res.Errors, &pb.MenuRowError{Row: n, Name: row.Name, Message: fmt.Sprintf("same name as row %d", first)})
+			continue
+		}
+		seen[key] = n
+
+		if _, ok := items[key]; ok {
+			res.Updated++
+		} else {
+			res.Created++
+		}
+		if category := menuKey(row.Category); category != "" && categories[category] == "" && !newCategories[category] {
+			newCategories[category] = true
+			res.CategoriesCreated++
+		}
+	}
+	if len(res.Errors) > 0 || req.DryRun {
+		return res, nil
+	}
+
+	for _, row := range req.Rows {
+		categoryId := ""
+		if category := menuKey(row.Category); category != "" {
+			if categoryId = categories[category]; categoryId == "" {
+				err = tx.QueryRowContext(ctx, `
+					INSERT INTO MenuCategories (
+						restaurant_id,
+						name,
+						position
+					)
+					VALUES (
+						$1,
+						$2,
+						(SELECT COALESCE(MAX(position), 0) + 1 FROM MenuCategories WHERE restaurant_id = $1 AND deleted_at = 0)
+					)
+					RETURNING
+						id
+				`, req.RestaurantId, strings.TrimSpace(row.Category)).Scan(&categoryId)
+				if err != nil {
+					return nil, fmt.Errorf("failed to import menu: %v", err)
+				}
+				categories[category] = categoryId
+			}
+		}
+
+		id, ok := items[menuKey(row.Name)]
+		if ok {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE
+					Menu
+				SET
+					description = $2,
+					price_minor = $3,
+					category_id = COALESCE(NULLIF($4, '')::uuid, category_id),
+					daily_stock = NULLIF($5, 0),
+					sold_out = $6,
+					updated_at = CURRENT_TIMESTAMP
+				WHERE
+					id = $1
+			`, id, row.Description, row.Price.GetMinorUnits(), categoryId, row.DailyStock, row.SoldOut)
+		} else {
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO Menu (
+					restaurant_id,
+					name,
+					description,
+					price_minor,
+					category_id,
+					daily_stock,
+					sold_out
+				)
+				VALUES (
+					$1,
+					$2,
+					$3,
+					$4,
+					NULLIF($5, '')::uuid,
+					NULLIF($6, 0),
+					$7
+				)
+				RETURNING
+					id
+			`, req.RestaurantId, strings.TrimSpace(row.Name), row.Description, row.Price.GetMinorUnits(),
+				categoryId, row.DailyStock, row.SoldOut).Scan(&id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import menu: %v", err)
+		}
+		if _, _, err = saveMenuItemTags(ctx, tx, id, row.Allergens, row.DietaryTags); err != nil {
+			return nil, err
+		}
+		if err = recordMenuPrice(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import menu: %v", err)
+	}
+	res.Committed = true
+	return res, nil
+}
+
+// ExportMenu returns a restaurant's menu as rows that ImportMenu accepts, in
+// menu order.
+func (r *ReservationRepo) ExportMenu(ctx context.Context, req *pb.ExportMenuRequest) (_ *pb.ExportMenuResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ExportMenu", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if _, err = restaurantCurrency(ctx, r.DB, req.RestaurantId); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			m.name,
+			COALESCE(m.description, ''),
+			m.price_minor,
+			rs.currency,
+			COALESCE(c.name, ''),
+			ARRAY(
+				SELECT t.tag_code FROM MenuItemTags t JOIN MenuTags k ON k.code = t.tag_code
+				WHERE t.menu_item_id = m.id AND k.kind = 'allergen' ORDER BY t.tag_code
+			),
+			ARRAY(
+				SELECT t.tag_code FROM MenuItemTags t JOIN MenuTags k ON k.code = t.tag_code
+				WHERE t.menu_item_id = m.id AND k.kind = 'dietary' ORDER BY t.tag_code
+			),
+			COALESCE(m.daily_stock, 0),
+			m.sold_out
+		FROM
+			Menu m
+			JOIN Restaurants rs ON rs.id = m.restaurant_id
+			LEFT JOIN MenuCategories c ON c.id = m.category_id AND c.deleted_at = 0
+		WHERE
+			m.restaurant_id = $1 AND m.deleted_at = 0
+		ORDER BY
+			c.position NULLS LAST, c.id, m.position, m.name
+	`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export menu: %v", err)
+	}
+	defer rows.Close()
+
+	var menu []*pb.MenuRow
+	for rows.Next() {
+		row := &pb.MenuRow{Price: &pb.Money{}}
+		err := rows.Scan(&row.Name, &row.Description, &row.Price.MinorUnits, &row.Price.Currency, &row.Category,
+			pq.Array(&row.Allergens), pq.Array(&row.DietaryTags), &row.DailyStock, &row.SoldOut)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan menu row: %v", err)
+		}
+		menu = append(menu, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export menu: %v", err)
+	}
+	return &pb.ExportMenuResponse{Rows: menu}, nil
+}
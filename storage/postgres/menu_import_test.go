@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestImportMenu(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	ctx := context.Background()
+
+	rows := []*pb.MenuRow{
+		{Name: "Import test plov", Price: &pb.Money{MinorUnits: 1250}, Category: "Import test mains", DailyStock: 20},
+		{Name: "Import test salad", Price: &pb.Money{MinorUnits: 700}, Category: "Import test mains", DietaryTags: []string{"vegan"}},
+	}
+
+	res, err := repo.ImportMenu(ctx, &pb.ImportMenuRequest{
+		RestaurantId: restaurantId,
+		Rows: append(rows,
+			&pb.MenuRow{Name: "", Price: &pb.Money{MinorUnits: 100}},
+			&pb.MenuRow{Name: "Import test soup", Price: &pb.Money{MinorUnits: 500}, Allergens: []string{"vegan"}},
+			&pb.MenuRow{Name: "import test PLOV", Price: &pb.Money{MinorUnits: 1300}},
+		),
+	})
+	assert.NoError(t, err)
+	assert.False(t, res.Committed)
+	var failed []int32
+	for _, rowErr := range res.Errors {
+		failed = append(failed, rowErr.Row)
+	}
+	assert.Equal(t, []int32{3, 4, 5}, failed)
+
+	res, err = repo.ImportMenu(ctx, &pb.ImportMenuRequest{RestaurantId: restaurantId, DryRun: true, Rows: rows})
+	assert.NoError(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, int32(2), res.Created)
+	assert.Equal(t, int32(1), res.CategoriesCreated)
+
+	res, err = repo.ImportMenu(ctx, &pb.ImportMenuRequest{RestaurantId: restaurantId, Rows: rows})
+	assert.NoError(t, err)
+	assert.True(t, res.Committed)
+	assert.Equal(t, int32(2), res.Created)
+
+	rows[0].Price = &pb.Money{MinorUnits: 1400}
+	res, err = repo.ImportMenu(ctx, &pb.ImportMenuRequest{RestaurantId: restaurantId, Rows: rows})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), res.Updated)
+	assert.Zero(t, res.Created)
+	assert.Zero(t, res.CategoriesCreated)
+
+	exported, err := repo.ExportMenu(ctx, &pb.ExportMenuRequest{RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	byName := make(map[string]*pb.MenuRow)
+	for _, row := range exported.Rows {
+		byName[row.Name] = row
+	}
+	if assert.Contains(t, byName, "Import test plov") {
+		assert.Equal(t, int64(1400), byName["Import test plov"].Price.MinorUnits)
+		assert.Equal(t, "Import test mains", byName["Import test plov"].Category)
+		assert.Equal(t, int32(20), byName["Import test plov"].DailyStock)
+	}
+
+	_, err = repo.ExportMenu(ctx, &pb.ExportMenuRequest{RestaurantId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := repo.ListMenuItems(ctx, &pb.ListMenuItemsRequest{RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	for _, item := range list.MenuItems {
+		if item.Name == "Import test plov" || item.Name == "Import test salad" {
+			_, err = repo.DeleteMenuItem(ctx, &pb.DeleteMenuItemRequest{Id: item.Id})
+			assert.NoError(t, err)
+		}
+	}
+	categories, err := repo.ListMenuCategories(ctx, &pb.ListMenuCategoriesRequest{RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	for _, category := range categories.Categories {
+		if category.Name == "Import test mains" {
+			_, err = repo.DeleteMenuCategory(ctx, &pb.DeleteMenuCategoryRequest{Id: category.Id})
+			assert.NoError(t, err)
+		}
+	}
+}
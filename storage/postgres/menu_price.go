@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const menuPriceChangeColumns = `
+	c.id,
+	c.menu_item_id,
+	c.price_minor,
+	r.currency,
+	to_char(c.effective_at, 'YYYY-MM-DD HH24:MI:SS'),
+	COALESCE(to_char(c.applied_at, 'YYYY-MM-DD HH24:MI:SS'), '')
+`
+
+// parsePriceEffectiveAt checks UpdateMenuItemRequest.price_effective_at,
+// which may be empty.
+func parsePriceEffectiveAt(effectiveAt string) error {
+	if effectiveAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(menuTimeLayout, effectiveAt); err != nil {
+		return status.Error(codes.InvalidArgument, "price_effective_at must be formatted as YYYY-MM-DD HH:MM:SS")
+	}
+	return nil
+}
+
+// priceScheduled reports whether a price taking effect at effectiveAt has to
+// wait for the price change job rather than apply now.
+func priceScheduled(ctx context.Context, q queryer, effectiveAt string) (bool, error) {
+	if effectiveAt == "" {
+		return false, nil
+	}
+	var scheduled bool
+	if err := q.QueryRowContext(ctx, `SELECT $1::timestamp > CURRENT_TIMESTAMP`, effectiveAt).Scan(&scheduled); err != nil {
+		return false, fmt.Errorf("failed to check price_effective_at: %v", err)
+	}
+	return scheduled, nil
+}
+
+// recordMenuPrice adds the item's current price to its history, unless it is
+// already the latest applied price.
+func recordMenuPrice(ctx context.Context, tx *sql.Tx, menuItemId string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO MenuPriceChanges (
+			menu_item_id,
+			price_minor,
+			effective_at,
+			applied_at
+		)
+		SELECT
+			id,
+			price_minor,
+			CURRENT_TIMESTAMP,
+			CURRENT_TIMESTAMP
+		FROM
+			Menu
+		WHERE
+			id = $1
+			AND price_minor IS DISTINCT FROM (
+				SELECT
+					price_minor
+				FROM
+					MenuPriceChanges
+				WHERE
+					menu_item_id = $1 AND applied_at IS NOT NULL
+				ORDER BY
+					applied_at DESC, created_at DESC
+				LIMIT 1
+			)
+	`, menuItemId)
+	if err != nil {
+		return fmt.Errorf("failed to record menu price: %v", err)
+	}
+	return nil
+}
+
+// scheduleMenuPrice adds a price change that the price change job applies
+// once effectiveAt has passed.
+func scheduleMenuPrice(ctx context.Context, tx *sql.Tx, menuItemId string, priceMinor int64, effectiveAt string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO MenuPriceChanges (
+			menu_item_id,
+			price_minor,
+			effective_at
+		)
+		VALUES (
+			$1,
+			$2,
+			$3
+		)
+	`, menuItemId, priceMinor, effectiveAt)
+	if err != nil {
+		return fmt.Errorf("failed to schedule menu price: %v", err)
+	}
+	return nil
+}
+
+// GetMenuItemPriceHistory returns the prices an item has had and is
+// scheduled to have. Deleted items keep their history.
+func (r *ReservationRepo) GetMenuItemPriceHistory(ctx context.Context, req *pb.GetMenuItemPriceHistoryRequest) (_ *pb.GetMenuItemPriceHistoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetMenuItemPriceHistory", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	var exists bool
+	err = r.DB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM Menu WHERE id = $1)`, req.MenuItemId).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu item: %v", err)
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, "menu item not found")
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			`+menuPriceChangeColumns+`
+		FROM
+			MenuPriceChanges c
+			JOIN Menu m ON m.id = c.menu_item_id
+			JOIN Restaurants r ON r.id = m.restaurant_id
+		WHERE
+			c.menu_item_id = $1
+		ORDER BY
+			c.applied_at IS NULL,
+			c.applied_at DESC,
+			c.effective_at,
+			c.created_at DESC
+	`, req.MenuItemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu price history: %v", err)
+	}
+	defer rows.Close()
+
+	res := &pb.GetMenuItemPriceHistoryResponse{}
+	for rows.Next() {
+		change := &pb.MenuPriceChange{Price: &pb.Money{}}
+		err = rows.Scan(&change.Id, &change.MenuItemId, &change.Price.MinorUnits, &change.Price.Currency,
+			&change.EffectiveAt, &change.AppliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan menu price change: %v", err)
+		}
+		if change.AppliedAt == "" {
+			res.Scheduled = append(res.Scheduled, change)
+		} else {
+			res.History = append(res.History, change)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get menu price history: %v", err)
+	}
+	return res, nil
+}
+
+// CancelMenuPriceChange deletes a price change that has not taken effect yet.
+func (r *ReservationRepo) CancelMenuPriceChange(ctx context.Context, req *pb.CancelMenuPriceChangeRequest) (_ *pb.CancelMenuPriceChangeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CancelMenuPriceChange", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := r.DB.ExecContext(ctx, `
+		DELETE FROM
+			MenuPriceChanges
+		WHERE
+			id = $1 AND applied_at IS NULL
+	`, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel menu price change: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to cancel menu price change: %v", err)
+	} else if n == 0 {
+		return nil, status.Error(codes.NotFound, "scheduled price change not found")
+	}
+	return &pb.CancelMenuPriceChangeResponse{Message: "price change cancelled"}, nil
+}
+
+// ApplyDuePriceChanges makes scheduled prices whose time has come the
+// current price of their item, the latest one winning when several are due,
+// and returns how many items changed price.
+func (r *ReservationRepo) ApplyDuePriceChanges(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ApplyDuePriceChanges", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := r.DB.ExecContext(ctx, `
+		WITH due AS (
+			UPDATE
+				MenuPriceChanges
+			SET
+				applied_at = CURRENT_TIMESTAMP
+			WHERE
+				applied_at IS NULL AND effective_at <= CURRENT_TIMESTAMP
+			RETURNING
+				menu_item_id,
+				price_minor,
+				effective_at,
+				created_at
+		)
+		UPDATE
+			Menu m
+		SET
+			price_minor = d.price_minor,
+			updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT DISTINCT ON (menu_item_id)
+				menu_item_id,
+				price_minor
+			FROM
+				due
+			ORDER BY
+				menu_item_id, effective_at DESC, created_at DESC
+		) d
+		WHERE
+			m.id = d.menu_item_id
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply price changes: %v", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply price changes: %v", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParsePriceEffectiveAt(t *testing.T) {
+	assert.NoError(t, parsePriceEffectiveAt(""))
+	assert.NoError(t, parsePriceEffectiveAt("2030-01-01 09:00:00"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(parsePriceEffectiveAt("2030-01-01T09:00:00Z")))
+}
+
+func TestMenuPriceHistory(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	ctx := context.Background()
+
+	item, err := repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Price history dish",
+		Price:        &pb.Money{MinorUnits: 1000},
+	})
+	assert.NoError(t, err)
+	id := item.MenuItem.Id
+	defer repo.DeleteMenuItem(ctx, &pb.DeleteMenuItemRequest{Id: id})
+
+	res, err := repo.CreateReservation(ctx, &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-02 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, saveTestOrder(&repo, res.Reservation.Id, restaurantId, id, 2))
+
+	update := &pb.UpdateMenuItemRequest{
+		Id:           id,
+		RestaurantId: restaurantId,
+		Name:         "Price history dish",
+		Price:        &pb.Money{MinorUnits: 1200},
+	}
+	updated, err := repo.UpdateMenuItem(ctx, update)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1200), updated.MenuItem.Price.MinorUnits)
+
+	bill, err := repo.GetBill(ctx, &pb.GetBillRequest{ReservationId: res.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), bill.Bill.Subtotal.MinorUnits)
+
+	update.Price = &pb.Money{MinorUnits: 1500}
+	update.PriceEffectiveAt = "2099-01-01 00:00:00"
+	updated, err = repo.UpdateMenuItem(ctx, update)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1200), updated.MenuItem.Price.MinorUnits)
+
+	update.PriceEffectiveAt = "tomorrow"
+	_, err = repo.UpdateMenuItem(ctx, update)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	history, err := repo.GetMenuItemPriceHistory(ctx, &pb.GetMenuItemPriceHistoryRequest{MenuItemId: id})
+	assert.NoError(t, err)
+	if assert.Len(t, history.History, 2) {
+		assert.Equal(t, int64(1200), history.History[0].Price.MinorUnits)
+		assert.Equal(t, int64(1000), history.History[1].Price.MinorUnits)
+	}
+	if assert.Len(t, history.Scheduled, 1) {
+		assert.Equal(t, int64(1500), history.Scheduled[0].Price.MinorUnits)
+		assert.Equal(t, "2099-01-01 00:00:00", history.Scheduled[0].EffectiveAt)
+		assert.Empty(t, history.Scheduled[0].AppliedAt)
+
+		_, err = repo.CancelMenuPriceChange(ctx, &pb.CancelMenuPriceChangeRequest{Id: history.Scheduled[0].Id})
+		assert.NoError(t, err)
+		_, err = repo.CancelMenuPriceChange(ctx, &pb.CancelMenuPriceChangeRequest{Id: history.Scheduled[0].Id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, scheduleMenuPrice(ctx, tx, id, 1300, "2000-01-01 00:00:00"))
+	assert.NoError(t, tx.Commit())
+	n, err := repo.ApplyDuePriceChanges(ctx)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+
+	got, err := repo.GetMenuItem(ctx, &pb.GetMenuItemRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1300), got.MenuItem.Price.MinorUnits)
+
+	history, err = repo.GetMenuItemPriceHistory(ctx, &pb.GetMenuItemPriceHistoryRequest{MenuItemId: id})
+	assert.NoError(t, err)
+	assert.Len(t, history.History, 3)
+	assert.Empty(t, history.Scheduled)
+
+	_, err = repo.GetMenuItemPriceHistory(ctx, &pb.GetMenuItemPriceHistoryRequest{MenuItemId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
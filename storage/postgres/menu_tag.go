@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Menu tag kinds.
+const (
+	TagAllergen = "allergen"
+	TagDietary  = "dietary"
+)
+
+// normalizeTags lowercases tag codes, drops empty and repeated ones and sorts
+// them.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// checkMenuTags normalizes tag codes and rejects unknown ones, or ones of
+// another kind if kind is set.
+func checkMenuTags(ctx context.Context, q queryer, kind string, tags []string) ([]string, error) {
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			code
+		FROM
+			MenuTags
+		WHERE
+			code = ANY($1) AND ($2 = '' OR kind = $2)
+	`, pq.Array(tags), kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check menu tags: %v", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]bool, len(tags))
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan menu tag: %v", err)
+		}
+		known[code] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check menu tags: %v", err)
+	}
+	for _, tag := range tags {
+		if !known[tag] {
+			if kind == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "unknown tag %q", tag)
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "unknown %s tag %q", kind, tag)
+		}
+	}
+	return tags, nil
+}
+
+// saveMenuItemTags replaces the allergens and dietary tags of an item and
+// returns them normalized.
+func saveMenuItemTags(ctx context.Context, tx *sql.Tx, menuItemId string, allergens, dietary []string) ([]string, []string, error) {
+	allergens, err := checkMenuTags(ctx, tx, TagAllergen, allergens)
+	if err != nil {
+		return nil, nil, err
+	}
+	dietary, err = checkMenuTags(ctx, tx, TagDietary, dietary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM MenuItemTags WHERE menu_item_id = $1`, menuItemId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save menu item tags: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO MenuItemTags (
+			menu_item_id,
+			tag_code
+		)
+		SELECT
+			$1,
+			unnest($2::text[])
+	`, menuItemId, pq.Array(slices.Concat(allergens, dietary)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save menu item tags: %v", err)
+	}
+	return allergens, dietary, nil
+}
+
+// SetMenuItemTags replaces the allergens and dietary tags of a menu item.
+func (r *ReservationRepo) SetMenuItemTags(ctx context.Context, req *pb.SetMenuItemTagsRequest) (_ *pb.SetMenuItemTagsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetMenuItemTags", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set menu item tags: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE
+			Menu
+		SET
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0
+	`, req.MenuItemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set menu item tags: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "menu item not found")
+	}
+	if _, _, err = saveMenuItemTags(ctx, tx, req.MenuItemId, req.Allergens, req.DietaryTags); err != nil {
+		return nil, err
+	}
+
+	item, err := scanMenuItem(tx.QueryRowContext(ctx, `
+		SELECT
+			`+menuItemColumns+`
+		FROM
+			Menu
+		WHERE
+			id = $1
+	`, req.MenuItemId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set menu item tags: %v", err)
+	}
+	if err = attachModifierGroups(ctx, tx, item); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set menu item tags: %v", err)
+	}
+	return &pb.SetMenuItemTagsResponse{MenuItem: item}, nil
+}
+
+func (r *ReservationRepo) ListMenuTags(ctx context.Context, req *pb.ListMenuTagsRequest) (_ *pb.ListMenuTagsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListMenuTags", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			code,
+			kind,
+			name
+		FROM
+			MenuTags
+		WHERE
+			$1 = '' OR kind = $1
+		ORDER BY
+			kind, name
+	`, req.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list menu tags: %v", err)
+	}
+	defer rows.Close()
+
+	var tags []*pb.MenuTag
+	for rows.Next() {
+		tag := &pb.MenuTag{}
+		if err := rows.Scan(&tag.Code, &tag.Kind, &tag.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan menu tag: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list menu tags: %v", err)
+	}
+	return &pb.ListMenuTagsResponse{Tags: tags}, nil
+}
+
+// saveDietaryNotes replaces the dietary notes of a reservation and returns
+// them normalized.
+func saveDietaryNotes(ctx context.Context, tx *sql.Tx, reservationId string, notes []string) ([]string, error) {
+	notes, err := checkMenuTags(ctx, tx, "", notes)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM ReservationDietaryNotes WHERE reservation_id = $1`, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save dietary notes: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ReservationDietaryNotes (
+			reservation_id,
+			tag_code
+		)
+		SELECT
+			$1,
+			unnest($2::text[])
+	`, reservationId, pq.Array(notes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save dietary notes: %v", err)
+	}
+	return notes, nil
+}
+
+// loadDietaryNotes returns the tags noted on a reservation.
+func loadDietaryNotes(ctx context.Context, q queryer, reservationId string) ([]*pb.MenuTag, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			t.code,
+			t.kind,
+			t.name
+		FROM
+			ReservationDietaryNotes n
+			JOIN MenuTags t ON t.code = n.tag_code
+		WHERE
+			n.reservation_id = $1
+		ORDER BY
+			t.code
+	`, reservationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dietary notes: %v", err)
+	}
+	defer rows.Close()
+
+	var notes []*pb.MenuTag
+	for rows.Next() {
+		note := &pb.MenuTag{}
+		if err := rows.Scan(&note.Code, &note.Kind, &note.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan dietary note: %v", err)
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get dietary notes: %v", err)
+	}
+	return notes, nil
+}
+
+// DietaryWarnings lists the conflicts between ordered items and the dietary
+// notes of a reservation: items containing a noted allergen and items not
+// tagged with a noted diet.
+func DietaryWarnings(notes []*pb.MenuTag, items []*pb.MenuItem) []*pb.DietaryWarning {
+	var warnings []*pb.DietaryWarning
+	for _, item := range items {
+		for _, note := range notes {
+			switch {
+			case note.Kind == TagAllergen && slices.Contains(item.Allergens, note.Code):
+				warnings = append(warnings, &pb.DietaryWarning{
+					MenuItemId: item.Id,
+					Tag:        note.Code,
+					Message:    fmt.Sprintf("%s contains %s", item.Name, strings.ToLower(note.Name)),
+				})
+			case note.Kind == TagDietary && !slices.Contains(item.DietaryTags, note.Code):
+				warnings = append(warnings, &pb.DietaryWarning{
+					MenuItemId: item.Id,
+					Tag:        note.Code,
+					Message:    fmt.Sprintf("%s is not marked %s", item.Name, strings.ToLower(note.Name)),
+				})
+			}
+		}
+	}
+	return warnings
+}
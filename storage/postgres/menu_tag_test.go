@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDietaryWarnings(t *testing.T) {
+	assert.Equal(t, []string{"peanuts", "vegan"}, normalizeTags([]string{" Vegan", "peanuts", "", "VEGAN"}))
+	assert.Empty(t, normalizeTags(nil))
+
+	peanuts := &pb.MenuTag{Code: "peanuts", Kind: TagAllergen, Name: "Peanuts"}
+	vegan := &pb.MenuTag{Code: "vegan", Kind: TagDietary, Name: "Vegan"}
+	items := []*pb.MenuItem{
+		{Id: "satay", Name: "Satay", Allergens: []string{"peanuts", "soybeans"}},
+		{Id: "salad", Name: "Salad", DietaryTags: []string{"vegan", "vegetarian"}},
+	}
+
+	warnings := DietaryWarnings([]*pb.MenuTag{peanuts, vegan}, items)
+	assert.Equal(t, []*pb.DietaryWarning{
+		{MenuItemId: "satay", Tag: "peanuts", Message: "Satay contains peanuts"},
+		{MenuItemId: "satay", Tag: "vegan", Message: "Satay is not marked vegan"},
+	}, warnings)
+
+	assert.Empty(t, DietaryWarnings(nil, items))
+	assert.Empty(t, DietaryWarnings([]*pb.MenuTag{vegan}, items[1:]))
+}
+
+func TestMenuTags(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	_, err = repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Tag test dish",
+		Price:        &pb.Money{MinorUnits: 1200},
+		Allergens:    []string{"vegan"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	satay, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Tag test satay",
+		Price:        &pb.Money{MinorUnits: 1200},
+		Allergens:    []string{"Peanuts"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peanuts"}, satay.MenuItem.Allergens)
+
+	salad, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Tag test salad",
+		Price:        &pb.Money{MinorUnits: 900},
+	})
+	assert.NoError(t, err)
+	tagged, err := repo.SetMenuItemTags(context.Background(), &pb.SetMenuItemTagsRequest{
+		MenuItemId:  salad.MenuItem.Id,
+		DietaryTags: []string{"vegan", "vegetarian"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vegan", "vegetarian"}, tagged.MenuItem.DietaryTags)
+
+	list, err := repo.ListMenuItems(context.Background(), &pb.ListMenuItemsRequest{
+		RestaurantId: restaurantId,
+		IncludeTags:  []string{"vegan", "vegetarian"},
+		ExcludeTags:  []string{"peanuts"},
+	})
+	assert.NoError(t, err)
+	var ids []string
+	for _, item := range list.MenuItems {
+		ids = append(ids, item.Id)
+	}
+	assert.Contains(t, ids, salad.MenuItem.Id)
+	assert.NotContains(t, ids, satay.MenuItem.Id)
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-03 19:00:00",
+		Status:          "Confirmed",
+		DietaryNotes:    []string{"vegan", "peanuts"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peanuts", "vegan"}, res.Reservation.DietaryNotes)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	warnings, err := orderWarnings(context.Background(), tx, res.Reservation.Id, []*pb.MealOrder{
+		{MenuItemId: satay.MenuItem.Id, Quantity: 1},
+		{MenuItemId: salad.MenuItem.Id, Quantity: 1},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 2)
+	tx.Rollback()
+
+	updated, err := repo.UpdateReservation(context.Background(), &pb.UpdateReservationRequest{
+		Id:              res.Reservation.Id,
+		UserId:          res.Reservation.UserId,
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-03 19:00:00",
+		DietaryNotes:    []string{"peanuts"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peanuts"}, updated.Reservation.DietaryNotes)
+
+	updated, err = repo.UpdateReservation(context.Background(), &pb.UpdateReservationRequest{
+		Id:              res.Reservation.Id,
+		UserId:          res.Reservation.UserId,
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-03 19:00:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peanuts"}, updated.Reservation.DietaryNotes)
+
+	updated, err = repo.UpdateReservation(context.Background(), &pb.UpdateReservationRequest{
+		Id:                 res.Reservation.Id,
+		UserId:             res.Reservation.UserId,
+		RestaurantId:       restaurantId,
+		ReservationTime:    "2030-08-03 19:00:00",
+		UpdateDietaryNotes: true,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Reservation.DietaryNotes)
+
+	for _, id := range []string{satay.MenuItem.Id, salad.MenuItem.Id} {
+		_, err = repo.DeleteMenuItem(context.Background(), &pb.DeleteMenuItemRequest{Id: id})
+		assert.NoError(t, err)
+	}
+}
@@ -1,29 +1,29 @@
 package postgres
 
 import (
+	"context"
 	pb "reservation-service/generated/reservation_service"
-	"reservation-service/storage/redis"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateMenuItem(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	menuRepo := NewRRestaurantRepo(db, r)
 
 	menu := pb.CreateMenuItemRequest{
 		RestaurantId: "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
 		Name:         "Osh",
 		Description:  "Very good",
-		Price:        25000,
+		Price:        &pb.Money{MinorUnits: 25000},
 	}
-	res, err := menuRepo.CreateMenuItem(&menu)
+	res, err := menuRepo.CreateMenuItem(context.Background(), &menu)
 	if err != nil {
 		t.Errorf("failed to created menuItem : %v", err)
 		return
@@ -33,24 +33,24 @@ func TestCreateMenuItem(t *testing.T) {
 	assert.Equal(t, menu.RestaurantId, res.MenuItem.RestaurantId)
 	assert.Equal(t, menu.Name, res.MenuItem.Name)
 	assert.Equal(t, menu.Description, res.MenuItem.Description)
-	assert.Equal(t, menu.Price, res.MenuItem.Price)
+	assert.Equal(t, menu.Price.MinorUnits, res.MenuItem.Price.MinorUnits)
 
 	assert.NotEmpty(t, res.MenuItem.Id)
 }
 
 func TestListMenuItems(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	menuRepo := NewRRestaurantRepo(db, r)
 	reqMenu := pb.ListMenuItemsRequest{
 		RestaurantId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
 		Name:         "Osh",
 	}
-	listMenu, err := menuRepo.ListMenuItems(&reqMenu)
+	listMenu, err := menuRepo.ListMenuItems(context.Background(), &reqMenu)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -65,17 +65,17 @@ func TestListMenuItems(t *testing.T) {
 }
 
 func TestGetMenuItem(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	menuRepo := NewRRestaurantRepo(db, r)
 	id := pb.GetMenuItemRequest{
 		Id: "be933d44-3822-43f0-bcde-940bfb724dff",
 	}
-	menu, err := menuRepo.GetMenuItem(&id)
+	menu, err := menuRepo.GetMenuItem(context.Background(), &id)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -87,21 +87,21 @@ func TestGetMenuItem(t *testing.T) {
 }
 
 func TestUpdateMenuItem(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	menuRepo := NewRRestaurantRepo(db, r)
 	menu := pb.UpdateMenuItemRequest{
 		Id:           "be933d44-3822-43f0-bcde-940bfb724dff",
 		RestaurantId: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
 		Name:         "Mastava",
 		Description:  "dsfa",
-		Price:        13000,
+		Price:        &pb.Money{MinorUnits: 13000},
 	}
-	updateMenu, err := menuRepo.UpdateMenuItem(&menu)
+	updateMenu, err := menuRepo.UpdateMenuItem(context.Background(), &menu)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -111,21 +111,21 @@ func TestUpdateMenuItem(t *testing.T) {
 	assert.Equal(t, menu.RestaurantId, updateMenu.MenuItem.RestaurantId)
 	assert.Equal(t, menu.Name, updateMenu.MenuItem.Name)
 	assert.Equal(t, menu.Description, updateMenu.MenuItem.Description)
-	assert.Equal(t, menu.Price, updateMenu.MenuItem.Price)
+	assert.Equal(t, menu.Price.MinorUnits, updateMenu.MenuItem.Price.MinorUnits)
 }
 
 func TestDeleteMenuItem(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	menuRepo := NewRRestaurantRepo(db, r)
 	id := pb.DeleteMenuItemRequest{
 		Id: "903cca44-1f9e-487f-9529-ecc06173f042",
 	}
-	res, err := menuRepo.DeleteMenuItem(&id)
+	res, err := menuRepo.DeleteMenuItem(context.Background(), &id)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -133,3 +133,23 @@ func TestDeleteMenuItem(t *testing.T) {
 
 	assert.NotEmpty(t, res)
 }
+
+func TestRestoreMenuItem(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	r := connectTestRedis()
+	menuRepo := NewRRestaurantRepo(db, r)
+	id := pb.RestoreMenuItemRequest{
+		Id: "903cca44-1f9e-487f-9529-ecc06173f042",
+	}
+	res, err := menuRepo.RestoreMenuItem(context.Background(), &id)
+	if err != nil {
+		t.Errorf("ERROR : %v", err)
+		return
+	}
+
+	assert.Equal(t, id.Id, res.MenuItem.Id)
+}
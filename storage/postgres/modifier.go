@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SelectModifierOptions checks the options chosen for an item against its
+// modifier groups and returns them in menu order. Every option must belong to
+// one of the groups, none may be chosen twice, and each group needs between
+// its min_selections and max_selections options.
+func SelectModifierOptions(groups []*pb.ModifierGroup, optionIds []string) ([]*pb.ModifierOption, error) {
+	chosen := make(map[string]bool, len(optionIds))
+	for _, id := range optionIds {
+		if chosen[id] {
+			return nil, status.Errorf(codes.InvalidArgument, "option %s is chosen more than once", id)
+		}
+		chosen[id] = true
+	}
+
+	var selected []*pb.ModifierOption
+	for _, group := range groups {
+		var n int32
+		for _, option := range group.Options {
+			if chosen[option.Id] {
+				selected = append(selected, option)
+				delete(chosen, option.Id)
+				n++
+			}
+		}
+		if n < group.MinSelections || n > group.MaxSelections {
+			if group.MinSelections == group.MaxSelections {
+				return nil, status.Errorf(codes.InvalidArgument, "choose %d of %q", group.MinSelections, group.Name)
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "choose between %d and %d of %q",
+				group.MinSelections, group.MaxSelections, group.Name)
+		}
+	}
+	for _, id := range optionIds {
+		if chosen[id] {
+			return nil, status.Errorf(codes.InvalidArgument, "option %s is not an option of the menu item", id)
+		}
+	}
+	return selected, nil
+}
+
+// optionsKey identifies a set of chosen options regardless of their order.
+func optionsKey(optionIds []string) string {
+	ids := append([]string(nil), optionIds...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func validateModifierGroup(group *pb.ModifierGroup) error {
+	if group.Name == "" {
+		return status.Error(codes.InvalidArgument, "modifier group name is required")
+	}
+	if len(group.Options) == 0 {
+		return status.Errorf(codes.InvalidArgument, "modifier group %q has no options", group.Name)
+	}
+	if group.MinSelections < 0 || group.MaxSelections < 1 || group.MinSelections > group.MaxSelections {
+		return status.Errorf(codes.InvalidArgument, "modifier group %q needs 0 <= min_selections <= max_selections and max_selections >= 1", group.Name)
+	}
+	if int(group.MaxSelections) > len(group.Options) {
+		return status.Errorf(codes.InvalidArgument, "modifier group %q allows more selections than it has options", group.Name)
+	}
+	for _, option := range group.Options {
+		if option.Name == "" {
+			return status.Errorf(codes.InvalidArgument, "an option of modifier group %q has no name", group.Name)
+		}
+	}
+	return nil
+}
+
+// loadModifierGroups returns the modifier groups of the given menu items,
+// keyed by item id.
+func loadModifierGroups(ctx context.Context, q queryer, menuItemIds []string) (map[string][]*pb.ModifierGroup, error) {
+	groups := make(map[string][]*pb.ModifierGroup)
+	if len(menuItemIds) == 0 {
+		return groups, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			g.menu_item_id,
+			g.id,
+			g.name,
+			g.min_selections,
+			g.max_selections,
+			o.id,
+			o.name,
+			o.price_delta_minor,
+			rs.currency
+		FROM
+			ModifierGroups g
+			JOIN ModifierOptions o ON o.group_id = g.id
+			JOIN Menu m ON m.id = g.menu_item_id
+			JOIN Restaurants rs ON rs.id = m.restaurant_id
+		WHERE
+			g.menu_item_id = ANY($1::uuid[])
+		ORDER BY
+			g.menu_item_id, g.position, o.position
+	`, pq.Array(menuItemIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifier groups: %v", err)
+	}
+	defer rows.Close()
+
+	var last *pb.ModifierGroup
+	for rows.Next() {
+		var (
+			menuItemId string
+			group      = &pb.ModifierGroup{}
+			option     = &pb.ModifierOption{PriceDelta: &pb.Money{}}
+		)
+		err := rows.Scan(&menuItemId, &group.Id, &group.Name, &group.MinSelections, &group.MaxSelections,
+			&option.Id, &option.Name, &option.PriceDelta.MinorUnits, &option.PriceDelta.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan modifier option: %v", err)
+		}
+		if last == nil || last.Id != group.Id {
+			groups[menuItemId] = append(groups[menuItemId], group)
+			last = group
+		}
+		last.Options = append(last.Options, option)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get modifier groups: %v", err)
+	}
+	return groups, nil
+}
+
+// attachModifierGroups fills in the modifier groups of the items.
+func attachModifierGroups(ctx context.Context, q queryer, items ...*pb.MenuItem) error {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+	groups, err := loadModifierGroups(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		item.ModifierGroups = groups[item.Id]
+	}
+	return nil
+}
+
+// SetMenuItemModifiers replaces the modifier groups of a menu item. Lines
+// already ordered keep the options and prices they were ordered with.
+func (r *ReservationRepo) SetMenuItemModifiers(ctx context.Context, req *pb.SetMenuItemModifiersRequest) (_ *pb.SetMenuItemModifiersResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetMenuItemModifiers", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	for _, group := range req.Groups {
+		if err = validateModifierGroup(group); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set modifiers: %v", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanMenuItem(tx.QueryRowContext(ctx, `
+		SELECT
+			`+menuItemColumns+`
+		FROM
+			Menu
+		WHERE
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, req.MenuItemId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "menu item not found")
+		}
+		return nil, fmt.Errorf("failed to set modifiers: %v", err)
+	}
+	for _, group := range req.Groups {
+		for _, option := range group.Options {
+			if err = checkMoney(ctx, tx, item.RestaurantId, "price_delta", option.PriceDelta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM ModifierGroups WHERE menu_item_id = $1`, req.MenuItemId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set modifiers: %v", err)
+	}
+	for i, group := range req.Groups {
+		var groupId string
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO ModifierGroups (
+				id,
+				menu_item_id,
+				name,
+				min_selections,
+				max_selections,
+				position
+			)
+			VALUES (
+				COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()),
+				$2,
+				$3,
+				$4,
+				$5,
+				$6
+			)
+			RETURNING
+				id
+		`, group.Id, req.MenuItemId, group.Name, group.MinSelections, group.MaxSelections, i).Scan(&groupId)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return nil, status.Errorf(codes.InvalidArgument, "modifier group id %s belongs to another menu item", group.Id)
+			}
+			return nil, fmt.Errorf("failed to set modifiers: %v", err)
+		}
+
+		for j, option := range group.Options {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO ModifierOptions (
+					id,
+					group_id,
+					name,
+					price_delta_minor,
+					position
+				)
+				VALUES (
+					COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()),
+					$2,
+					$3,
+					$4,
+					$5
+				)
+			`, option.Id, groupId, option.Name, option.PriceDelta.GetMinorUnits(), j)
+			if err != nil {
+				if isUniqueViolation(err) {
+					return nil, status.Errorf(codes.InvalidArgument, "option id %s belongs to another modifier group", option.Id)
+				}
+				return nil, fmt.Errorf("failed to set modifiers: %v", err)
+			}
+		}
+	}
+
+	if err = attachModifierGroups(ctx, tx, item); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set modifiers: %v", err)
+	}
+	return &pb.SetMenuItemModifiersResponse{MenuItem: item}, nil
+}
+
+// saveOrderLineOptions records the options of an order line with their
+// current price deltas.
+func saveOrderLineOptions(ctx context.Context, tx *sql.Tx, orderId string, options []*pb.ModifierOption) error {
+	for i, option := range options {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ReservationOrderOptions (
+				order_id,
+				option_id,
+				name,
+				price_delta_minor,
+				position
+			)
+			VALUES (
+				$1,
+				$2,
+				$3,
+				$4,
+				$5
+			)
+		`, orderId, option.Id, option.Name, option.PriceDelta.GetMinorUnits(), i)
+		if err != nil {
+			return fmt.Errorf("failed to save order options: %v", err)
+		}
+	}
+	return nil
+}
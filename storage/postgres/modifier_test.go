@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSelectModifierOptions(t *testing.T) {
+	size := &pb.ModifierGroup{Id: "size", Name: "Size", MinSelections: 1, MaxSelections: 1, Options: []*pb.ModifierOption{
+		{Id: "small", Name: "Small"},
+		{Id: "large", Name: "Large", PriceDelta: &pb.Money{MinorUnits: 300}},
+	}}
+	extras := &pb.ModifierGroup{Id: "extras", Name: "Extras", MinSelections: 0, MaxSelections: 2, Options: []*pb.ModifierOption{
+		{Id: "cheese", Name: "Cheese", PriceDelta: &pb.Money{MinorUnits: 100}},
+		{Id: "egg", Name: "Egg", PriceDelta: &pb.Money{MinorUnits: 150}},
+		{Id: "onion", Name: "Onion"},
+	}}
+	groups := []*pb.ModifierGroup{size, extras}
+
+	tests := []struct {
+		name      string
+		optionIds []string
+		want      []string
+		code      codes.Code
+	}{
+		{"required only", []string{"large"}, []string{"large"}, codes.OK},
+		{"menu order", []string{"egg", "small", "cheese"}, []string{"small", "cheese", "egg"}, codes.OK},
+		{"missing required", []string{"cheese"}, nil, codes.InvalidArgument},
+		{"too many in group", []string{"small", "large"}, nil, codes.InvalidArgument},
+		{"over max", []string{"small", "cheese", "egg", "onion"}, nil, codes.InvalidArgument},
+		{"unknown option", []string{"small", "bacon"}, nil, codes.InvalidArgument},
+		{"duplicate option", []string{"small", "cheese", "cheese"}, nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectModifierOptions(groups, tt.optionIds)
+			assert.Equal(t, tt.code, status.Code(err))
+			var ids []string
+			for _, option := range selected {
+				ids = append(ids, option.Id)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	selected, err := SelectModifierOptions(nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, selected)
+	assert.Equal(t, optionsKey([]string{"b", "a"}), optionsKey([]string{"a", "b"}))
+}
+
+func TestMenuItemModifiers(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	item, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Modifier test steak",
+		Price:        &pb.Money{MinorUnits: 2000},
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.SetMenuItemModifiers(context.Background(), &pb.SetMenuItemModifiersRequest{
+		MenuItemId: item.MenuItem.Id,
+		Groups:     []*pb.ModifierGroup{{Name: "Doneness", MinSelections: 2, MaxSelections: 1}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	set, err := repo.SetMenuItemModifiers(context.Background(), &pb.SetMenuItemModifiersRequest{
+		MenuItemId: item.MenuItem.Id,
+		Groups: []*pb.ModifierGroup{
+			{Name: "Doneness", MinSelections: 1, MaxSelections: 1, Options: []*pb.ModifierOption{{Name: "Rare"}, {Name: "Medium"}}},
+			{Name: "Extras", MaxSelections: 1, Options: []*pb.ModifierOption{{Name: "Pepper sauce", PriceDelta: &pb.Money{MinorUnits: 250}}}},
+		},
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, set.MenuItem.ModifierGroups, 2) {
+		return
+	}
+	rare := set.MenuItem.ModifierGroups[0].Options[0].Id
+	sauce := set.MenuItem.ModifierGroups[1].Options[0].Id
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-08-02 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	err = saveOrderLines(context.Background(), tx, res.Reservation.Id, restaurantId, []*pb.MealOrder{{MenuItemId: item.MenuItem.Id, Quantity: 1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	tx.Rollback()
+
+	tx, err = db.Begin()
+	assert.NoError(t, err)
+	err = saveOrderLines(context.Background(), tx, res.Reservation.Id, restaurantId, []*pb.MealOrder{
+		{MenuItemId: item.MenuItem.Id, Quantity: 2, OptionIds: []string{sauce, rare}},
+		{MenuItemId: item.MenuItem.Id, Quantity: 1, OptionIds: []string{rare}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	bill, err := repo.GetBill(context.Background(), &pb.GetBillRequest{ReservationId: res.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Len(t, bill.Bill.Lines, 2)
+	assert.Equal(t, int64(2000+2*2250), bill.Bill.Subtotal.MinorUnits)
+
+	_, err = repo.DeleteMenuItem(context.Background(), &pb.DeleteMenuItemRequest{Id: item.MenuItem.Id})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/geo"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultNearbyRadius = 5000
+	maxNearbyRadius     = 50000
+	defaultNearbyLimit  = 20
+	maxNearbyLimit      = 100
+)
+
+func validateOpeningHours(hours *pb.OpeningHours) error {
+	if hours.DayOfWeek < -1 || hours.DayOfWeek > 6 {
+		return status.Error(codes.InvalidArgument, "day_of_week must be between 0 and 6, or -1 for every day")
+	}
+	opens, err := time.Parse(slotTimeLayout, hours.OpenTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid open_time %q, use HH:MM", hours.OpenTime)
+	}
+	closes, err := time.Parse(slotTimeLayout, hours.CloseTime)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid close_time %q, use HH:MM", hours.CloseTime)
+	}
+	if opens.Equal(closes) {
+		return status.Error(codes.InvalidArgument, "close_time must differ from open_time")
+	}
+	return nil
+}
+
+// SetOpeningHours replaces the opening hours of a restaurant.
+func (r *ReservationRepo) SetOpeningHours(ctx context.Context, req *pb.SetOpeningHoursRequest) (_ *pb.SetOpeningHoursResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetOpeningHours", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	for _, hours := range req.Hours {
+		if err = validateOpeningHours(hours); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set opening hours: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = restaurantCurrency(ctx, tx, req.RestaurantId); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM RestaurantOpeningHours WHERE restaurant_id = $1`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set opening hours: %v", err)
+	}
+	for _, hours := range req.Hours {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO RestaurantOpeningHours (
+				restaurant_id,
+				day_of_week,
+				open_time,
+				close_time
+			)
+			VALUES (
+				$1,
+				$2,
+				$3,
+				$4
+			)
+		`, req.RestaurantId, hours.DayOfWeek, hours.OpenTime, hours.CloseTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set opening hours: %v", err)
+		}
+	}
+
+	restaurant := &pb.Restaurant{Id: req.RestaurantId}
+	if err = attachOpeningHours(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set opening hours: %v", err)
+	}
+	return &pb.SetOpeningHoursResponse{Hours: restaurant.OpeningHours}, nil
+}
+
+// attachOpeningHours fills in the opening hours of the restaurants.
+func attachOpeningHours(ctx context.Context, q queryer, restaurants ...*pb.Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+	ids := make([]string, len(restaurants))
+	byId := make(map[string]*pb.Restaurant, len(restaurants))
+	for i, restaurant := range restaurants {
+		ids[i] = restaurant.Id
+		byId[restaurant.Id] = restaurant
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			restaurant_id,
+			day_of_week,
+			to_char(open_time, 'HH24:MI'),
+			to_char(close_time, 'HH24:MI')
+		FROM
+			RestaurantOpeningHours
+		WHERE
+			restaurant_id = ANY($1::uuid[])
+		ORDER BY
+			restaurant_id, day_of_week, open_time
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get opening hours: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var restaurantId string
+		hours := &pb.OpeningHours{}
+		if err := rows.Scan(&restaurantId, &hours.DayOfWeek, &hours.OpenTime, &hours.CloseTime); err != nil {
+			return fmt.Errorf("failed to scan opening hours: %v", err)
+		}
+		byId[restaurantId].OpeningHours = append(byId[restaurantId].OpeningHours, hours)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get opening hours: %v", err)
+	}
+	return nil
+}
+
+// ListNearbyRestaurants returns the active restaurants within a radius of a
+// point, nearest first. A bounding box on the indexed coordinates narrows
+// the restaurants before the exact haversine distance is computed.
+func (r *ReservationRepo) ListNearbyRestaurants(ctx context.Context, req *pb.ListNearbyRestaurantsRequest) (_ *pb.ListNearbyRestaurantsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListNearbyRestaurants", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	center := geo.Point{Lat: req.Latitude, Lng: req.Longitude}
+	if !center.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	radius := req.RadiusMeters
+	if radius <= 0 {
+		radius = defaultNearbyRadius
+	}
+	if radius > maxNearbyRadius {
+		return nil, status.Errorf(codes.InvalidArgument, "radius_meters must be at most %d", maxNearbyRadius)
+	}
+	if req.OpenAt != "" {
+		if _, err = time.Parse(menuTimeLayout, req.OpenAt); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "open_at must be formatted as YYYY-MM-DD HH:MM:SS")
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+	limit = min(limit, maxNearbyLimit)
+	sw, ne := geo.BoundingBox(center, radius)
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			`+restaurantColumns+`,
+			distance,
+			open_now
+		FROM (
+			SELECT
+				`+restaurantColumns+`,
+				2 * $3::float8 * asin(sqrt(least(1,
+					power(sin(radians(latitude - $1) / 2), 2) +
+					cos(radians($1)) * cos(radians(latitude)) * power(sin(radians(longitude - $2) / 2), 2)
+				))) AS distance,
+				restaurant_open_at(id, (CURRENT_TIMESTAMP AT TIME ZONE timezone)::timestamp) AS open_now
+			FROM
+				Restaurants
+			WHERE
+				deleted_at = 0 AND is_active
+				AND latitude BETWEEN $4 AND $5
+				AND longitude BETWEEN $6 AND $7
+		) nearby
+		WHERE
+			distance <= $8
+			AND (NOT $9 OR open_now)
+			AND ($10 = '' OR restaurant_open_at(id, NULLIF($10, '')::timestamp))
+		ORDER BY
+			distance, id
+		LIMIT $11 OFFSET $12
+	`, center.Lat, center.Lng, float64(geo.EarthRadius), sw.Lat, ne.Lat, sw.Lng, ne.Lng,
+		radius, req.OpenNow, req.OpenAt, limit, max(req.Offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nearby restaurants: %v", err)
+	}
+	defer rows.Close()
+
+	res := &pb.ListNearbyRestaurantsResponse{}
+	var restaurants []*pb.Restaurant
+	for rows.Next() {
+		nearby := &pb.NearbyRestaurant{}
+		nearby.Restaurant, err = scanRestaurant(rows, &nearby.DistanceMeters, &nearby.OpenNow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan restaurant: %v", err)
+		}
+		res.Restaurants = append(res.Restaurants, nearby)
+		restaurants = append(restaurants, nearby.Restaurant)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list nearby restaurants: %v", err)
+	}
+	if err = attachRestaurantTranslations(ctx, r.DB, restaurants...); err != nil {
+		return nil, err
+	}
+	if err = attachOpeningHours(ctx, r.DB, restaurants...); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, r.DB, restaurants...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateOpeningHours(t *testing.T) {
+	assert.NoError(t, validateOpeningHours(&pb.OpeningHours{DayOfWeek: -1, OpenTime: "09:00", CloseTime: "22:00"}))
+	assert.NoError(t, validateOpeningHours(&pb.OpeningHours{DayOfWeek: 5, OpenTime: "18:00", CloseTime: "02:00"}))
+	for _, hours := range []*pb.OpeningHours{
+		{DayOfWeek: 7, OpenTime: "09:00", CloseTime: "22:00"},
+		{DayOfWeek: 1, OpenTime: "9am", CloseTime: "22:00"},
+		{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "09:00"},
+	} {
+		assert.Equal(t, codes.InvalidArgument, status.Code(validateOpeningHours(hours)), "%v", hours)
+	}
+}
+
+func TestListNearbyRestaurants(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	ctx := context.Background()
+
+	// Far out in the Pacific, so that no other restaurant is near.
+	center := &pb.GeoPoint{Latitude: -48.87, Longitude: -123.39}
+	var ids []string
+	for i, location := range []*pb.GeoPoint{
+		{Latitude: -48.88, Longitude: -123.39},
+		{Latitude: -48.87, Longitude: -123.391},
+		{Latitude: -48.2, Longitude: -123.39},
+	} {
+		created, err := repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{
+			Name:     []string{"Nearby test far", "Nearby test near", "Nearby test away"}[i],
+			Address:  "Pacific Ocean",
+			Location: location,
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		ids = append(ids, created.Restaurant.Id)
+		defer repo.DeleteRestaurant(ctx, &pb.DeleteRestaurantRequest{Id: created.Restaurant.Id, Force: true})
+	}
+
+	res, err := repo.ListNearbyRestaurants(ctx, &pb.ListNearbyRestaurantsRequest{
+		Latitude:     center.Latitude,
+		Longitude:    center.Longitude,
+		RadiusMeters: 5000,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Restaurants, 2) {
+		assert.Equal(t, ids[1], res.Restaurants[0].Restaurant.Id)
+		assert.InDelta(t, 73, res.Restaurants[0].DistanceMeters, 5)
+		assert.Equal(t, ids[0], res.Restaurants[1].Restaurant.Id)
+		assert.InDelta(t, 1112, res.Restaurants[1].DistanceMeters, 10)
+		assert.True(t, res.Restaurants[1].OpenNow)
+	}
+
+	// Open for an hour three days from now only.
+	day := time.Now().AddDate(0, 0, 3)
+	_, err = repo.SetOpeningHours(ctx, &pb.SetOpeningHoursRequest{
+		RestaurantId: ids[0],
+		Hours:        []*pb.OpeningHours{{DayOfWeek: int32(day.Weekday()), OpenTime: "10:00", CloseTime: "11:00"}},
+	})
+	assert.NoError(t, err)
+
+	res, err = repo.ListNearbyRestaurants(ctx, &pb.ListNearbyRestaurantsRequest{
+		Latitude:  center.Latitude,
+		Longitude: center.Longitude,
+		OpenNow:   true,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Restaurants, 1) {
+		assert.Equal(t, ids[1], res.Restaurants[0].Restaurant.Id)
+	}
+
+	res, err = repo.ListNearbyRestaurants(ctx, &pb.ListNearbyRestaurantsRequest{
+		Latitude:  center.Latitude,
+		Longitude: center.Longitude,
+		OpenAt:    day.Format(time.DateOnly) + " 10:30:00",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, res.Restaurants, 2)
+
+	// open_now reads the restaurant's own clock.
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	local := time.Now().In(tokyo)
+	_, err = repo.UpdateRestaurant(ctx, &pb.UpdateRestaurantRequest{Id: ids[0], Name: "Nearby test far", Address: "Pacific Ocean", Timezone: "Asia/Tokyo"})
+	assert.NoError(t, err)
+	_, err = repo.SetOpeningHours(ctx, &pb.SetOpeningHoursRequest{
+		RestaurantId: ids[0],
+		Hours: []*pb.OpeningHours{{
+			DayOfWeek: -1,
+			OpenTime:  fmt.Sprintf("%02d:00", local.Hour()),
+			CloseTime: fmt.Sprintf("%02d:00", (local.Hour()+1)%24),
+		}},
+	})
+	assert.NoError(t, err)
+	res, err = repo.ListNearbyRestaurants(ctx, &pb.ListNearbyRestaurantsRequest{
+		Latitude:  center.Latitude,
+		Longitude: center.Longitude,
+		OpenNow:   true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, res.Restaurants, 2)
+	_, err = repo.UpdateRestaurant(ctx, &pb.UpdateRestaurantRequest{Id: ids[0], Name: "Nearby test far", Address: "Pacific Ocean", Timezone: "Mars/Olympus"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	got, err := repo.GetRestaurant(ctx, &pb.GetRestaurantRequest{Id: ids[0]})
+	assert.NoError(t, err)
+	assert.Len(t, got.Restaurant.OpeningHours, 1)
+	assert.Equal(t, -48.88, got.Restaurant.Location.Latitude)
+	assert.Equal(t, "Asia/Tokyo", got.Restaurant.Timezone)
+
+	// A new address without a location drops the old one.
+	moved, err := repo.UpdateRestaurant(ctx, &pb.UpdateRestaurantRequest{Id: ids[2], Name: "Nearby test away", Address: "Somewhere else"})
+	assert.NoError(t, err)
+	assert.Nil(t, moved.Restaurant.Location)
+
+	_, err = repo.ListNearbyRestaurants(ctx, &pb.ListNearbyRestaurantsRequest{Latitude: 91})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{Name: "Nowhere", Location: &pb.GeoPoint{Longitude: 200}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// No-show policy actions.
+const (
+	NoShowActionNone    = "none"
+	NoShowActionDeposit = "deposit"
+	NoShowActionBlock   = "block"
+)
+
+// checkNoShowPolicy applies the restaurant's no-show policy to a new booking.
+// It refuses blocked guests and reports whether a deposit is required.
+func checkNoShowPolicy(ctx context.Context, tx *sql.Tx, restaurantId, userId string) (bool, error) {
+	var (
+		threshold, count int32
+		action           string
+	)
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			p.threshold,
+			p.action,
+			COALESCE(g.no_show_count, 0)
+		FROM
+			NoShowPolicies p
+		LEFT JOIN
+			GuestNoShows g ON g.user_id = $2
+		WHERE
+			p.restaurant_id = $1
+	`, restaurantId, userId).Scan(&threshold, &action, &count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check no-show policy: %v", err)
+	}
+
+	if threshold == 0 || count < threshold {
+		return false, nil
+	}
+	switch action {
+	case NoShowActionBlock:
+		return false, status.Errorf(codes.FailedPrecondition,
+			"guest has %d no-shows and cannot book at this restaurant", count)
+	case NoShowActionDeposit:
+		return true, nil
+	}
+	return false, nil
+}
+
+// NoShow is a reservation marked as a no-show.
+type NoShow struct {
+	ReservationId   string
+	UserId          string
+	RestaurantId    string
+	ReservationTime string
+}
+
+// MarkNoShows marks confirmed reservations whose time passed more than grace
+// ago without the guest being seated, and adds them to the guests' no-show
+// counts.
+func (r *ReservationRepo) MarkNoShows(ctx context.Context, grace time.Duration) (_ []NoShow, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MarkNoShows", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark no-shows: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH marked AS (
+			UPDATE
+				reservations
+			SET
+				status = 'NoShow',
+				updated_at = CURRENT_TIMESTAMP
+			WHERE
+				deleted_at = 0
+				AND status = 'Confirmed'
+				AND reservation_time < CURRENT_TIMESTAMP - $1 * INTERVAL '1 millisecond'
+			RETURNING
+				id,
+				user_id,
+				restaurant_id,
+				reservation_time
+		), counted AS (
+			INSERT INTO GuestNoShows (
+				user_id,
+				no_show_count,
+				last_no_show_at
+			)
+			SELECT
+				user_id,
+				COUNT(*),
+				MAX(reservation_time)
+			FROM
+				marked
+			GROUP BY
+				user_id
+			ON CONFLICT (user_id) DO UPDATE SET
+				no_show_count = GuestNoShows.no_show_count + EXCLUDED.no_show_count,
+				last_no_show_at = GREATEST(GuestNoShows.last_no_show_at, EXCLUDED.last_no_show_at),
+				updated_at = CURRENT_TIMESTAMP
+		)
+		SELECT
+			id,
+			user_id,
+			restaurant_id,
+			reservation_time
+		FROM
+			marked
+	`, grace.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark no-shows: %v", err)
+	}
+
+	var noShows []NoShow
+	for rows.Next() {
+		var n NoShow
+		if err := rows.Scan(&n.ReservationId, &n.UserId, &n.RestaurantId, &n.ReservationTime); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan no-show: %v", err)
+		}
+		noShows = append(noShows, n)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to mark no-shows: %v", err)
+	}
+
+	for _, n := range noShows {
+		if err = releaseMenuStock(ctx, tx, n.ReservationId); err != nil {
+			return nil, err
+		}
+		err = addOutboxEvent(ctx, tx, "reservation", n.ReservationId, EventReservationNoShow, ReservationNoShowEvent(n))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to mark no-shows: %v", err)
+	}
+	return noShows, nil
+}
+
+// SeatReservation records that the guest arrived, which keeps the reservation
+// from being marked as a no-show. Seating a guest already marked as a no-show
+// takes it back off their count.
+func (r *ReservationRepo) SeatReservation(ctx context.Context, req *pb.SeatReservationRequest) (_ *pb.SeatReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SeatReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		WITH previous AS (
+			SELECT status FROM reservations WHERE id = $1 AND deleted_at = 0 FOR UPDATE
+		), seated AS (
+			UPDATE
+				reservations
+			SET
+				status = 'Seated',
+				seated_at = CURRENT_TIMESTAMP,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE
+				id = $1 AND deleted_at = 0 AND status IN ('Pending', 'Confirmed', 'NoShow')
+			RETURNING
+				*
+		), corrected AS (
+			UPDATE
+				GuestNoShows
+			SET
+				no_show_count = GREATEST(no_show_count - 1, 0),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE
+				user_id = (SELECT user_id FROM seated)
+				AND (SELECT status FROM previous) = 'NoShow'
+		)
+		SELECT
+			` + reservationColumns + `
+		FROM
+			seated;
+	`
+	reservation, err := scanReservation(r.DB.QueryRowContext(ctx, query, req.Id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found or cannot be seated")
+		}
+		return nil, fmt.Errorf("failed to seat reservation: %v", err)
+	}
+	return &pb.SeatReservationResponse{Reservation: reservation}, nil
+}
+
+// GetNoShowPolicy returns the restaurant's policy. Restaurants without one
+// get a disabled policy.
+func (r *ReservationRepo) GetNoShowPolicy(ctx context.Context, req *pb.GetNoShowPolicyRequest) (_ *pb.GetNoShowPolicyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetNoShowPolicy", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	policy := &pb.NoShowPolicy{RestaurantId: req.RestaurantId, Action: NoShowActionNone}
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			threshold,
+			action
+		FROM
+			NoShowPolicies
+		WHERE
+			restaurant_id = $1
+	`, req.RestaurantId).Scan(&policy.Threshold, &policy.Action)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get no-show policy: %v", err)
+	}
+	return &pb.GetNoShowPolicyResponse{Policy: policy}, nil
+}
+
+func (r *ReservationRepo) SetNoShowPolicy(ctx context.Context, req *pb.SetNoShowPolicyRequest) (_ *pb.SetNoShowPolicyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetNoShowPolicy", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetPolicy()
+	if in.GetThreshold() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "threshold must not be negative")
+	}
+	switch in.GetAction() {
+	case NoShowActionNone, NoShowActionDeposit, NoShowActionBlock:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "action must be one of none, deposit, block")
+	}
+
+	// A deposit asked of no-show guests is priced by the deposit rules.
+	if in.Action == NoShowActionDeposit {
+		var hasRules bool
+		err = r.DB.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM DepositRules WHERE restaurant_id = $1)
+		`, in.RestaurantId).Scan(&hasRules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set no-show policy: %v", err)
+		}
+		if !hasRules {
+			return nil, status.Error(codes.FailedPrecondition, "set deposit rules before asking no-show guests for a deposit")
+		}
+	}
+
+	policy := &pb.NoShowPolicy{}
+	err = r.DB.QueryRowContext(ctx, `
+		INSERT INTO NoShowPolicies (
+			restaurant_id,
+			threshold,
+			action
+		)
+		SELECT
+			id,
+			$2,
+			$3
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		ON CONFLICT (restaurant_id) DO UPDATE SET
+			threshold = EXCLUDED.threshold,
+			action = EXCLUDED.action,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING
+			restaurant_id,
+			threshold,
+			action
+	`, in.RestaurantId, in.Threshold, in.Action).Scan(&policy.RestaurantId, &policy.Threshold, &policy.Action)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		return nil, fmt.Errorf("failed to set no-show policy: %v", err)
+	}
+	return &pb.SetNoShowPolicyResponse{Policy: policy}, nil
+}
+
+func (r *ReservationRepo) GetGuestNoShows(ctx context.Context, req *pb.GetGuestNoShowsRequest) (_ *pb.GetGuestNoShowsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetGuestNoShows", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	resp := &pb.GetGuestNoShowsResponse{UserId: req.UserId}
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			no_show_count,
+			COALESCE(last_no_show_at::text, '')
+		FROM
+			GuestNoShows
+		WHERE
+			user_id = $1
+	`, req.UserId).Scan(&resp.NoShowCount, &resp.LastNoShowAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get guest no-shows: %v", err)
+	}
+	return resp, nil
+}
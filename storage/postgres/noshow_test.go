@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMarkNoShows(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	userId := "9b2f7c4e-1d3a-4e6b-8f5c-0a7d2e9b4c61"
+
+	resp, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          userId,
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: time.Now().Add(-2 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	// Other overdue reservations may be marked too; only this one is checked.
+	noShows, err := repo.MarkNoShows(context.Background(), 30*time.Minute)
+	assert.NoError(t, err)
+	var marked []string
+	for _, n := range noShows {
+		marked = append(marked, n.ReservationId)
+	}
+	assert.Contains(t, marked, resp.Reservation.Id)
+
+	guest, err := repo.GetGuestNoShows(context.Background(), &pb.GetGuestNoShowsRequest{UserId: userId})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, guest.NoShowCount, int32(1))
+
+	seated, err := repo.SeatReservation(context.Background(), &pb.SeatReservationRequest{Id: resp.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, "Seated", seated.Reservation.Status)
+
+	after, err := repo.GetGuestNoShows(context.Background(), &pb.GetGuestNoShowsRequest{UserId: userId})
+	assert.NoError(t, err)
+	assert.Equal(t, guest.NoShowCount-1, after.NoShowCount)
+}
+
+func TestNoShowPolicy(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	userId := "5e8a1f3c-7b2d-4c9e-a6f0-3d1b8e5c7a92"
+	restaurant, err := repo.CreateRestaurant(context.Background(), &pb.CreateRestaurantRequest{Name: "No-show policy", Address: "Yunusobod"})
+	assert.NoError(t, err)
+	restaurantId := restaurant.Restaurant.Id
+
+	_, err = db.Exec(`
+		INSERT INTO GuestNoShows (user_id, no_show_count) VALUES ($1, 3)
+		ON CONFLICT (user_id) DO UPDATE SET no_show_count = 3
+	`, userId)
+	assert.NoError(t, err)
+	defer repo.SetNoShowPolicy(context.Background(), &pb.SetNoShowPolicyRequest{
+		Policy: &pb.NoShowPolicy{RestaurantId: restaurantId, Threshold: 0, Action: NoShowActionNone},
+	})
+
+	req := &pb.CreateReservationRequest{
+		UserId:          userId,
+		RestaurantId:    restaurantId,
+		ReservationTime: time.Now().Add(24 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	}
+
+	// A deposit needs rules to price it.
+	deposit := &pb.SetNoShowPolicyRequest{
+		Policy: &pb.NoShowPolicy{RestaurantId: restaurantId, Threshold: 3, Action: NoShowActionDeposit},
+	}
+	_, err = repo.SetNoShowPolicy(context.Background(), deposit)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = repo.SetDepositRules(context.Background(), &pb.SetDepositRulesRequest{
+		RestaurantId: restaurantId,
+		Rules:        []*pb.DepositRule{{AmountPerPerson: &pb.Money{MinorUnits: 1000}, DayOfWeek: -1, MinPartySize: 8}},
+	})
+	assert.NoError(t, err)
+	_, err = repo.SetNoShowPolicy(context.Background(), deposit)
+	assert.NoError(t, err)
+	_, err = repo.SetDepositRules(context.Background(), &pb.SetDepositRulesRequest{RestaurantId: restaurantId})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	resp, err := repo.CreateReservation(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pending", resp.Reservation.Status)
+	assert.True(t, resp.Reservation.DepositRequired)
+	assert.Equal(t, int64(1000), resp.Reservation.DepositAmount.MinorUnits)
+	_, err = repo.UpdateReservation(context.Background(), &pb.UpdateReservationRequest{
+		Id:              resp.Reservation.Id,
+		UserId:          userId,
+		RestaurantId:    restaurantId,
+		ReservationTime: req.ReservationTime,
+		Status:          "Confirmed",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = repo.SetNoShowPolicy(context.Background(), &pb.SetNoShowPolicyRequest{
+		Policy: &pb.NoShowPolicy{RestaurantId: restaurantId, Threshold: 3, Action: NoShowActionBlock},
+	})
+	assert.NoError(t, err)
+	_, err = repo.CreateReservation(context.Background(), req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Moving a booking from elsewhere is refused as well.
+	other, err := repo.CreateRestaurant(context.Background(), &pb.CreateRestaurantRequest{Name: "Elsewhere", Address: "Sergeli"})
+	assert.NoError(t, err)
+	req.RestaurantId = other.Restaurant.Id
+	elsewhere, err := repo.CreateReservation(context.Background(), req)
+	assert.NoError(t, err)
+	_, err = repo.UpdateReservation(context.Background(), &pb.UpdateReservationRequest{
+		Id:              elsewhere.Reservation.Id,
+		UserId:          userId,
+		RestaurantId:    restaurantId,
+		ReservationTime: req.ReservationTime,
+		Status:          "Confirmed",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,341 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Notification kinds.
+const (
+	NotificationConfirmation = "confirmation"
+	NotificationChange       = "change"
+	NotificationCancellation = "cancellation"
+	NotificationReminder24h  = "reminder_24h"
+	NotificationReminder2h   = "reminder_2h"
+)
+
+// Notification statuses.
+const (
+	NotificationPending = "Pending"
+	NotificationSent    = "Sent"
+	NotificationSkipped = "Skipped"
+	NotificationFailed  = "Failed"
+)
+
+// Notification is a claimed notification together with the reservation,
+// restaurant and guest details needed to deliver it. Orphaned is set when the
+// reservation's restaurant no longer exists, and SentChannels lists the
+// channels the notification already went out on.
+type Notification struct {
+	Id                string
+	Kind              string
+	Attempts          int
+	ReservationId     string
+	UserId            string
+	ReservationStatus string
+	// ReservationTime is the time the notification was queued for and
+	// CurrentTime the reservation's time now; they differ if it was moved.
+	ReservationTime   time.Time
+	CurrentTime       time.Time
+	Deleted           bool
+	Orphaned          bool
+	RestaurantName    string
+	RestaurantAddress string
+	Email             string
+	PhoneNumber       string
+	EmailEnabled      bool
+	SMSEnabled        bool
+	SentChannels      []string
+}
+
+// Notification channels.
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+// queueNotification schedules an immediate notification for a reservation in
+// the same transaction as the change it announces.
+func queueNotification(ctx context.Context, tx *sql.Tx, reservationId, kind string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO Notifications (
+			reservation_id,
+			kind,
+			reservation_time
+		)
+		SELECT
+			id,
+			$2,
+			reservation_time
+		FROM
+			reservations
+		WHERE
+			id = $1
+	`, reservationId, kind)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s notification: %v", kind, err)
+	}
+	return nil
+}
+
+// EnqueueReminders queues the 24h and 2h reminders of reservations that have
+// entered the reminder window. A reminder is skipped if the reservation was
+// booked inside its window, and is queued again if the reservation is moved.
+func (r *ReservationRepo) EnqueueReminders(ctx context.Context) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.EnqueueReminders", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	res, err := r.DB.ExecContext(ctx, `
+		INSERT INTO Notifications (
+			reservation_id,
+			kind,
+			reservation_time
+		)
+		SELECT
+			r.id,
+			w.kind,
+			r.reservation_time
+		FROM
+			reservations r
+		CROSS JOIN (
+			VALUES
+				('reminder_24h', INTERVAL '24 hours', INTERVAL '2 hours'),
+				('reminder_2h', INTERVAL '2 hours', INTERVAL '0')
+		) AS w(kind, opens, closes)
+		WHERE
+			r.deleted_at = 0
+			AND r.status IN ('Pending', 'Confirmed')
+			AND r.reservation_time - w.opens <= CURRENT_TIMESTAMP
+			AND r.reservation_time - w.closes > CURRENT_TIMESTAMP
+			AND r.created_at < r.reservation_time - w.opens
+		ON CONFLICT (reservation_id, kind, reservation_time)
+			WHERE kind IN ('reminder_24h', 'reminder_2h')
+			DO NOTHING
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue reminders: %v", err)
+	}
+	return res.RowsAffected()
+}
+
+// ClaimNotifications returns up to limit due notifications and hides them
+// from other dispatchers for the lease duration.
+func (r *ReservationRepo) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) (_ []Notification, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ClaimNotifications", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH claimed AS (
+			SELECT
+				id
+			FROM
+				Notifications
+			WHERE
+				status = 'Pending' AND send_at <= CURRENT_TIMESTAMP
+			ORDER BY
+				send_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE
+			Notifications n
+		SET
+			send_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		FROM
+			claimed c,
+			reservations r
+			LEFT JOIN restaurants rs ON rs.id = r.restaurant_id
+			LEFT JOIN NotificationPreferences p ON p.user_id = r.user_id
+		WHERE
+			n.id = c.id AND r.id = n.reservation_id
+		RETURNING
+			n.id,
+			n.kind,
+			n.attempts,
+			r.id,
+			r.user_id,
+			r.status,
+			n.reservation_time,
+			r.reservation_time,
+			r.deleted_at <> 0,
+			rs.id IS NULL,
+			COALESCE(rs.name, ''),
+			COALESCE(rs.address, ''),
+			COALESCE(p.email, ''),
+			COALESCE(p.phone_number, ''),
+			COALESCE(p.email_enabled, true),
+			COALESCE(p.sms_enabled, true),
+			n.sent_channels
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %v", err)
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		var n Notification
+		err = rows.Scan(&n.Id, &n.Kind, &n.Attempts, &n.ReservationId, &n.UserId, &n.ReservationStatus,
+			&n.ReservationTime, &n.CurrentTime, &n.Deleted, &n.Orphaned, &n.RestaurantName, &n.RestaurantAddress,
+			&n.Email, &n.PhoneNumber, &n.EmailEnabled, &n.SMSEnabled, pq.Array(&n.SentChannels))
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %v", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %v", err)
+	}
+	return notifications, nil
+}
+
+// MarkNotification records the final outcome of a notification: Sent,
+// Skipped or Failed.
+func (r *ReservationRepo) MarkNotification(ctx context.Context, id, outcome, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MarkNotification", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.DB.ExecContext(ctx, `
+		UPDATE
+			Notifications
+		SET
+			status = $2,
+			attempts = attempts + 1,
+			last_error = NULLIF($3, ''),
+			sent_at = CASE WHEN $2 = 'Sent' THEN CURRENT_TIMESTAMP END
+		WHERE
+			id = $1
+	`, id, outcome, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification: %v", err)
+	}
+	return nil
+}
+
+// MarkChannelSent records that a notification went out on channel, so that
+// a retry does not send it there again.
+func (r *ReservationRepo) MarkChannelSent(ctx context.Context, id, channel string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MarkChannelSent", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.DB.ExecContext(ctx, `
+		UPDATE
+			Notifications
+		SET
+			sent_channels = array_append(sent_channels, $2)
+		WHERE
+			id = $1 AND NOT $2 = ANY(sent_channels)
+	`, id, channel)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification channel: %v", err)
+	}
+	return nil
+}
+
+// RetryNotification records a failed delivery and schedules the next attempt
+// after retryIn.
+func (r *ReservationRepo) RetryNotification(ctx context.Context, id string, retryIn time.Duration, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.RetryNotification", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.DB.ExecContext(ctx, `
+		UPDATE
+			Notifications
+		SET
+			attempts = attempts + 1,
+			send_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond',
+			last_error = $3
+		WHERE
+			id = $1
+	`, id, retryIn.Milliseconds(), reason)
+	if err != nil {
+		return fmt.Errorf("failed to retry notification: %v", err)
+	}
+	return nil
+}
+
+// GetNotificationPreferences returns the guest's preferences. Guests who never
+// set them get every channel enabled and no contact details.
+func (r *ReservationRepo) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest) (_ *pb.GetNotificationPreferencesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetNotificationPreferences", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	prefs := &pb.NotificationPreferences{UserId: req.UserId, EmailEnabled: true, SmsEnabled: true}
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(email, ''),
+			COALESCE(phone_number, ''),
+			email_enabled,
+			sms_enabled
+		FROM
+			NotificationPreferences
+		WHERE
+			user_id = $1
+	`, req.UserId).Scan(&prefs.Email, &prefs.PhoneNumber, &prefs.EmailEnabled, &prefs.SmsEnabled)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get notification preferences: %v", err)
+	}
+	return &pb.GetNotificationPreferencesResponse{Preferences: prefs}, nil
+}
+
+// UpdateNotificationPreferences replaces the guest's contact details and
+// opt-outs.
+func (r *ReservationRepo) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest) (_ *pb.UpdateNotificationPreferencesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpdateNotificationPreferences", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetPreferences()
+	if in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "preferences.user_id is required")
+	}
+
+	prefs := &pb.NotificationPreferences{}
+	err = r.DB.QueryRowContext(ctx, `
+		INSERT INTO NotificationPreferences (
+			user_id,
+			email,
+			phone_number,
+			email_enabled,
+			sms_enabled
+		)
+		VALUES (
+			$1,
+			NULLIF($2, ''),
+			NULLIF($3, ''),
+			$4,
+			$5
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			phone_number = EXCLUDED.phone_number,
+			email_enabled = EXCLUDED.email_enabled,
+			sms_enabled = EXCLUDED.sms_enabled,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING
+			user_id,
+			COALESCE(email, ''),
+			COALESCE(phone_number, ''),
+			email_enabled,
+			sms_enabled
+	`, in.UserId, in.Email, in.PhoneNumber, in.EmailEnabled, in.SmsEnabled).Scan(
+		&prefs.UserId, &prefs.Email, &prefs.PhoneNumber, &prefs.EmailEnabled, &prefs.SmsEnabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update notification preferences: %v", err)
+	}
+	return &pb.UpdateNotificationPreferencesResponse{Preferences: prefs}, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	userId := "3c1d9b7e-5f0a-4b8e-9a41-2f6d8c0e7b15"
+
+	resp, err := repo.UpdateNotificationPreferences(context.Background(), &pb.UpdateNotificationPreferencesRequest{
+		Preferences: &pb.NotificationPreferences{
+			UserId:       userId,
+			Email:        "guest@example.com",
+			EmailEnabled: true,
+			SmsEnabled:   false,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "guest@example.com", resp.Preferences.Email)
+	assert.False(t, resp.Preferences.SmsEnabled)
+
+	got, err := repo.GetNotificationPreferences(context.Background(), &pb.GetNotificationPreferencesRequest{UserId: userId})
+	assert.NoError(t, err)
+	assert.Equal(t, resp.Preferences.Email, got.Preferences.Email)
+	assert.True(t, got.Preferences.EmailEnabled)
+	assert.False(t, got.Preferences.SmsEnabled)
+}
+
+func TestCreateReservationQueuesConfirmation(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	resp, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: time.Now().Add(48 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	var kind, state string
+	err = db.QueryRow(`
+		SELECT kind, status FROM Notifications WHERE reservation_id = $1
+	`, resp.Reservation.Id).Scan(&kind, &state)
+	assert.NoError(t, err)
+	assert.Equal(t, NotificationConfirmation, kind)
+	assert.Equal(t, NotificationPending, state)
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reservation-service/tracing"
+	"sort"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Outbox event types.
+const (
+	EventReservationCreated   = "ReservationCreated"
+	EventReservationCancelled = "ReservationCancelled"
+	EventReservationNoShow    = "ReservationNoShow"
+	EventMealsOrdered         = "MealsOrdered"
+	EventPaymentCompleted     = "PaymentCompleted"
+	EventRefundCompleted      = "RefundCompleted"
+)
+
+// ReservationCreatedEvent is the payload of EventReservationCreated.
+type ReservationCreatedEvent struct {
+	ReservationId   string `json:"reservation_id"`
+	UserId          string `json:"user_id"`
+	RestaurantId    string `json:"restaurant_id"`
+	ReservationTime string `json:"reservation_time"`
+	Status          string `json:"status"`
+}
+
+// ReservationCancelledEvent is the payload of EventReservationCancelled.
+type ReservationCancelledEvent struct {
+	ReservationId   string `json:"reservation_id"`
+	UserId          string `json:"user_id"`
+	RestaurantId    string `json:"restaurant_id"`
+	ReservationTime string `json:"reservation_time"`
+	Reason          string `json:"reason"`
+}
+
+// ReservationNoShowEvent is the payload of EventReservationNoShow.
+type ReservationNoShowEvent struct {
+	ReservationId   string `json:"reservation_id"`
+	UserId          string `json:"user_id"`
+	RestaurantId    string `json:"restaurant_id"`
+	ReservationTime string `json:"reservation_time"`
+}
+
+// MealOrderLine is a single ordered menu item.
+type MealOrderLine struct {
+	MenuItemId string   `json:"menu_item_id"`
+	Quantity   int32    `json:"quantity"`
+	OptionIds  []string `json:"option_ids,omitempty"`
+}
+
+// MealsOrderedEvent is the payload of EventMealsOrdered.
+type MealsOrderedEvent struct {
+	ReservationId string          `json:"reservation_id"`
+	UserId        string          `json:"user_id"`
+	RestaurantId  string          `json:"restaurant_id"`
+	Meals         []MealOrderLine `json:"meals"`
+}
+
+// PaymentCompletedEvent is the payload of EventPaymentCompleted.
+type PaymentCompletedEvent struct {
+	ReservationId string `json:"reservation_id"`
+	PaymentId     string `json:"payment_id"`
+	AmountMinor   int64  `json:"amount_minor"`
+	Currency      string `json:"currency"`
+	PaymentMethod string `json:"payment_method"`
+	Deposit       bool   `json:"deposit,omitempty"`
+}
+
+// RefundCompletedEvent is the payload of EventRefundCompleted.
+type RefundCompletedEvent struct {
+	RefundId      string `json:"refund_id"`
+	ReservationId string `json:"reservation_id"`
+	PaymentId     string `json:"payment_id"`
+	AmountMinor   int64  `json:"amount_minor"`
+	Currency      string `json:"currency"`
+	Reason        string `json:"reason"`
+}
+
+// OutboxEvent is a stored event waiting to be published.
+type OutboxEvent struct {
+	Id            string
+	AggregateType string
+	AggregateId   string
+	EventType     string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+}
+
+// addOutboxEvent stores an event in the same transaction as the change it
+// describes, so it is published only if that change is committed.
+func addOutboxEvent(ctx context.Context, tx *sql.Tx, aggregateType, aggregateId, eventType string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %v", eventType, err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO Outbox (
+			aggregate_type,
+			aggregate_id,
+			event_type,
+			payload
+		)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4
+		)`, aggregateType, aggregateId, eventType, body)
+	if err != nil {
+		return fmt.Errorf("failed to store %s event: %v", eventType, err)
+	}
+	return nil
+}
+
+// ClaimOutboxEvents returns up to limit unpublished events that are due and
+// hides them from other relays for the lease duration. Events are returned
+// oldest first.
+func (r *ReservationRepo) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) (_ []OutboxEvent, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ClaimOutboxEvents", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		UPDATE 
+			Outbox 
+		SET 
+			next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT 
+				id 
+			FROM 
+				Outbox 
+			WHERE 
+				published_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY 
+				created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING 
+			id, 
+			aggregate_type, 
+			aggregate_id, 
+			event_type, 
+			payload, 
+			created_at, 
+			attempts
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []OutboxEvent
+	for rows.Next() {
+		var e OutboxEvent
+		err = rows.Scan(&e.Id, &e.AggregateType, &e.AggregateId, &e.EventType, &e.Payload, &e.CreatedAt, &e.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %v", err)
+		}
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %v", err)
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery order.
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// MarkOutboxPublished records that an event was delivered.
+func (r *ReservationRepo) MarkOutboxPublished(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MarkOutboxPublished", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.DB.ExecContext(ctx, `
+		UPDATE 
+			Outbox 
+		SET 
+			published_at = CURRENT_TIMESTAMP, 
+			last_error = NULL
+		WHERE 
+			id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %v", err)
+	}
+	return nil
+}
+
+// MarkOutboxFailed records a failed delivery and schedules the next attempt
+// after retryIn.
+func (r *ReservationRepo) MarkOutboxFailed(ctx context.Context, id string, retryIn time.Duration, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.MarkOutboxFailed", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.DB.ExecContext(ctx, `
+		UPDATE 
+			Outbox 
+		SET 
+			attempts = attempts + 1, 
+			next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond', 
+			last_error = $3
+		WHERE 
+			id = $1
+	`, id, retryIn.Milliseconds(), reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %v", err)
+	}
+	return nil
+}
+
+func sortOutboxEvents(events []OutboxEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReservationWritesOutboxEvent(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	resp, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: time.Now().Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	var payload []byte
+	err = db.QueryRow(`
+		SELECT payload FROM Outbox 
+		WHERE aggregate_id = $1 AND event_type = $2 AND published_at IS NULL
+	`, resp.Reservation.Id, EventReservationCreated).Scan(&payload)
+	assert.NoError(t, err)
+
+	var event ReservationCreatedEvent
+	assert.NoError(t, json.Unmarshal(payload, &event))
+	assert.Equal(t, resp.Reservation.Id, event.ReservationId)
+	assert.Equal(t, "Confirmed", event.Status)
+}
+
+func TestClaimAndMarkOutboxEvents(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	ctx := context.Background()
+
+	events, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	if len(events) == 0 {
+		return
+	}
+
+	// Claimed events are leased and not handed out again.
+	again, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	for _, e := range again {
+		assert.NotEqual(t, events[0].Id, e.Id)
+	}
+
+	assert.NoError(t, repo.MarkOutboxFailed(ctx, events[0].Id, 0, "broker unavailable"))
+	assert.NoError(t, repo.MarkOutboxPublished(ctx, events[0].Id))
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetReservationPayment links a completed payment to its reservation, redeems
+// its promo code and records a PaymentCompleted event. A reservation held for
+// a deposit is confirmed by the payment. It fails with FailedPrecondition if
+// the reservation was already paid, is no longer pending or confirmed, its
+// bill no longer comes to amount, or its promo code ran out of redemptions
+// meanwhile.
+func (r *ReservationRepo) SetReservationPayment(ctx context.Context, reservationId, paymentId string, amount *pb.Money, paymentMethod string) (err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetReservationPayment", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set reservation payment: %v", err)
+	}
+	defer tx.Rollback()
+
+	// The row lock keeps OrderMeals and ApplyPromoCode from changing the bill
+	// until the payment is recorded.
+	var (
+		paid  bool
+		state string
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			payment_id IS NOT NULL,
+			status
+		FROM
+			reservations
+		WHERE
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, reservationId).Scan(&paid, &state)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "reservation not found")
+	case err != nil:
+		return fmt.Errorf("failed to set reservation payment: %v", err)
+	case paid:
+		return status.Error(codes.FailedPrecondition, "reservation is already paid")
+	case state != "Pending" && state != "Confirmed":
+		return status.Errorf(codes.FailedPrecondition, "reservation is %s and cannot be paid", state)
+	}
+	bill, err := loadBill(ctx, tx, reservationId)
+	if err != nil {
+		return err
+	}
+	if due := bill.AmountDue; due.MinorUnits != amount.GetMinorUnits() {
+		return status.Errorf(codes.FailedPrecondition, "the bill changed to %s while it was being paid",
+			money.Format(due.MinorUnits, due.Currency))
+	}
+
+	var promoId sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		UPDATE 
+			reservations 
+		SET 
+			payment_id = $2, 
+			status = CASE WHEN deposit_required AND status = 'Pending' THEN 'Confirmed' ELSE status END, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			id = $1
+		RETURNING 
+			promo_code_id
+	`, reservationId, paymentId).Scan(&promoId)
+	if err != nil {
+		return fmt.Errorf("failed to set reservation payment: %v", err)
+	}
+
+	if promoId.Valid {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE
+				PromoCodes
+			SET
+				redemptions = redemptions + 1
+			WHERE
+				id = $1 AND (max_redemptions IS NULL OR redemptions < max_redemptions)
+		`, promoId.String)
+		if err != nil {
+			return fmt.Errorf("failed to redeem promo code: %v", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return status.Error(codes.FailedPrecondition, "promo code has no redemptions left")
+		}
+	}
+
+	err = addOutboxEvent(ctx, tx, "reservation", reservationId, EventPaymentCompleted, PaymentCompletedEvent{
+		ReservationId: reservationId,
+		PaymentId:     paymentId,
+		AmountMinor:   amount.GetMinorUnits(),
+		Currency:      amount.GetCurrency(),
+		PaymentMethod: paymentMethod,
+	})
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to set reservation payment: %v", err)
+	}
+	return nil
+}
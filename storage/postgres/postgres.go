@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reservation-service/config"
@@ -8,17 +9,22 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func ConnectDB() (*sql.DB, error) {
-	cfg := config.Load()
-	conn := fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=disable",
-		cfg.DB_HOST, cfg.DB_PORT, cfg.DB_USERNAME, cfg.DB_DATABASE, cfg.DB_PASSWORD, )
+func ConnectDB(cfg config.PostgresConfig) (*sql.DB, error) {
+	conn := fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=%s connect_timeout=%d",
+		cfg.Host, cfg.Port, cfg.User, cfg.Database, cfg.Password, cfg.SSLMode, int(cfg.ConnectTimeout.Seconds()))
 
 	db, err := sql.Open("postgres", conn)
 	if err != nil {
 		return nil, err
 	}
-	
-	if err = db.Ping(); err != nil {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 	
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"database/sql"
+	"reservation-service/config"
+	"reservation-service/storage/redis"
+
+	r "github.com/redis/go-redis/v9"
+)
+
+func connectTestDB() (*sql.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return ConnectDB(cfg.Postgres)
+}
+
+func connectTestRedis() *r.Client {
+	cfg, _ := config.Load()
+	return redis.ConnectR(cfg.Redis)
+}
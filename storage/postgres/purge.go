@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PurgeDeleted permanently removes rows that were soft-deleted more than
+// RetentionDays ago. Children are purged before their parents, and a parent
+// that still has children afterwards is kept, so deleting it never cascades
+// to live rows and the counts are everything that was removed. Reservations
+// stay while they have orders, menu items while orders refer to them, menu
+// categories while live items are in them, and restaurants while they have
+// reservations, menu items or menu categories. Reservations with refunds are kept, since the
+// refunds ledger must outlive them.
+func (r *ReservationRepo) PurgeDeleted(ctx context.Context, req *pb.PurgeDeletedRequest) (_ *pb.PurgeDeletedResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.PurgeDeleted", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	if req.RetentionDays < 0 {
+		return nil, status.Error(codes.InvalidArgument, "retention_days must not be negative")
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin purge: %v", err)
+	}
+	defer tx.Rollback()
+
+	cutoff := `deleted_at <> 0 AND deleted_at < EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - $1 * 86400`
+	purge := func(table, keep string) (int64, error) {
+		res, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE `+cutoff+keep, req.RetentionDays)
+		if err != nil {
+			return 0, fmt.Errorf("failed to purge %s: %v", table, err)
+		}
+		return res.RowsAffected()
+	}
+
+	resp := &pb.PurgeDeletedResponse{}
+	if resp.ReservationOrders, err = purge("ReservationOrders", ""); err != nil {
+		return nil, err
+	}
+	if resp.Reservations, err = purge("Reservations", `
+		AND NOT EXISTS (SELECT 1 FROM ReservationOrders o WHERE o.reservation_id = Reservations.id)
+		AND NOT EXISTS (SELECT 1 FROM Refunds f WHERE f.reservation_id = Reservations.id)`); err != nil {
+		return nil, err
+	}
+	if resp.MenuItems, err = purge("Menu", ` AND NOT EXISTS (SELECT 1 FROM ReservationOrders o WHERE o.menu_item_id = Menu.id)`); err != nil {
+		return nil, err
+	}
+	if resp.MenuCategories, err = purge("MenuCategories", ` AND NOT EXISTS (SELECT 1 FROM Menu m WHERE m.category_id = MenuCategories.id AND m.deleted_at = 0)`); err != nil {
+		return nil, err
+	}
+	if resp.Restaurants, err = purge("Restaurants", `
+		AND NOT EXISTS (SELECT 1 FROM Reservations c WHERE c.restaurant_id = Restaurants.id)
+		AND NOT EXISTS (SELECT 1 FROM Menu c WHERE c.restaurant_id = Restaurants.id)
+		AND NOT EXISTS (SELECT 1 FROM MenuCategories c WHERE c.restaurant_id = Restaurants.id)`); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %v", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,473 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/money"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Staff roles.
+const (
+	RoleStaff   = "staff"
+	RoleManager = "manager"
+)
+
+// Refund statuses.
+const (
+	RefundPending   = "Pending"
+	RefundCompleted = "Completed"
+)
+
+// refundColumns lists the columns read by scanRefund.
+const refundColumns = `id,
+			reservation_id,
+			payment_id,
+			amount_minor,
+			(SELECT rs.currency FROM reservations r JOIN Restaurants rs ON rs.id = r.restaurant_id WHERE r.id = reservation_id),
+			COALESCE(reason, ''),
+			status,
+			COALESCE(requested_by::text, ''),
+			idempotency_key,
+			created_at::text,
+			balance_before_minor`
+
+// RefundRecord is a stored refund and the payment amount, in minor units, it
+// is taken from.
+type RefundRecord struct {
+	Refund        *pb.Refund
+	BalanceBefore int64
+}
+
+func scanRefund(row rowScanner) (*RefundRecord, error) {
+	refund := &pb.Refund{Amount: &pb.Money{}}
+	rec := &RefundRecord{Refund: refund}
+	err := row.Scan(&refund.Id, &refund.ReservationId, &refund.PaymentId, &refund.Amount.MinorUnits, &refund.Amount.Currency,
+		&refund.Reason, &refund.Status, &refund.RequestedBy, &refund.IdempotencyKey, &refund.CreatedAt, &rec.BalanceBefore)
+	return rec, err
+}
+
+// StaffRole returns the user's role at the restaurant, or "" if they are not
+// on its staff.
+func (r *ReservationRepo) StaffRole(ctx context.Context, restaurantId, userId string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.StaffRole", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	var role string
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			role
+		FROM
+			RestaurantStaff
+		WHERE
+			restaurant_id = $1 AND user_id = $2
+	`, restaurantId, userId).Scan(&role)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get staff role: %v", err)
+	}
+	return role, nil
+}
+
+func (r *ReservationRepo) SetRestaurantStaff(ctx context.Context, req *pb.SetRestaurantStaffRequest) (_ *pb.SetRestaurantStaffResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetRestaurantStaff", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	in := req.GetMember()
+	if in.GetRestaurantId() == "" || in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "member.restaurant_id and member.user_id are required")
+	}
+
+	switch in.Role {
+	case "":
+		_, err = r.DB.ExecContext(ctx, `
+			DELETE FROM RestaurantStaff WHERE restaurant_id = $1 AND user_id = $2
+		`, in.RestaurantId, in.UserId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove staff member: %v", err)
+		}
+		return &pb.SetRestaurantStaffResponse{Member: in}, nil
+	case RoleStaff, RoleManager:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "role must be one of staff, manager")
+	}
+
+	member := &pb.StaffMember{}
+	err = r.DB.QueryRowContext(ctx, `
+		INSERT INTO RestaurantStaff (
+			restaurant_id,
+			user_id,
+			role
+		)
+		SELECT
+			id,
+			$2,
+			$3
+		FROM
+			Restaurants
+		WHERE
+			id = $1 AND deleted_at = 0
+		ON CONFLICT (restaurant_id, user_id) DO UPDATE SET
+			role = EXCLUDED.role
+		RETURNING
+			restaurant_id,
+			user_id,
+			role
+	`, in.RestaurantId, in.UserId, in.Role).Scan(&member.RestaurantId, &member.UserId, &member.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		return nil, fmt.Errorf("failed to set staff member: %v", err)
+	}
+	return &pb.SetRestaurantStaffResponse{Member: member}, nil
+}
+
+func (r *ReservationRepo) ListRestaurantStaff(ctx context.Context, req *pb.ListRestaurantStaffRequest) (_ *pb.ListRestaurantStaffResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListRestaurantStaff", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			restaurant_id,
+			user_id,
+			role
+		FROM
+			RestaurantStaff
+		WHERE
+			restaurant_id = $1
+		ORDER BY
+			created_at
+	`, req.RestaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff: %v", err)
+	}
+	defer rows.Close()
+
+	var staff []*pb.StaffMember
+	for rows.Next() {
+		member := &pb.StaffMember{}
+		if err := rows.Scan(&member.RestaurantId, &member.UserId, &member.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan staff member: %v", err)
+		}
+		staff = append(staff, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list staff: %v", err)
+	}
+	return &pb.ListRestaurantStaffResponse{Staff: staff}, nil
+}
+
+// RefundQuote describes how much of a reservation's payment may be refunded.
+type RefundQuote struct {
+	ReservationId string
+	RestaurantId  string
+	PaymentId     string
+	Currency      string
+	// FeePercent is the share of the payment the cancellation policy keeps:
+	// the applied fee of a cancelled reservation, the current fee of an
+	// upcoming one and everything once the reservation has taken place.
+	FeePercent int32
+	// Refunded is the total of the payment's completed refunds in minor
+	// units.
+	Refunded int64
+}
+
+// Limit returns the most that may still be refunded of a payment with the
+// given remaining balance. Managers may refund the whole balance; other staff
+// only what the cancellation policy gives back.
+func (q *RefundQuote) Limit(balance int64, manager bool) int64 {
+	if manager {
+		return balance
+	}
+	paid := balance + q.Refunded
+	allowed := money.Percent(paid, float64(100-q.FeePercent)) - q.Refunded
+	return max(min(allowed, balance), 0)
+}
+
+// paymentIds lists the distinct payments of a reservation: the bill's, then
+// the deposit's. A bill the deposit covered has the deposit's payment.
+func paymentIds(bill, deposit string) []string {
+	var ids []string
+	if bill != "" {
+		ids = append(ids, bill)
+	}
+	if deposit != "" && deposit != bill {
+		ids = append(ids, deposit)
+	}
+	return ids
+}
+
+// QuoteRefund loads a payment of a reservation and the cancellation policy
+// that applies to refunding it. paymentId defaults to the bill payment, or
+// the deposit's while the bill is unpaid.
+func (r *ReservationRepo) QuoteRefund(ctx context.Context, reservationId, paymentId string) (_ *RefundQuote, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.QuoteRefund", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		quote                = &RefundQuote{ReservationId: reservationId}
+		state, applied       string
+		billPayment, deposit string
+		reservationTime      time.Time
+		secondsUntil         float64
+	)
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			r.restaurant_id,
+			r.status,
+			r.reservation_time,
+			COALESCE(r.payment_id::text, ''),
+			COALESCE(r.deposit_payment_id::text, ''),
+			rs.currency,
+			COALESCE(r.cancellation_policy::text, ''),
+			EXTRACT(EPOCH FROM r.reservation_time - CURRENT_TIMESTAMP)
+		FROM
+			reservations r
+			JOIN Restaurants rs ON rs.id = r.restaurant_id
+		WHERE
+			r.id = $1 AND r.deleted_at = 0
+	`, reservationId).Scan(&quote.RestaurantId, &state, &reservationTime, &billPayment, &deposit, &quote.Currency, &applied,
+		&secondsUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to quote refund: %v", err)
+	}
+	ids := paymentIds(billPayment, deposit)
+	if len(ids) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "reservation has no payment to refund")
+	}
+	switch paymentId {
+	case "":
+		quote.PaymentId = ids[0]
+	case billPayment, deposit:
+		quote.PaymentId = paymentId
+	default:
+		return nil, status.Error(codes.InvalidArgument, "payment_id is not a payment of this reservation")
+	}
+
+	err = r.DB.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(amount_minor), 0)
+		FROM
+			Refunds
+		WHERE
+			payment_id = $1 AND status = 'Completed'
+	`, quote.PaymentId).Scan(&quote.Refunded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote refund: %v", err)
+	}
+
+	switch {
+	case state == "Cancelled":
+		if applied != "" {
+			policy := &pb.AppliedCancellationPolicy{}
+			if err = protojson.Unmarshal([]byte(applied), policy); err != nil {
+				return nil, fmt.Errorf("failed to decode cancellation policy: %v", err)
+			}
+			quote.FeePercent = policy.FeePercent
+		}
+	case (state == "Pending" || state == "Confirmed") && secondsUntil > 0:
+		policy, err := loadCancellationPolicy(ctx, r.DB, quote.RestaurantId)
+		if err != nil {
+			return nil, err
+		}
+		quote.FeePercent = EvaluateCancellation(policy, reservationTime, time.Duration(secondsUntil*float64(time.Second))).FeePercent
+	default:
+		quote.FeePercent = 100
+	}
+	return quote, nil
+}
+
+// FindRefund returns the refund recorded under an idempotency key, or nil.
+func (r *ReservationRepo) FindRefund(ctx context.Context, idempotencyKey string) (_ *RefundRecord, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.FindRefund", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rec, err := scanRefund(r.DB.QueryRowContext(ctx, `
+		SELECT
+			`+refundColumns+`
+		FROM
+			Refunds
+		WHERE
+			idempotency_key = $1
+	`, idempotencyKey))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find refund: %v", err)
+	}
+	return rec, nil
+}
+
+// BeginRefund records a pending refund of balanceBefore's payment. Only one
+// refund of a payment may be pending; a refund whose idempotency key is
+// already taken returns the stored one instead.
+func (r *ReservationRepo) BeginRefund(ctx context.Context, refund *pb.Refund, balanceBefore int64) (_ *RefundRecord, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.BeginRefund", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin refund: %v", err)
+	}
+	defer tx.Rollback()
+
+	var pending bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM Refunds WHERE payment_id = $1 AND status = 'Pending' AND idempotency_key <> $2
+		)
+	`, refund.PaymentId, refund.IdempotencyKey).Scan(&pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin refund: %v", err)
+	}
+	if pending {
+		return nil, status.Error(codes.Aborted, "another refund of this payment is in progress")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO Refunds (
+			idempotency_key,
+			reservation_id,
+			payment_id,
+			amount_minor,
+			balance_before_minor,
+			reason,
+			requested_by
+		)
+		VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			NULLIF($6, ''),
+			NULLIF($7, '')::uuid
+		)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`, refund.IdempotencyKey, refund.ReservationId, refund.PaymentId, refund.Amount.GetMinorUnits(), balanceBefore, refund.Reason, refund.RequestedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin refund: %v", err)
+	}
+
+	rec, err := scanRefund(tx.QueryRowContext(ctx, `
+		SELECT
+			`+refundColumns+`
+		FROM
+			Refunds
+		WHERE
+			idempotency_key = $1
+	`, refund.IdempotencyKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin refund: %v", err)
+	}
+	if rec.Refund.ReservationId != refund.ReservationId {
+		return nil, status.Error(codes.InvalidArgument, "idempotency_key was already used for another reservation")
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to begin refund: %v", err)
+	}
+	return rec, nil
+}
+
+// CompleteRefund marks a refund as completed once the payment service has
+// applied it, and records a RefundCompleted event. Completing a completed
+// refund returns it unchanged.
+func (r *ReservationRepo) CompleteRefund(ctx context.Context, id string) (_ *pb.Refund, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CompleteRefund", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete refund: %v", err)
+	}
+	defer tx.Rollback()
+
+	rec, err := scanRefund(tx.QueryRowContext(ctx, `
+		UPDATE
+			Refunds
+		SET
+			status = 'Completed',
+			completed_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND status = 'Pending'
+		RETURNING
+			`+refundColumns+`
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		rec, err = scanRefund(tx.QueryRowContext(ctx, `SELECT `+refundColumns+` FROM Refunds WHERE id = $1`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "refund not found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete refund: %v", err)
+		}
+		return rec.Refund, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete refund: %v", err)
+	}
+
+	refund := rec.Refund
+	err = addOutboxEvent(ctx, tx, "reservation", refund.ReservationId, EventRefundCompleted, RefundCompletedEvent{
+		RefundId:      refund.Id,
+		ReservationId: refund.ReservationId,
+		PaymentId:     refund.PaymentId,
+		AmountMinor:   refund.Amount.MinorUnits,
+		Currency:      refund.Amount.Currency,
+		Reason:        refund.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to complete refund: %v", err)
+	}
+	return refund, nil
+}
+
+func (r *ReservationRepo) ListRefunds(ctx context.Context, req *pb.ListRefundsRequest) (_ *pb.ListRefundsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListRefunds", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			`+refundColumns+`
+		FROM
+			Refunds
+		WHERE
+			reservation_id = $1
+		ORDER BY
+			created_at
+	`, req.ReservationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %v", err)
+	}
+	defer rows.Close()
+
+	var refunds []*pb.Refund
+	for rows.Next() {
+		rec, err := scanRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan refund: %v", err)
+		}
+		refunds = append(refunds, rec.Refund)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %v", err)
+	}
+	return &pb.ListRefundsResponse{Refunds: refunds}, nil
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRefundQuoteLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		quote    RefundQuote
+		balance  int64
+		manager  bool
+		expected int64
+	}{
+		{"free cancellation", RefundQuote{FeePercent: 0}, 8000, false, 8000},
+		{"late fee", RefundQuote{FeePercent: 25}, 8000, false, 6000},
+		{"late fee partly refunded", RefundQuote{FeePercent: 25, Refunded: 2000}, 6000, false, 4000},
+		{"policy already refunded", RefundQuote{FeePercent: 25, Refunded: 6000}, 2000, false, 0},
+		{"taken place", RefundQuote{FeePercent: 100}, 8000, false, 0},
+		{"manager overrides policy", RefundQuote{FeePercent: 100}, 8000, true, 8000},
+		{"rounds to minor units", RefundQuote{FeePercent: 33}, 1000, false, 670},
+		{"rounds half away from zero", RefundQuote{FeePercent: 50}, 1001, false, 501},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.quote.Limit(tt.balance, tt.manager))
+		})
+	}
+}
+
+func TestRefunds(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+	userId := "67188541-6344-42bd-8be2-a14c558d30aa"
+	paymentId := "5d0b1e9a-2f4c-4a7e-8b1d-6c3e2a9f0b7d"
+
+	_, err = repo.SetRestaurantStaff(context.Background(), &pb.SetRestaurantStaffRequest{
+		Member: &pb.StaffMember{RestaurantId: restaurantId, UserId: userId, Role: "owner"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = repo.SetRestaurantStaff(context.Background(), &pb.SetRestaurantStaffRequest{
+		Member: &pb.StaffMember{RestaurantId: restaurantId, UserId: userId, Role: RoleManager},
+	})
+	assert.NoError(t, err)
+	role, err := repo.StaffRole(context.Background(), restaurantId, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleManager, role)
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          userId,
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-07-01 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.QuoteRefund(context.Background(), res.Reservation.Id, "")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	err = repo.SetReservationPayment(context.Background(), res.Reservation.Id, paymentId, &pb.Money{Currency: "USD"}, "card")
+	assert.NoError(t, err)
+
+	refund := &pb.Refund{
+		ReservationId:  res.Reservation.Id,
+		PaymentId:      paymentId,
+		Amount:         &pb.Money{MinorUnits: 3000},
+		RequestedBy:    userId,
+		IdempotencyKey: "refund-test-" + res.Reservation.Id,
+	}
+	rec, err := repo.BeginRefund(context.Background(), refund, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, RefundPending, rec.Refund.Status)
+
+	retried, err := repo.BeginRefund(context.Background(), refund, 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, rec.Refund.Id, retried.Refund.Id)
+	assert.Equal(t, int64(8000), retried.BalanceBefore)
+
+	_, err = repo.BeginRefund(context.Background(), &pb.Refund{
+		ReservationId:  res.Reservation.Id,
+		PaymentId:      paymentId,
+		Amount:         &pb.Money{MinorUnits: 1000},
+		IdempotencyKey: "another-" + res.Reservation.Id,
+	}, 8000)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	completed, err := repo.CompleteRefund(context.Background(), rec.Refund.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, RefundCompleted, completed.Status)
+	_, err = repo.CompleteRefund(context.Background(), rec.Refund.Id)
+	assert.NoError(t, err)
+
+	quote, err := repo.QuoteRefund(context.Background(), res.Reservation.Id, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), quote.Refunded)
+
+	list, err := repo.ListRefunds(context.Background(), &pb.ListRefundsRequest{ReservationId: res.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Len(t, list.Refunds, 1)
+
+	_, err = repo.SetRestaurantStaff(context.Background(), &pb.SetRestaurantStaffRequest{
+		Member: &pb.StaffMember{RestaurantId: restaurantId, UserId: userId},
+	})
+	assert.NoError(t, err)
+}
+
+func TestPurgeKeepsRefundedReservations(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	paymentId := "0c7b3f5e-8a21-4d6f-9e4b-2f1a7c8d9e30"
+
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: "2030-07-02 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	id := res.Reservation.Id
+
+	err = repo.SetReservationPayment(context.Background(), id, paymentId, &pb.Money{Currency: "USD"}, "card")
+	assert.NoError(t, err)
+	rec, err := repo.BeginRefund(context.Background(), &pb.Refund{
+		ReservationId:  id,
+		PaymentId:      paymentId,
+		Amount:         &pb.Money{MinorUnits: 5000},
+		IdempotencyKey: "purge-test-" + id,
+	}, 5000)
+	assert.NoError(t, err)
+	_, err = repo.CompleteRefund(context.Background(), rec.Refund.Id)
+	assert.NoError(t, err)
+
+	// Age the deleted reservation past the retention period.
+	_, err = db.Exec(`UPDATE reservations SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE id = $1`, id)
+	assert.NoError(t, err)
+
+	_, err = repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+
+	var kept bool
+	err = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM reservations WHERE id = $1)`, id).Scan(&kept)
+	assert.NoError(t, err)
+	assert.True(t, kept)
+	list, err := repo.ListRefunds(context.Background(), &pb.ListRefundsRequest{ReservationId: id})
+	assert.NoError(t, err)
+	assert.Len(t, list.Refunds, 1)
+}
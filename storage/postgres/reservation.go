@@ -8,41 +8,132 @@ import (
 	"time"
 
 	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func (r *ReservationRepo) CreateReservation(req *pb.CreateReservationRequest) (*pb.CreateReservationResponse, error) {
+// reservationColumns lists the columns read by scanReservation.
+const reservationColumns = `id, 
+			user_id, 
+			restaurant_id, 
+			reservation_time, 
+			status, 
+			COALESCE(cancellation_reason, ''), 
+			deposit_required, 
+			party_size, 
+			deposit_minor, 
+			(SELECT currency FROM Restaurants rs WHERE rs.id = restaurant_id), 
+			deposit_paid_at IS NOT NULL, 
+			ARRAY(SELECT n.tag_code FROM ReservationDietaryNotes n WHERE n.reservation_id = id ORDER BY n.tag_code)`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanReservation scans reservationColumns followed by extra.
+func scanReservation(row rowScanner, extra ...interface{}) (*pb.Reservation, error) {
+	reservation := &pb.Reservation{DepositAmount: &pb.Money{}}
+	dest := []interface{}{
+		&reservation.Id, &reservation.UserId, &reservation.RestaurantId, &reservation.ReservationTime,
+		&reservation.Status, &reservation.CancellationReason, &reservation.DepositRequired,
+		&reservation.PartySize, &reservation.DepositAmount.MinorUnits, &reservation.DepositAmount.Currency,
+		&reservation.DepositPaid, pq.Array(&reservation.DietaryNotes),
+	}
+	return reservation, row.Scan(append(dest, extra...)...)
+}
+
+func (r *ReservationRepo) CreateReservation(ctx context.Context, req *pb.CreateReservationRequest) (_ *pb.CreateReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CreateReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO reservations (
 			user_id, 
 			restaurant_id, 
 			reservation_time, 
-			status
+			status, 
+			deposit_required, 
+			party_size, 
+			deposit_minor
 		)
-		VALUES (
-			$1, 
-			$2, 
-			$3, 
-			$4
+		SELECT 
+			$1::uuid, 
+			$2::uuid, 
+			$3::timestamp, 
+			CASE WHEN $5::boolean THEN 'Pending' ELSE $4 END, 
+			$5, 
+			$6, 
+			$7
+		WHERE EXISTS (
+			SELECT 1 FROM Restaurants WHERE id = $2 AND deleted_at = 0 AND is_active
 		)
 		RETURNING 
-			id, 
-			user_id, 
-			restaurant_id, 
-			reservation_time, 
-			status;
+			` + reservationColumns + `;
 	`
-	reservation := &pb.Reservation{}
-	err := r.DB.QueryRow(query, req.UserId, req.RestaurantId, req.ReservationTime, req.Status).Scan(
-		&reservation.Id, &reservation.UserId, &reservation.RestaurantId, &reservation.ReservationTime, &reservation.Status)
+	partySize := req.PartySize
+	if partySize == 0 {
+		partySize = 1
+	}
+	if partySize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "party_size must be positive")
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reservation: %v", err)
 	}
+	defer tx.Rollback()
+
+	depositRequired, err := checkNoShowPolicy(ctx, tx, req.RestaurantId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	deposit, err := depositFor(ctx, tx, req.RestaurantId, req.ReservationTime, partySize, depositRequired)
+	if err != nil {
+		return nil, err
+	}
+	// A reservation with a deposit stays pending until it is paid.
+	depositRequired = depositRequired || deposit > 0
+
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, query, req.UserId, req.RestaurantId, req.ReservationTime, req.Status, depositRequired, partySize, deposit))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.FailedPrecondition, "restaurant is not accepting reservations")
+		}
+		return nil, fmt.Errorf("failed to create reservation: %v", err)
+	}
+	if reservation.DietaryNotes, err = saveDietaryNotes(ctx, tx, reservation.Id, req.DietaryNotes); err != nil {
+		return nil, err
+	}
+
+	err = addOutboxEvent(ctx, tx, "reservation", reservation.Id, EventReservationCreated, ReservationCreatedEvent{
+		ReservationId:   reservation.Id,
+		UserId:          reservation.UserId,
+		RestaurantId:    reservation.RestaurantId,
+		ReservationTime: reservation.ReservationTime,
+		Status:          reservation.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = queueNotification(ctx, tx, reservation.Id, NotificationConfirmation); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create reservation: %v", err)
+	}
 	return &pb.CreateReservationResponse{Reservation: reservation}, nil
 }
 
-func (r *ReservationRepo) ListReservations(req *pb.ListReservationsRequest) (*pb.ListReservationsResponse, error) {
+func (r *ReservationRepo) ListReservations(ctx context.Context, req *pb.ListReservationsRequest) (_ *pb.ListReservationsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListReservations", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		params = make(map[string]interface{})
 		args   []interface{}
@@ -50,11 +141,7 @@ func (r *ReservationRepo) ListReservations(req *pb.ListReservationsRequest) (*pb
 	)
 	query := `
 		SELECT 
-			id, 
-			user_id, 
-			restaurant_id, 
-			reservation_time, 
-			status 
+			` + reservationColumns + `
 		FROM 
 			reservations 
 		WHERE deleted_at = 0  `
@@ -84,7 +171,7 @@ func (r *ReservationRepo) ListReservations(req *pb.ListReservationsRequest) (*pb
 
 	query, args = ReplaceQueryParams(query, params)
 
-	rows, err := r.DB.Query(query, args...)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list reservations: %v", err)
 	}
@@ -92,8 +179,8 @@ func (r *ReservationRepo) ListReservations(req *pb.ListReservationsRequest) (*pb
 
 	var reservations []*pb.Reservation
 	for rows.Next() {
-		reservation := &pb.Reservation{}
-		if err := rows.Scan(&reservation.Id, &reservation.UserId, &reservation.RestaurantId, &reservation.ReservationTime, &reservation.Status); err != nil {
+		reservation, err := scanReservation(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan reservations: %v", err)
 		}
 		reservations = append(reservations, reservation)
@@ -101,31 +188,49 @@ func (r *ReservationRepo) ListReservations(req *pb.ListReservationsRequest) (*pb
 	return &pb.ListReservationsResponse{Reservations: reservations}, nil
 }
 
-func (r *ReservationRepo) GetReservation(req *pb.GetReservationRequest) (*pb.GetReservationResponse, error) {
+func (r *ReservationRepo) GetReservation(ctx context.Context, req *pb.GetReservationRequest) (_ *pb.GetReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT 
-			id, 
-			user_id, 
-			restaurant_id, 
-			reservation_time, 
-			status 
+			` + reservationColumns + `
 		FROM 
 			reservations 
 		WHERE id = $1 AND deleted_at = 0;
 	`
-	reservation := &pb.Reservation{}
-	err := r.DB.QueryRow(query, req.Id).Scan(
-		&reservation.Id, &reservation.UserId, &reservation.RestaurantId, &reservation.ReservationTime, &reservation.Status)
+	reservation, err := scanReservation(r.DB.QueryRowContext(ctx, query, req.Id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("reservation not found")
+			return nil, status.Error(codes.NotFound, "reservation not found")
 		}
 		return nil, fmt.Errorf("failed to get reservation: %v", err)
 	}
 	return &pb.GetReservationResponse{Reservation: reservation}, nil
 }
 
-func (r *ReservationRepo) UpdateReservation(req *pb.UpdateReservationRequest) (*pb.UpdateReservationResponse, error) {
+// checkStatusTransition refuses status changes UpdateReservation must not
+// make. Cancelling, seating and no-shows have their own calls, and a
+// reservation that is over can no longer be changed.
+func checkStatusTransition(from, to string) error {
+	switch from {
+	case "Pending", "Confirmed":
+	default:
+		return status.Errorf(codes.FailedPrecondition, "reservation is %s and can no longer be changed", from)
+	}
+	switch to {
+	case "Pending", "Confirmed":
+		return nil
+	case "Cancelled":
+		return status.Error(codes.InvalidArgument, "use CancelReservation to cancel a reservation")
+	}
+	return status.Errorf(codes.InvalidArgument, "status cannot be changed to %q", to)
+}
+
+func (r *ReservationRepo) UpdateReservation(ctx context.Context, req *pb.UpdateReservationRequest) (_ *pb.UpdateReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpdateReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE 
 			reservations 
@@ -134,48 +239,252 @@ func (r *ReservationRepo) UpdateReservation(req *pb.UpdateReservationRequest) (*
 			restaurant_id = $3, 
 			reservation_time = $4, 
 			status = $5, 
+			party_size = $6, 
+			deposit_required = $7, 
+			deposit_minor = $8, 
 			updated_at = CURRENT_TIMESTAMP
 		WHERE 
 			id = $1 AND deleted_at = 0
 		RETURNING 
-			id, 
-			user_id, 
-			restaurant_id, 
-			reservation_time, 
-			status;
+			` + reservationColumns + `;
 	`
-	reservation := &pb.Reservation{}
-	err := r.DB.QueryRow(query, req.Id, req.UserId, req.RestaurantId, req.ReservationTime, req.Status).Scan(
-		&reservation.Id, &reservation.UserId, &reservation.RestaurantId, &reservation.ReservationTime, &reservation.Status)
+	if req.PartySize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "party_size must be positive")
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reservation: %v", err)
+	}
+	defer tx.Rollback()
+
+	previous, err := scanReservation(tx.QueryRowContext(ctx, `
+		SELECT 
+			`+reservationColumns+`
+		FROM 
+			reservations 
+		WHERE 
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, req.Id))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
+		return nil, fmt.Errorf("failed to update reservation: %v", err)
+	}
+	newStatus := req.Status
+	if newStatus == "" {
+		newStatus = previous.Status
+	}
+	if err = checkStatusTransition(previous.Status, newStatus); err != nil {
+		return nil, err
+	}
+	partySize := req.PartySize
+	if partySize == 0 {
+		partySize = previous.PartySize
+	}
+
+	// A booking moved to another restaurant or guest goes through that
+	// restaurant's no-show policy again, as a new booking would.
+	required := previous.DepositRequired
+	if req.RestaurantId != previous.RestaurantId || req.UserId != previous.UserId {
+		if required, err = checkNoShowPolicy(ctx, tx, req.RestaurantId, req.UserId); err != nil {
+			return nil, err
+		}
+	}
+	// An unpaid deposit follows the party size and time of the booking. A
+	// paid one stays what was paid.
+	depositRequired, deposit := previous.DepositRequired, previous.DepositAmount.MinorUnits
+	if !previous.DepositPaid {
+		if deposit, err = depositFor(ctx, tx, req.RestaurantId, req.ReservationTime, partySize, required); err != nil {
+			return nil, err
+		}
+		depositRequired = required || deposit > 0
+		if depositRequired && !previous.DepositRequired {
+			return nil, status.Error(codes.FailedPrecondition, "the changed booking requires a deposit, cancel it and book again")
+		}
+		if depositRequired && newStatus == "Confirmed" {
+			return nil, status.Error(codes.FailedPrecondition, "reservation is held until its deposit is paid")
+		}
+	}
+
+	// The stock of the ordered meals moves with the reservation's day.
+	if err = releaseMenuStock(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, query, req.Id, req.UserId, req.RestaurantId, req.ReservationTime, newStatus, partySize, depositRequired, deposit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reservation: %v", err)
+	}
+	if err = holdMenuStock(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+	if len(req.DietaryNotes) > 0 || req.UpdateDietaryNotes {
+		if reservation.DietaryNotes, err = saveDietaryNotes(ctx, tx, reservation.Id, req.DietaryNotes); err != nil {
+			return nil, err
+		}
+	}
+
+	if reservation.Status != previous.Status || reservation.ReservationTime != previous.ReservationTime {
+		if err = queueNotification(ctx, tx, reservation.Id, NotificationChange); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to update reservation: %v", err)
 	}
 	return &pb.UpdateReservationResponse{Reservation: reservation}, nil
 }
 
-func (r *ReservationRepo) DeleteReservation(req *pb.DeleteReservationRequest) (*pb.DeleteReservationResponse, error) {
+func (r *ReservationRepo) DeleteReservation(ctx context.Context, req *pb.DeleteReservationRequest) (_ *pb.DeleteReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.DeleteReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE 
 			reservations 
 		SET 
 			deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
 		WHERE 
-			id = $1 AND deleted_at = 0;
+			id = $1 AND deleted_at = 0
+		RETURNING 
+			user_id, 
+			restaurant_id, 
+			reservation_time, 
+			status;
 	`
 
-	_, err := r.DB.Exec(query, req.Id)
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete reservation: %v", err)
+	}
+	defer tx.Rollback()
+
+	// A reservation under way is neither cancelled nor deleted: it ends as
+	// seated or as a no-show.
+	var (
+		state   string
+		started bool
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			status,
+			reservation_time <= CURRENT_TIMESTAMP
+		FROM
+			reservations
+		WHERE
+			id = $1 AND deleted_at = 0
+		FOR UPDATE
+	`, req.Id).Scan(&state, &started)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("reservation not found")
+			return nil, status.Error(codes.NotFound, "reservation not found")
 		}
 		return nil, fmt.Errorf("failed to delete reservation: %v", err)
 	}
+	if started && (state == "Pending" || state == "Confirmed") {
+		return nil, status.Error(codes.FailedPrecondition, "reservation has already started and cannot be deleted")
+	}
+
+	event := ReservationCancelledEvent{ReservationId: req.Id, Reason: "Reservation deleted"}
+	var previousStatus string
+	err = tx.QueryRowContext(ctx, query, req.Id).Scan(&event.UserId, &event.RestaurantId, &event.ReservationTime, &previousStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete reservation: %v", err)
+	}
+
+	if previousStatus == "Pending" || previousStatus == "Confirmed" {
+		if err = releaseMenuStock(ctx, tx, req.Id); err != nil {
+			return nil, err
+		}
+		if err = addOutboxEvent(ctx, tx, "reservation", req.Id, EventReservationCancelled, event); err != nil {
+			return nil, err
+		}
+		if err = queueNotification(ctx, tx, req.Id, NotificationCancellation); err != nil {
+			return nil, err
+		}
+	}
+	// The orders go with the reservation, and are purged and restored with it.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE
+			ReservationOrders
+		SET
+			deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP)
+		WHERE
+			reservation_id = $1 AND deleted_at = 0
+	`, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete reservation orders: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to delete reservation: %v", err)
+	}
 	return &pb.DeleteReservationResponse{Message: "Reservation deleted successfully"}, nil
 }
 
-func (r *ReservationRepo) CheckReservation(ctx context.Context, in *pb.CheckReservationRequest) (*pb.CheckReservationResponse, error) {
+func (r *ReservationRepo) RestoreReservation(ctx context.Context, req *pb.RestoreReservationRequest) (_ *pb.RestoreReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.RestoreReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE 
+			reservations 
+		SET 
+			deleted_at = 0, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			id = $1 AND deleted_at <> 0
+		RETURNING 
+			` + reservationColumns + `;
+	`
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore reservation: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Orders deleted along with the reservation come back with it; those
+	// removed from the order before stay removed.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE
+			ReservationOrders o
+		SET
+			deleted_at = 0
+		FROM
+			reservations r
+		WHERE
+			r.id = $1 AND r.deleted_at <> 0
+			AND o.reservation_id = r.id AND o.deleted_at = r.deleted_at
+	`, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore reservation orders: %v", err)
+	}
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, query, req.Id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "deleted reservation not found")
+		}
+		return nil, fmt.Errorf("failed to restore reservation: %v", err)
+	}
+	// Deleting gave back the stock of an upcoming reservation's meals.
+	if reservation.Status == "Pending" || reservation.Status == "Confirmed" {
+		if err = holdMenuStock(ctx, tx, req.Id); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to restore reservation: %v", err)
+	}
+	return &pb.RestoreReservationResponse{Reservation: reservation}, nil
+}
+
+func (r *ReservationRepo) CheckReservation(ctx context.Context, in *pb.CheckReservationRequest) (_ *pb.CheckReservationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CheckReservation", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	var exists bool
-	err := r.DB.QueryRow(`
+	err = r.DB.QueryRowContext(ctx, `
 		SELECT 
 			EXISTS (
 				SELECT
@@ -194,37 +503,185 @@ func (r *ReservationRepo) CheckReservation(ctx context.Context, in *pb.CheckRese
 	return &pb.CheckReservationResponse{Available: exists}, nil
 }
 
-func (r *ReservationRepo) OrderMeals(ctx context.Context, in *pb.OrderMealsRequest) (*pb.OrderMealsResponse, error) {
+func (r *ReservationRepo) OrderMeals(ctx context.Context, in *pb.OrderMealsRequest) (_ *pb.OrderMealsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.OrderMeals", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	for _, meal := range in.Meals {
+		if meal.Quantity < 0 {
+			return nil, status.Error(codes.InvalidArgument, "quantity must not be negative")
+		}
+	}
+
 	var reservationTime time.Time
-	
-	err := r.DB.QueryRow(`
+	event := MealsOrderedEvent{ReservationId: in.ReservationId}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		state string
+		paid  bool
+	)
+	err = tx.QueryRowContext(ctx, `
 		SELECT
-			reservation_time
+			reservation_time,
+			user_id,
+			restaurant_id,
+			status,
+			payment_id IS NOT NULL
 		FROM
 			reservations
 		WHERE 
 			deleted_at = 0 and id = $1
-	`, in.ReservationId).Scan(&reservationTime)
+		FOR UPDATE
+	`, in.ReservationId).Scan(&reservationTime, &event.UserId, &event.RestaurantId, &state, &paid)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "reservation not found")
+		}
 		return nil, err
 	}
+	if state != "Pending" && state != "Confirmed" {
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %s and cannot take orders", state)
+	}
+	// The bill is settled once paid, so it can't take more meals.
+	if paid {
+		return nil, status.Error(codes.FailedPrecondition, "reservation is already paid")
+	}
 
-	// Ovqat buyurtmalarini Redis-ga bir marta HSet bilan qo'shish
+	if err = reserveMenuStock(ctx, tx, in.ReservationId, reservationTime, in.Meals); err != nil {
+		return nil, err
+	}
+	if err = saveOrderLines(ctx, tx, in.ReservationId, event.RestaurantId, in.Meals); err != nil {
+		return nil, err
+	}
+	warnings, err := orderWarnings(ctx, tx, in.ReservationId, in.Meals)
+	if err != nil {
+		return nil, err
+	}
+	for _, meal := range in.Meals {
+		event.Meals = append(event.Meals, MealOrderLine{MenuItemId: meal.MenuItemId, Quantity: meal.Quantity, OptionIds: meal.OptionIds})
+	}
+	if err = addOutboxEvent(ctx, tx, "reservation", in.ReservationId, EventMealsOrdered, event); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// The Redis copy is written only once the order is committed. An order
+	// replaces the quantities ordered before, so a call that failed here can
+	// be retried.
 	mealData := make(map[string]interface{})
 	for _, meal := range in.Meals {
-		mealData[meal.MenuItemId] = meal.Quantity
+		field := meal.MenuItemId
+		if len(meal.OptionIds) > 0 {
+			field += "|" + optionsKey(meal.OptionIds)
+		}
+		mealData[field] = meal.Quantity
 	}
-	err = r.R.HSet(ctx, in.ReservationId, mealData).Err()
+	_, err = r.R.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// Ovqat buyurtmalarini Redis-ga bir marta HSet bilan qo'shish
+		pipe.HSet(ctx, in.ReservationId, mealData)
+		// Redisda saqlash muddatini belgilash (expiring key)
+		pipe.Expire(ctx, in.ReservationId, time.Until(reservationTime))
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return &pb.OrderMealsResponse{Status: "success", Warnings: warnings}, nil
+}
 
-	// Redisda saqlash muddatini belgilash (expiring key)
-	expiration := time.Until(reservationTime)
-	err = r.R.Expire(ctx, in.ReservationId, expiration).Err()
+// orderWarnings checks the ordered meals against the reservation's dietary
+// notes.
+func orderWarnings(ctx context.Context, tx *sql.Tx, reservationId string, meals []*pb.MealOrder) ([]*pb.DietaryWarning, error) {
+	notes, err := loadDietaryNotes(ctx, tx, reservationId)
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+	var ids []string
+	for _, meal := range meals {
+		if meal.Quantity > 0 {
+			ids = append(ids, meal.MenuItemId)
+		}
+	}
+	items, err := loadMenuItems(ctx, tx, ids)
 	if err != nil {
 		return nil, err
 	}
-	
-	return &pb.OrderMealsResponse{Status: "success"}, nil
-}
\ No newline at end of file
+	return DietaryWarnings(notes, items), nil
+}
+
+// saveOrderLines stores the ordered meals in ReservationOrders, one line per
+// item and set of options, priced at the item's current price. A line's
+// quantity replaces the one ordered before, at the price of the new order; a
+// quantity of 0 removes the line. Only items on the restaurant's menu can be
+// ordered, with options that satisfy the item's modifier groups.
+func saveOrderLines(ctx context.Context, tx *sql.Tx, reservationId, restaurantId string, meals []*pb.MealOrder) error {
+	for _, meal := range meals {
+		key := optionsKey(meal.OptionIds)
+		_, err := tx.ExecContext(ctx, `
+			UPDATE
+				ReservationOrders
+			SET
+				deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE
+				reservation_id = $1 AND menu_item_id = $2 AND options_key = $3 AND deleted_at = 0
+		`, reservationId, meal.MenuItemId, key)
+		if err != nil {
+			return fmt.Errorf("failed to save order: %v", err)
+		}
+		if meal.Quantity == 0 {
+			continue
+		}
+
+		var orderId string
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO ReservationOrders (
+				reservation_id,
+				menu_item_id,
+				quantity,
+				options_key,
+				unit_price_minor
+			)
+			SELECT
+				$1,
+				id,
+				$3,
+				$5,
+				price_minor
+			FROM
+				Menu
+			WHERE
+				id = $2 AND restaurant_id = $4 AND deleted_at = 0
+			RETURNING
+				id
+		`, reservationId, meal.MenuItemId, meal.Quantity, restaurantId, key).Scan(&orderId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return status.Errorf(codes.InvalidArgument, "menu item %s is not on the restaurant's menu", meal.MenuItemId)
+			}
+			return fmt.Errorf("failed to save order: %v", err)
+		}
+
+		groups, err := loadModifierGroups(ctx, tx, []string{meal.MenuItemId})
+		if err != nil {
+			return err
+		}
+		options, err := SelectModifierOptions(groups[meal.MenuItemId], meal.OptionIds)
+		if err != nil {
+			return err
+		}
+		if err = saveOrderLineOptions(ctx, tx, orderId, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,10 +9,12 @@ import (
 	pb "reservation-service/generated/reservation_service"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCreateReservation(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -28,7 +30,7 @@ func TestCreateReservation(t *testing.T) {
 		Status:          "Confirmed",
 	}
 
-	resp, err := repo.CreateReservation(req)
+	resp, err := repo.CreateReservation(context.Background(), req)
 
 	expectedRespnce := &pb.CreateReservationResponse{
 		Reservation: &pb.Reservation{
@@ -48,7 +50,7 @@ func TestCreateReservation(t *testing.T) {
 }
 
 func TestListReservations(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -61,7 +63,7 @@ func TestListReservations(t *testing.T) {
 		RestaurantId: "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
 	}
 
-	resp, err := repo.ListReservations(req)
+	resp, err := repo.ListReservations(context.Background(), req)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -84,7 +86,7 @@ func TestListReservations(t *testing.T) {
 }
 
 func TestGetReservation(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -94,7 +96,7 @@ func TestGetReservation(t *testing.T) {
 	repo := ReservationRepo{DB: db}
 
 	req := &pb.GetReservationRequest{Id: "e93dc146-97dc-417c-b312-f0f1f349ee78"}
-	resp, err := repo.GetReservation(req)
+	resp, err := repo.GetReservation(context.Background(), req)
 	assert.NoError(t, err)
 	
 	expectedResponse := pb.GetReservationResponse{
@@ -113,7 +115,7 @@ func TestGetReservation(t *testing.T) {
 }
 
 func TestUpdateReservation(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -129,7 +131,7 @@ func TestUpdateReservation(t *testing.T) {
 		ReservationTime: "2024-07-10 11:41:40",
 		Status:          "Confirmed",
 	}
-	resp, err := repo.UpdateReservation(req)
+	resp, err := repo.UpdateReservation(context.Background(), req)
 	assert.NoError(t, err)
 	
 	expectedResponse := &pb.UpdateReservationResponse{
@@ -147,8 +149,46 @@ func TestUpdateReservation(t *testing.T) {
 	}
 }
 
+func TestUpdateReservationStatus(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	created, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: time.Now().Add(24 * time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	req := &pb.UpdateReservationRequest{
+		Id:              created.Reservation.Id,
+		UserId:          created.Reservation.UserId,
+		RestaurantId:    created.Reservation.RestaurantId,
+		ReservationTime: created.Reservation.ReservationTime,
+	}
+
+	req.Status = "Seated"
+	_, err = repo.UpdateReservation(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	req.Status = "Cancelled"
+	_, err = repo.UpdateReservation(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = repo.SeatReservation(context.Background(), &pb.SeatReservationRequest{Id: created.Reservation.Id})
+	assert.NoError(t, err)
+	req.Status = "Confirmed"
+	_, err = repo.UpdateReservation(context.Background(), req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
 func TestDeleteReservation(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -158,14 +198,14 @@ func TestDeleteReservation(t *testing.T) {
 	repo := ReservationRepo{DB: db}
 
 	req := &pb.DeleteReservationRequest{Id: "e93dc146-97dc-417c-b312-f0f1f349ee78"}
-	resp, err := repo.DeleteReservation(req)
+	resp, err := repo.DeleteReservation(context.Background(), req)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "Reservation deleted successfully", resp.Message)
 }
 
 func TestCheckReservation(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -187,7 +227,7 @@ func TestCheckReservation(t *testing.T) {
 }
 
 func TestOrderMeals(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("failed to setup test database: %v", err)
 		return
@@ -211,3 +251,184 @@ func TestOrderMeals(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Equal(t, "success", resp.Status)
 }
+
+func TestRestoreReservation(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	req := &pb.RestoreReservationRequest{Id: "e93dc146-97dc-417c-b312-f0f1f349ee78"}
+	resp, err := repo.RestoreReservation(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, req.Id, resp.Reservation.Id)
+}
+
+func TestDeleteReservationNotFound(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	req := &pb.DeleteReservationRequest{Id: "00000000-0000-0000-0000-000000000000"}
+	resp, err := repo.DeleteReservation(context.Background(), req)
+	assert.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, resp)
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	resp, err := repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestPurgeKeepsRestaurantsWithLiveChildren(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+
+	restaurant, err := repo.CreateRestaurant(context.Background(), &pb.CreateRestaurantRequest{Name: "Purge", Address: "Yunusobod"})
+	assert.NoError(t, err)
+	restaurantId := restaurant.Restaurant.Id
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2020-07-01 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+
+	// The restaurant was deleted long ago, but its reservation never was.
+	_, err = db.Exec(`UPDATE Restaurants SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE id = $1`, restaurantId)
+	assert.NoError(t, err)
+
+	_, err = repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+	got, err := repo.GetReservation(context.Background(), &pb.GetReservationRequest{Id: res.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, restaurantId, got.Reservation.RestaurantId)
+
+	// Once the reservation is purged too, so is the restaurant.
+	_, err = db.Exec(`UPDATE reservations SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE id = $1`, res.Reservation.Id)
+	assert.NoError(t, err)
+	resp, err := repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, resp.Restaurants, int64(1))
+	var exists bool
+	err = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM Restaurants WHERE id = $1)`, restaurantId).Scan(&exists)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDeleteReservationTakesItsOrders(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	restaurantId := "a9a9858a-def9-4ab0-9925-a40177cd9b7d"
+
+	item, err := repo.CreateMenuItem(context.Background(), &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Purged with its reservation",
+		Price:        &pb.Money{MinorUnits: 700},
+	})
+	assert.NoError(t, err)
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurantId,
+		ReservationTime: "2030-07-03 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	id := res.Reservation.Id
+	assert.NoError(t, saveTestOrder(&repo, id, restaurantId, item.MenuItem.Id, 2))
+
+	liveOrders := func() int {
+		var n int
+		err := db.QueryRow(`SELECT COUNT(*) FROM ReservationOrders WHERE reservation_id = $1 AND deleted_at = 0`, id).Scan(&n)
+		assert.NoError(t, err)
+		return n
+	}
+
+	_, err = repo.DeleteReservation(context.Background(), &pb.DeleteReservationRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, liveOrders())
+	_, err = repo.RestoreReservation(context.Background(), &pb.RestoreReservationRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, liveOrders())
+
+	// A purged reservation takes its orders along, and counts them.
+	_, err = repo.DeleteReservation(context.Background(), &pb.DeleteReservationRequest{Id: id})
+	assert.NoError(t, err)
+	_, err = db.Exec(`UPDATE reservations SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE id = $1`, id)
+	assert.NoError(t, err)
+	_, err = db.Exec(`UPDATE ReservationOrders SET deleted_at = EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) - 90 * 86400 WHERE reservation_id = $1`, id)
+	assert.NoError(t, err)
+	resp, err := repo.PurgeDeleted(context.Background(), &pb.PurgeDeletedRequest{RetentionDays: 30})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, resp.ReservationOrders, int64(1))
+	assert.GreaterOrEqual(t, resp.Reservations, int64(1))
+	var exists bool
+	err = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM reservations WHERE id = $1)`, id).Scan(&exists)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDeleteStartedReservation(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	res, err := repo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    "a9a9858a-def9-4ab0-9925-a40177cd9b7d",
+		ReservationTime: time.Now().Add(-time.Hour).Format("2006-01-02 15:04:05"),
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	req := &pb.DeleteReservationRequest{Id: res.Reservation.Id}
+
+	_, err = repo.DeleteReservation(context.Background(), req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	var notices int
+	err = db.QueryRow(`SELECT COUNT(*) FROM Notifications WHERE reservation_id = $1 AND kind = $2`, req.Id, NotificationCancellation).Scan(&notices)
+	assert.NoError(t, err)
+	assert.Zero(t, notices)
+
+	_, err = repo.SeatReservation(context.Background(), &pb.SeatReservationRequest{Id: req.Id})
+	assert.NoError(t, err)
+	_, err = repo.DeleteReservation(context.Background(), req)
+	assert.NoError(t, err)
+}
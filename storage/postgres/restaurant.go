@@ -1,13 +1,20 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	pb "reservation-service/generated/reservation_service"
+	"reservation-service/geo"
+	"reservation-service/money"
+	"reservation-service/tracing"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ReservationRepo struct {
@@ -23,39 +30,94 @@ func NewRRestaurantRepo(db *sql.DB, r *redis.Client) *ReservationRepo {
 	}
 }
 
-func (r *ReservationRepo) CreateRestaurant(req *pb.CreateRestaurantRequest) (*pb.CreateRestaurantResponse, error) {
+func (r *ReservationRepo) CreateRestaurant(ctx context.Context, req *pb.CreateRestaurantRequest) (_ *pb.CreateRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.CreateRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO Restaurants (
 			name, 
 			address, 
 			phone_number, 
-			description
+			description, 
+			currency,
+			latitude,
+			longitude,
+			timezone
 		)
 		VALUES (
 			$1, 
 			$2, 
 			$3, 
-			$4
+			$4, 
+			$5,
+			$6,
+			$7,
+			$8
 		)
-		RETURNING 
-			id, 
-			name, 
-			address, 
-			phone_number, 
-			description;
+		RETURNING
+			` + restaurantColumns + `
 	`
-	restaurant := &pb.Restaurant{}
+	currency := req.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if !money.ValidCurrency(currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid currency %q, use an ISO 4217 code", currency)
+	}
+	translations, err := normalizeTranslations(req.Translations)
+	if err != nil {
+		return nil, err
+	}
+	latitude, longitude, err := locationArgs(req.Location)
+	if err != nil {
+		return nil, err
+	}
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restaurant: %v", err)
+	}
+	defer tx.Rollback()
 
-	err := r.DB.QueryRow(query, req.Name, req.Address, req.PhoneNumber, req.Description).Scan(
-		&restaurant.Id, &restaurant.Name, &restaurant.Address, &restaurant.PhoneNumber, &restaurant.Description)
+	if err = checkTimezone(ctx, tx, timezone); err != nil {
+		return nil, err
+	}
+	restaurant, err := scanRestaurant(tx.QueryRowContext(ctx, query, req.Name, req.Address, req.PhoneNumber, req.Description, currency,
+		latitude, longitude, timezone))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create restaurant: %v", err)
 	}
+	if err = saveTranslations(ctx, tx, restaurantTranslations, restaurant.Id, translations); err != nil {
+		return nil, err
+	}
+	if req.Profile != nil {
+		if err = saveRestaurantProfile(ctx, tx, restaurant.Id, req.Profile); err != nil {
+			return nil, err
+		}
+		restaurant.Profile.PriceLevel, restaurant.Profile.Capacity = req.Profile.PriceLevel, req.Profile.Capacity
+	}
+	if err = attachRestaurantTranslations(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create restaurant: %v", err)
+	}
 	return &pb.CreateRestaurantResponse{Restaurant: restaurant}, nil
 }
 
-func (r *ReservationRepo) ListRestaurants(req *pb.ListRestaurantsRequest) (*pb.ListRestaurantsResponse, error) {
+func (r *ReservationRepo) ListRestaurants(ctx context.Context, req *pb.ListRestaurantsRequest) (_ *pb.ListRestaurantsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListRestaurants", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		params = make(map[string]interface{})
 		args   []interface{}
@@ -63,12 +125,8 @@ func (r *ReservationRepo) ListRestaurants(req *pb.ListRestaurantsRequest) (*pb.L
 	)
 
 	query := `
-		SELECT 
-			id, 
-			name, 
-			address, 
-			phone_number, 
-			description 
+		SELECT
+			` + restaurantColumns + `
 		FROM 
 			Restaurants 
 		WHERE 
@@ -84,19 +142,47 @@ func (r *ReservationRepo) ListRestaurants(req *pb.ListRestaurantsRequest) (*pb.L
 		filter += " AND address = :address "
 	}
 
+	if cuisines := normalizeTags(req.Cuisines); len(cuisines) > 0 {
+		params["cuisines"] = pq.Array(cuisines)
+		filter += ` AND EXISTS (
+						SELECT 1 FROM RestaurantTagLinks l WHERE l.restaurant_id = Restaurants.id AND l.tag_code = ANY(:cuisines::text[])
+					) `
+	}
+	if amenities := normalizeTags(req.Amenities); len(amenities) > 0 {
+		params["amenities"] = pq.Array(amenities)
+		filter += ` AND (
+						SELECT COUNT(*) FROM RestaurantTagLinks l WHERE l.restaurant_id = Restaurants.id AND l.tag_code = ANY(:amenities::text[])
+					) = cardinality(:amenities::text[]) `
+	}
+	if req.MinPriceLevel > 0 {
+		params["min_price_level"] = req.MinPriceLevel
+		filter += " AND price_level >= :min_price_level "
+	}
+	if req.MaxPriceLevel > 0 {
+		params["max_price_level"] = req.MaxPriceLevel
+		filter += " AND price_level <= :max_price_level "
+	}
+	if req.MinCapacity > 0 {
+		params["min_capacity"] = req.MinCapacity
+		filter += " AND capacity >= :min_capacity "
+	}
+
+	// A stable order keeps pages from repeating or skipping restaurants.
+	filter += " ORDER BY name, id "
+
 	if req.Limit > 0 {
 		params["limit"] = req.Limit
-		filter += " AND limit = :limit"
+		filter += " LIMIT :limit "
 	}
 
 	if req.Offset > 0 {
 		params["offset"] = req.Offset
-		filter += " AND offset = :offset"
+		filter += " OFFSET :offset "
 	}
 	query += filter
 
 	query, args = ReplaceQueryParams(query, params)
-	rows, err := r.DB.Query(query, args...)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list restaurants: %v", err)
 	}
@@ -104,41 +190,59 @@ func (r *ReservationRepo) ListRestaurants(req *pb.ListRestaurantsRequest) (*pb.L
 
 	var restaurants []*pb.Restaurant
 	for rows.Next() {
-		restaurant := &pb.Restaurant{}
-		if err := rows.Scan(&restaurant.Id, &restaurant.Name, &restaurant.Address, &restaurant.PhoneNumber, &restaurant.Description); err != nil {
+		restaurant, err := scanRestaurant(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan restaurant: %v", err)
 		}
 		restaurants = append(restaurants, restaurant)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list restaurants: %v", err)
+	}
+	if err = attachRestaurantTranslations(ctx, r.DB, restaurants...); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, r.DB, restaurants...); err != nil {
+		return nil, err
+	}
 	return &pb.ListRestaurantsResponse{Restaurants: restaurants}, nil
 }
 
-func (r *ReservationRepo) GetRestaurant(req *pb.GetRestaurantRequest) (*pb.GetRestaurantResponse, error) {
+func (r *ReservationRepo) GetRestaurant(ctx context.Context, req *pb.GetRestaurantRequest) (_ *pb.GetRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.GetRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		SELECT 
-			id, 
-			name, 
-			address, 
-			phone_number, 
-			description 
+		SELECT
+			` + restaurantColumns + `
 		FROM 
 			Restaurants 
 		WHERE 
 			id = $1 AND deleted_at = 0;
 	`
-	restaurant := &pb.Restaurant{}
-	err := r.DB.QueryRow(query, req.Id).Scan(
-		&restaurant.Id, &restaurant.Name, &restaurant.Address, &restaurant.PhoneNumber, &restaurant.Description)
+	restaurant, err := scanRestaurant(r.DB.QueryRowContext(ctx, query, req.Id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("restaurant not found")
+			return nil, status.Error(codes.NotFound, "restaurant not found")
 		}
 		return nil, fmt.Errorf("failed to get restaurant: %v", err)
 	}
+	if err = attachRestaurantTranslations(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachOpeningHours(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
 	return &pb.GetRestaurantResponse{Restaurant: restaurant}, nil
 }
 
-func (r *ReservationRepo) UpdateRestaurant(req *pb.UpdateRestaurantRequest) (*pb.UpdateRestaurantResponse, error) {
+func (r *ReservationRepo) UpdateRestaurant(ctx context.Context, req *pb.UpdateRestaurantRequest) (_ *pb.UpdateRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpdateRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE 
 			Restaurants 
@@ -147,26 +251,62 @@ func (r *ReservationRepo) UpdateRestaurant(req *pb.UpdateRestaurantRequest) (*pb
 			address = $3, 
 			phone_number = $4, 
 			description = $5, 
+			latitude = CASE WHEN $6::float8 IS NOT NULL THEN $6 WHEN address IS DISTINCT FROM $3 THEN NULL ELSE latitude END,
+			longitude = CASE WHEN $7::float8 IS NOT NULL THEN $7 WHEN address IS DISTINCT FROM $3 THEN NULL ELSE longitude END,
+			timezone = COALESCE(NULLIF($8, ''), timezone),
 			updated_at = CURRENT_TIMESTAMP
 		WHERE 
 			id = $1 AND deleted_at = 0
-		RETURNING 
-			id, 
-			name, 
-			address, 
-			phone_number, 
-			description;
+		RETURNING
+			` + restaurantColumns + `
 	`
-	restaurant := &pb.Restaurant{}
-	err := r.DB.QueryRow(query, req.Id, req.Name, req.Address, req.PhoneNumber, req.Description).Scan(
-		&restaurant.Id, &restaurant.Name, &restaurant.Address, &restaurant.PhoneNumber, &restaurant.Description)
+	translations, err := normalizeTranslations(req.Translations)
+	if err != nil {
+		return nil, err
+	}
+	latitude, longitude, err := locationArgs(req.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update restaurant: %v", err)
 	}
+	defer tx.Rollback()
+
+	if req.Timezone != "" {
+		if err = checkTimezone(ctx, tx, req.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	restaurant, err := scanRestaurant(tx.QueryRowContext(ctx, query, req.Id, req.Name, req.Address, req.PhoneNumber, req.Description,
+		latitude, longitude, req.Timezone))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		return nil, fmt.Errorf("failed to update restaurant: %v", err)
+	}
+	if err = saveTranslations(ctx, tx, restaurantTranslations, restaurant.Id, translations); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantTranslations(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update restaurant: %v", err)
+	}
 	return &pb.UpdateRestaurantResponse{Restaurant: restaurant}, nil
 }
 
-func (r *ReservationRepo) DeleteRestaurant(req *pb.DeleteRestaurantRequest) (*pb.DeleteRestaurantResponse, error) {
+func (r *ReservationRepo) DeleteRestaurant(ctx context.Context, req *pb.DeleteRestaurantRequest) (_ *pb.DeleteRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.DeleteRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		UPDATE 
 			Restaurants 
@@ -176,13 +316,338 @@ func (r *ReservationRepo) DeleteRestaurant(req *pb.DeleteRestaurantRequest) (*pb
 			id = $1 AND deleted_at = 0
 	`
 
-	_, err := r.DB.Exec(query, req.Id)
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete restaurant: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete restaurant: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "restaurant not found")
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Restaurant closed"
+	}
+	cancelled, err := cancelUpcomingReservations(ctx, tx, req.Id, reason, req.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to delete restaurant: %v", err)
+	}
+	return &pb.DeleteRestaurantResponse{
+		Message:               "Restaurant deleted successfully",
+		CancelledReservations: cancelled,
+	}, nil
+
+}
+
+func (r *ReservationRepo) DeactivateRestaurant(ctx context.Context, req *pb.DeactivateRestaurantRequest) (_ *pb.DeactivateRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.DeactivateRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE 
+			Restaurants 
+		SET 
+			is_active = false, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			id = $1 AND deleted_at = 0 AND is_active
+	`
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate restaurant: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate restaurant: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, status.Error(codes.NotFound, "active restaurant not found")
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Restaurant temporarily unavailable"
+	}
+	cancelled, err := cancelUpcomingReservations(ctx, tx, req.Id, reason, req.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to deactivate restaurant: %v", err)
+	}
+	return &pb.DeactivateRestaurantResponse{
+		Message:               "Restaurant deactivated successfully",
+		CancelledReservations: cancelled,
+	}, nil
+}
+
+func (r *ReservationRepo) ActivateRestaurant(ctx context.Context, req *pb.ActivateRestaurantRequest) (_ *pb.ActivateRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ActivateRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE 
+			Restaurants 
+		SET 
+			is_active = true, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			id = $1 AND deleted_at = 0
+		RETURNING
+			` + restaurantColumns + `
+	`
+	restaurant, err := scanRestaurant(r.DB.QueryRowContext(ctx, query, req.Id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "restaurant not found")
+		}
+		return nil, fmt.Errorf("failed to activate restaurant: %v", err)
+	}
+	if err = attachRestaurantTranslations(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachOpeningHours(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	return &pb.ActivateRestaurantResponse{Restaurant: restaurant}, nil
+}
+
+// restaurantColumns lists the Restaurants columns read by scanRestaurant.
+const restaurantColumns = `id,
+			name,
+			address,
+			phone_number,
+			description,
+			is_active,
+			currency,
+			latitude,
+			longitude,
+			price_level,
+			capacity,
+			timezone`
+
+// scanRestaurant scans restaurantColumns followed by extra. The lists of the
+// profile are left to attachRestaurantProfiles.
+func scanRestaurant(row rowScanner, extra ...interface{}) (*pb.Restaurant, error) {
+	restaurant := &pb.Restaurant{}
+	var latitude, longitude sql.NullFloat64
+	var priceLevel, capacity sql.NullInt32
+	dest := append([]interface{}{&restaurant.Id, &restaurant.Name, &restaurant.Address, &restaurant.PhoneNumber,
+		&restaurant.Description, &restaurant.IsActive, &restaurant.Currency, &latitude, &longitude, &priceLevel,
+		&capacity, &restaurant.Timezone}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	restaurant.Profile = &pb.RestaurantProfile{PriceLevel: priceLevel.Int32, Capacity: capacity.Int32}
+	if latitude.Valid && longitude.Valid {
+		restaurant.Location = &pb.GeoPoint{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
+	return restaurant, nil
+}
+
+// locationArgs returns the query arguments of a restaurant's location, NULL
+// when it is not given.
+func locationArgs(location *pb.GeoPoint) (sql.NullFloat64, sql.NullFloat64, error) {
+	if location == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}, nil
+	}
+	if !(geo.Point{Lat: location.Latitude, Lng: location.Longitude}).Valid() {
+		return sql.NullFloat64{}, sql.NullFloat64{}, status.Error(codes.InvalidArgument, "latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	return sql.NullFloat64{Float64: location.Latitude, Valid: true}, sql.NullFloat64{Float64: location.Longitude, Valid: true}, nil
+}
+
+// checkTimezone fails unless Postgres knows the time zone, so local times
+// can be computed for it in queries.
+func checkTimezone(ctx context.Context, q queryer, timezone string) error {
+	var known bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = $1)`, timezone).Scan(&known)
+	if err != nil {
+		return fmt.Errorf("failed to check time zone: %v", err)
+	}
+	if !known {
+		return status.Errorf(codes.InvalidArgument, "unknown timezone %q, use an IANA name such as Asia/Tashkent", timezone)
+	}
+	return nil
+}
+
+// cancelUpcomingReservations cancels every future pending or confirmed
+// reservation of a restaurant that is going away and queues a
+// ReservationCancelled event for each guest. Paid reservations are refused
+// unless force is set; the service refunds them before forcing.
+func cancelUpcomingReservations(ctx context.Context, tx *sql.Tx, restaurantId, reason string, force bool) (int32, error) {
+	if !force {
+		var paid int
+		err := tx.QueryRowContext(ctx, `
+			SELECT 
+				COUNT(*) 
+			FROM 
+				reservations 
+			WHERE 
+				restaurant_id = $1 
+				AND deleted_at = 0 
+				AND status IN ('Pending', 'Confirmed') 
+				AND reservation_time > CURRENT_TIMESTAMP 
+				AND (payment_id IS NOT NULL OR deposit_payment_id IS NOT NULL)
+		`, restaurantId).Scan(&paid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check paid reservations: %v", err)
+		}
+		if paid > 0 {
+			return 0, status.Errorf(codes.FailedPrecondition,
+				"restaurant has %d paid upcoming reservations, use force to cancel them", paid)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE 
+			reservations 
+		SET 
+			status = 'Cancelled', 
+			cancellation_reason = $2, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			restaurant_id = $1 
+			AND deleted_at = 0 
+			AND status IN ('Pending', 'Confirmed') 
+			AND reservation_time > CURRENT_TIMESTAMP
+		RETURNING 
+			id, 
+			user_id, 
+			reservation_time
+	`, restaurantId, reason)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel reservations: %v", err)
+	}
+
+	var events []ReservationCancelledEvent
+	for rows.Next() {
+		event := ReservationCancelledEvent{RestaurantId: restaurantId, Reason: reason}
+		if err := rows.Scan(&event.ReservationId, &event.UserId, &event.ReservationTime); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan cancelled reservation: %v", err)
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to cancel reservations: %v", err)
+	}
+
+	for _, event := range events {
+		if err := releaseMenuStock(ctx, tx, event.ReservationId); err != nil {
+			return 0, err
+		}
+		if err := addOutboxEvent(ctx, tx, "reservation", event.ReservationId, EventReservationCancelled, event); err != nil {
+			return 0, err
+		}
+		if err := queueNotification(ctx, tx, event.ReservationId, NotificationCancellation); err != nil {
+			return 0, err
+		}
+	}
+	return int32(len(events)), nil
+}
+
+func (r *ReservationRepo) RestoreRestaurant(ctx context.Context, req *pb.RestoreRestaurantRequest) (_ *pb.RestoreRestaurantResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.RestoreRestaurant", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE 
+			Restaurants 
+		SET 
+			deleted_at = 0, 
+			updated_at = CURRENT_TIMESTAMP
+		WHERE 
+			id = $1 AND deleted_at <> 0
+		RETURNING
+			` + restaurantColumns + `
+	`
+	restaurant, err := scanRestaurant(r.DB.QueryRowContext(ctx, query, req.Id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("restaurant not found")
+			return nil, status.Error(codes.NotFound, "deleted restaurant not found")
 		}
-		return nil, fmt.Errorf("failed to delete restaurant: %v", err)
+		return nil, fmt.Errorf("failed to restore restaurant: %v", err)
+	}
+	if err = attachRestaurantTranslations(ctx, r.DB, restaurant); err != nil {
+		return nil, err
 	}
-	return &pb.DeleteRestaurantResponse{Message: "Restaurant deleted successfully"}, nil
+	if err = attachOpeningHours(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, r.DB, restaurant); err != nil {
+		return nil, err
+	}
+	return &pb.RestoreRestaurantResponse{Restaurant: restaurant}, nil
+}
 
+// UpcomingPayment is a bill or deposit payment of an upcoming reservation.
+type UpcomingPayment struct {
+	ReservationId string
+	PaymentId     string
+	Currency      string
+}
+
+// UpcomingPayments lists the payments of a restaurant's future pending or
+// confirmed reservations, the ones a forced delete or deactivation cancels.
+func (r *ReservationRepo) UpcomingPayments(ctx context.Context, restaurantId string) (_ []UpcomingPayment, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.UpcomingPayments", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			r.id,
+			COALESCE(r.payment_id::text, ''),
+			COALESCE(r.deposit_payment_id::text, ''),
+			rs.currency
+		FROM
+			reservations r
+			JOIN Restaurants rs ON rs.id = r.restaurant_id
+		WHERE
+			r.restaurant_id = $1
+			AND rs.deleted_at = 0
+			AND r.deleted_at = 0
+			AND r.status IN ('Pending', 'Confirmed')
+			AND r.reservation_time > CURRENT_TIMESTAMP
+			AND (r.payment_id IS NOT NULL OR r.deposit_payment_id IS NOT NULL)
+		ORDER BY
+			r.reservation_time, r.id
+	`, restaurantId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upcoming payments: %v", err)
+	}
+	defer rows.Close()
+
+	var payments []UpcomingPayment
+	for rows.Next() {
+		var reservationId, billPayment, deposit, currency string
+		if err := rows.Scan(&reservationId, &billPayment, &deposit, &currency); err != nil {
+			return nil, fmt.Errorf("failed to scan upcoming payment: %v", err)
+		}
+		for _, paymentId := range paymentIds(billPayment, deposit) {
+			payments = append(payments, UpcomingPayment{ReservationId: reservationId, PaymentId: paymentId, Currency: currency})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list upcoming payments: %v", err)
+	}
+	return payments, nil
 }
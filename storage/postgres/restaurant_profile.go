@@ -0,0 +1,398 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Restaurant tag kinds.
+const (
+	TagCuisine = "cuisine"
+	TagAmenity = "amenity"
+)
+
+const (
+	maxPriceLevel        = 4
+	maxRestaurantPhotos  = 20
+	maxPhotoCaptionRunes = 255
+)
+
+var socialNetwork = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// validLink reports whether link is an absolute http or https URL.
+func validLink(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// normalizeRestaurantProfile checks the fields of a profile that need no
+// lookups, and lowercases its social networks. A nil profile is empty.
+func normalizeRestaurantProfile(profile *pb.RestaurantProfile) (*pb.RestaurantProfile, error) {
+	if profile == nil {
+		return &pb.RestaurantProfile{}, nil
+	}
+	if profile.PriceLevel < 0 || profile.PriceLevel > maxPriceLevel {
+		return nil, status.Errorf(codes.InvalidArgument, "price_level must be between 1 and %d, or 0 if unknown", maxPriceLevel)
+	}
+	if profile.Capacity < 0 {
+		return nil, status.Error(codes.InvalidArgument, "capacity must not be negative")
+	}
+	if len(profile.Photos) > maxRestaurantPhotos {
+		return nil, status.Errorf(codes.InvalidArgument, "a restaurant has at most %d photos", maxRestaurantPhotos)
+	}
+	for _, photo := range profile.Photos {
+		if !validLink(photo.Url) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid photo url %q, use an http or https URL", photo.Url)
+		}
+		if len([]rune(photo.Caption)) > maxPhotoCaptionRunes {
+			return nil, status.Errorf(codes.InvalidArgument, "photo caption must be at most %d characters", maxPhotoCaptionRunes)
+		}
+	}
+
+	links := make([]*pb.SocialLink, len(profile.SocialLinks))
+	seen := make(map[string]bool, len(profile.SocialLinks))
+	for i, link := range profile.SocialLinks {
+		network := strings.ToLower(strings.TrimSpace(link.Network))
+		if !socialNetwork.MatchString(network) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid social network %q", link.Network)
+		}
+		if seen[network] {
+			return nil, status.Errorf(codes.InvalidArgument, "social network %q is linked twice", network)
+		}
+		seen[network] = true
+		if !validLink(link.Url) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s url %q, use an http or https URL", network, link.Url)
+		}
+		links[i] = &pb.SocialLink{Network: network, Url: link.Url}
+	}
+
+	return &pb.RestaurantProfile{
+		Cuisines:    profile.Cuisines,
+		PriceLevel:  profile.PriceLevel,
+		Capacity:    profile.Capacity,
+		Amenities:   profile.Amenities,
+		Photos:      profile.Photos,
+		SocialLinks: links,
+	}, nil
+}
+
+// checkRestaurantTags normalizes tag codes and rejects unknown ones, or ones
+// of another kind.
+func checkRestaurantTags(ctx context.Context, q queryer, kind string, tags []string) ([]string, error) {
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			code
+		FROM
+			RestaurantTags
+		WHERE
+			code = ANY($1) AND kind = $2
+	`, pq.Array(tags), kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check restaurant tags: %v", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]bool, len(tags))
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan restaurant tag: %v", err)
+		}
+		known[code] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check restaurant tags: %v", err)
+	}
+	for _, tag := range tags {
+		if !known[tag] {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown %s %q", kind, tag)
+		}
+	}
+	return tags, nil
+}
+
+// saveRestaurantProfile replaces the profile of a restaurant.
+func saveRestaurantProfile(ctx context.Context, tx *sql.Tx, restaurantId string, profile *pb.RestaurantProfile) error {
+	profile, err := normalizeRestaurantProfile(profile)
+	if err != nil {
+		return err
+	}
+	cuisines, err := checkRestaurantTags(ctx, tx, TagCuisine, profile.Cuisines)
+	if err != nil {
+		return err
+	}
+	amenities, err := checkRestaurantTags(ctx, tx, TagAmenity, profile.Amenities)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE
+			Restaurants
+		SET
+			price_level = NULLIF($2, 0),
+			capacity = NULLIF($3, 0),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1 AND deleted_at = 0
+	`, restaurantId, profile.PriceLevel, profile.Capacity)
+	if err != nil {
+		return fmt.Errorf("failed to save restaurant profile: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return status.Error(codes.NotFound, "restaurant not found")
+	}
+
+	for _, table := range []string{"RestaurantTagLinks", "RestaurantPhotos", "RestaurantSocialLinks"} {
+		_, err = tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE restaurant_id = $1`, restaurantId)
+		if err != nil {
+			return fmt.Errorf("failed to save restaurant profile: %v", err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO RestaurantTagLinks (
+			restaurant_id,
+			tag_code
+		)
+		SELECT
+			$1,
+			unnest($2::text[])
+	`, restaurantId, pq.Array(slices.Concat(cuisines, amenities)))
+	if err != nil {
+		return fmt.Errorf("failed to save restaurant tags: %v", err)
+	}
+	for i, photo := range profile.Photos {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO RestaurantPhotos (
+				restaurant_id,
+				position,
+				url,
+				caption
+			)
+			VALUES (
+				$1,
+				$2,
+				$3,
+				$4
+			)
+		`, restaurantId, i, photo.Url, photo.Caption)
+		if err != nil {
+			return fmt.Errorf("failed to save restaurant photos: %v", err)
+		}
+	}
+	for _, link := range profile.SocialLinks {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO RestaurantSocialLinks (
+				restaurant_id,
+				network,
+				url
+			)
+			VALUES (
+				$1,
+				$2,
+				$3
+			)
+		`, restaurantId, link.Network, link.Url)
+		if err != nil {
+			return fmt.Errorf("failed to save restaurant social links: %v", err)
+		}
+	}
+	return nil
+}
+
+// SetRestaurantProfile replaces the profile of a restaurant.
+func (r *ReservationRepo) SetRestaurantProfile(ctx context.Context, req *pb.SetRestaurantProfileRequest) (_ *pb.SetRestaurantProfileResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.SetRestaurantProfile", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set restaurant profile: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err = saveRestaurantProfile(ctx, tx, req.RestaurantId, req.Profile); err != nil {
+		return nil, err
+	}
+	restaurant, err := scanRestaurant(tx.QueryRowContext(ctx, `
+		SELECT
+			`+restaurantColumns+`
+		FROM
+			Restaurants
+		WHERE
+			id = $1
+	`, req.RestaurantId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set restaurant profile: %v", err)
+	}
+	if err = attachRestaurantTranslations(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachOpeningHours(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = attachRestaurantProfiles(ctx, tx, restaurant); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set restaurant profile: %v", err)
+	}
+	return &pb.SetRestaurantProfileResponse{Restaurant: restaurant}, nil
+}
+
+func (r *ReservationRepo) ListRestaurantTags(ctx context.Context, req *pb.ListRestaurantTagsRequest) (_ *pb.ListRestaurantTagsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.ListRestaurantTags", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT
+			code,
+			kind,
+			name
+		FROM
+			RestaurantTags
+		WHERE
+			$1 = '' OR kind = $1
+		ORDER BY
+			kind, name
+	`, req.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list restaurant tags: %v", err)
+	}
+	defer rows.Close()
+
+	var tags []*pb.RestaurantTag
+	for rows.Next() {
+		tag := &pb.RestaurantTag{}
+		if err := rows.Scan(&tag.Code, &tag.Kind, &tag.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan restaurant tag: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list restaurant tags: %v", err)
+	}
+	return &pb.ListRestaurantTagsResponse{Tags: tags}, nil
+}
+
+// attachRestaurantProfiles fills in the cuisines, amenities, photos and
+// social links of restaurants scanned by scanRestaurant.
+func attachRestaurantProfiles(ctx context.Context, q queryer, restaurants ...*pb.Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+	ids := make([]string, len(restaurants))
+	byId := make(map[string]*pb.RestaurantProfile, len(restaurants))
+	for i, restaurant := range restaurants {
+		ids[i] = restaurant.Id
+		byId[restaurant.Id] = restaurant.Profile
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			l.restaurant_id,
+			t.kind,
+			t.code
+		FROM
+			RestaurantTagLinks l
+			JOIN RestaurantTags t ON t.code = l.tag_code
+		WHERE
+			l.restaurant_id = ANY($1::uuid[])
+		ORDER BY
+			t.code
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get restaurant tags: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var restaurantId, kind, code string
+		if err := rows.Scan(&restaurantId, &kind, &code); err != nil {
+			return fmt.Errorf("failed to scan restaurant tag: %v", err)
+		}
+		profile := byId[restaurantId]
+		if kind == TagCuisine {
+			profile.Cuisines = append(profile.Cuisines, code)
+		} else {
+			profile.Amenities = append(profile.Amenities, code)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get restaurant tags: %v", err)
+	}
+
+	rows, err = q.QueryContext(ctx, `
+		SELECT
+			restaurant_id,
+			url,
+			caption
+		FROM
+			RestaurantPhotos
+		WHERE
+			restaurant_id = ANY($1::uuid[])
+		ORDER BY
+			restaurant_id, position
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get restaurant photos: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var restaurantId string
+		photo := &pb.RestaurantPhoto{}
+		if err := rows.Scan(&restaurantId, &photo.Url, &photo.Caption); err != nil {
+			return fmt.Errorf("failed to scan restaurant photo: %v", err)
+		}
+		byId[restaurantId].Photos = append(byId[restaurantId].Photos, photo)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get restaurant photos: %v", err)
+	}
+
+	rows, err = q.QueryContext(ctx, `
+		SELECT
+			restaurant_id,
+			network,
+			url
+		FROM
+			RestaurantSocialLinks
+		WHERE
+			restaurant_id = ANY($1::uuid[])
+		ORDER BY
+			restaurant_id, network
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get restaurant social links: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var restaurantId string
+		link := &pb.SocialLink{}
+		if err := rows.Scan(&restaurantId, &link.Network, &link.Url); err != nil {
+			return fmt.Errorf("failed to scan restaurant social link: %v", err)
+		}
+		byId[restaurantId].SocialLinks = append(byId[restaurantId].SocialLinks, link)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get restaurant social links: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeRestaurantProfile(t *testing.T) {
+	profile, err := normalizeRestaurantProfile(&pb.RestaurantProfile{
+		PriceLevel:  2,
+		Capacity:    40,
+		Photos:      []*pb.RestaurantPhoto{{Url: "https://example.com/hall.jpg", Caption: "Hall"}},
+		SocialLinks: []*pb.SocialLink{{Network: " Instagram", Url: "https://instagram.com/plov"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "instagram", profile.SocialLinks[0].Network)
+
+	profile, err = normalizeRestaurantProfile(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, profile.Photos)
+
+	for _, profile := range []*pb.RestaurantProfile{
+		{PriceLevel: 5},
+		{Capacity: -1},
+		{Photos: []*pb.RestaurantPhoto{{Url: "hall.jpg"}}},
+		{Photos: []*pb.RestaurantPhoto{{Url: "ftp://example.com/hall.jpg"}}},
+		{SocialLinks: []*pb.SocialLink{{Network: "face book", Url: "https://facebook.com/plov"}}},
+		{SocialLinks: []*pb.SocialLink{{Network: "website", Url: "https://plov.uz"}, {Network: "Website", Url: "https://plov.com"}}},
+	} {
+		_, err := normalizeRestaurantProfile(profile)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%v", profile)
+	}
+}
+
+func TestRestaurantProfile(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	ctx := context.Background()
+
+	_, err = repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{
+		Name:    "Profile test unknown",
+		Profile: &pb.RestaurantProfile{Cuisines: []string{"wifi"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{
+		Name:    "Profile test",
+		Address: "Profile street",
+		Profile: &pb.RestaurantProfile{
+			Cuisines:   []string{"Uzbek", "turkish"},
+			PriceLevel: 2,
+			Capacity:   80,
+			Amenities:  []string{"wifi", "parking"},
+			Photos: []*pb.RestaurantPhoto{
+				{Url: "https://example.com/front.jpg", Caption: "Front"},
+				{Url: "https://example.com/hall.jpg"},
+			},
+			SocialLinks: []*pb.SocialLink{{Network: "instagram", Url: "https://instagram.com/profile_test"}},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	id := created.Restaurant.Id
+	defer repo.DeleteRestaurant(ctx, &pb.DeleteRestaurantRequest{Id: id, Force: true})
+	assert.Equal(t, []string{"turkish", "uzbek"}, created.Restaurant.Profile.Cuisines)
+	assert.Equal(t, []string{"parking", "wifi"}, created.Restaurant.Profile.Amenities)
+
+	got, err := repo.GetRestaurant(ctx, &pb.GetRestaurantRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Restaurant.Profile.PriceLevel)
+	assert.Equal(t, int32(80), got.Restaurant.Profile.Capacity)
+	if assert.Len(t, got.Restaurant.Profile.Photos, 2) {
+		assert.Equal(t, "Front", got.Restaurant.Profile.Photos[0].Caption)
+	}
+	assert.Len(t, got.Restaurant.Profile.SocialLinks, 1)
+
+	list, err := repo.ListRestaurants(ctx, &pb.ListRestaurantsRequest{
+		Address:       "Profile street",
+		Cuisines:      []string{"uzbek", "italian"},
+		Amenities:     []string{"parking", "wifi"},
+		MinPriceLevel: 1,
+		MaxPriceLevel: 2,
+		MinCapacity:   50,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Restaurants, 1) {
+		assert.Equal(t, id, list.Restaurants[0].Id)
+	}
+	for _, req := range []*pb.ListRestaurantsRequest{
+		{Address: "Profile street", Cuisines: []string{"italian"}},
+		{Address: "Profile street", Amenities: []string{"wifi", "kids"}},
+		{Address: "Profile street", MinPriceLevel: 3},
+		{Address: "Profile street", MinCapacity: 100},
+	} {
+		list, err = repo.ListRestaurants(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, list.Restaurants, "%v", req)
+	}
+
+	set, err := repo.SetRestaurantProfile(ctx, &pb.SetRestaurantProfileRequest{
+		RestaurantId: id,
+		Profile:      &pb.RestaurantProfile{Amenities: []string{"kids"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kids"}, set.Restaurant.Profile.Amenities)
+	assert.Empty(t, set.Restaurant.Profile.Cuisines)
+	assert.Empty(t, set.Restaurant.Profile.Photos)
+	assert.Zero(t, set.Restaurant.Profile.PriceLevel)
+
+	_, err = repo.SetRestaurantProfile(ctx, &pb.SetRestaurantProfileRequest{RestaurantId: "00000000-0000-0000-0000-000000000000"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	tags, err := repo.ListRestaurantTags(ctx, &pb.ListRestaurantTagsRequest{Kind: TagAmenity})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tags.Tags)
+	for _, tag := range tags.Tags {
+		assert.Equal(t, TagAmenity, tag.Kind)
+	}
+}
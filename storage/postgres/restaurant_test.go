@@ -1,20 +1,22 @@
 package postgres
 
 import (
+	"context"
 	pb "reservation-service/generated/reservation_service"
-	"reservation-service/storage/redis"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCreateRestaurant(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("Failed database connection")
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	restaurantRepo := NewRRestaurantRepo(db, r)
 	Newrestaurant := pb.CreateRestaurantRequest{
 		Name:        "S",
@@ -22,7 +24,7 @@ func TestCreateRestaurant(t *testing.T) {
 		PhoneNumber: "991234567",
 		Description: "jwlejf",
 	}
-	restaurant, err := restaurantRepo.CreateRestaurant(&Newrestaurant)
+	restaurant, err := restaurantRepo.CreateRestaurant(context.Background(), &Newrestaurant)
 	if err != nil {
 		t.Errorf("Failed Created Restaurant : %v", err)
 		return
@@ -39,18 +41,18 @@ func TestCreateRestaurant(t *testing.T) {
 }
 
 func TestListRestaurants(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("Failed database connection")
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	restaurantRepo := NewRRestaurantRepo(db, r)
 	reqMenu := pb.ListRestaurantsRequest{
 		Name:    "S",
 		Address: "Chilonzor",
 	}
-	listRestaurant, err := restaurantRepo.ListRestaurants(&reqMenu)
+	listRestaurant, err := restaurantRepo.ListRestaurants(context.Background(), &reqMenu)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -64,17 +66,17 @@ func TestListRestaurants(t *testing.T) {
 }
 
 func TestGetRestaurant(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("Failed database connection")
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	restaurantRepo := NewRRestaurantRepo(db, r)
 	id := pb.GetRestaurantRequest{
 		Id: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
 	}
-	restaurant, err := restaurantRepo.GetRestaurant(&id)
+	restaurant, err := restaurantRepo.GetRestaurant(context.Background(), &id)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -85,12 +87,12 @@ func TestGetRestaurant(t *testing.T) {
 }
 
 func TestUpdateRestaurant(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("Failed database connection")
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	restaurantRepo := NewRRestaurantRepo(db, r)
 	restaurant := pb.UpdateRestaurantRequest{
 		Id:          "207815e3-0b01-46bb-952c-2ea8b8d728e5",
@@ -99,7 +101,7 @@ func TestUpdateRestaurant(t *testing.T) {
 		PhoneNumber: "991234546",
 		Description: "",
 	}
-	updateRes, err := restaurantRepo.UpdateRestaurant(&restaurant)
+	updateRes, err := restaurantRepo.UpdateRestaurant(context.Background(), &restaurant)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -116,17 +118,17 @@ func TestUpdateRestaurant(t *testing.T) {
 }
 
 func TestDeleteRestaurant(t *testing.T) {
-	db, err := ConnectDB()
+	db, err := connectTestDB()
 	if err != nil {
 		t.Errorf("Failed database connection")
 		return
 	}
-	r := redis.ConnectR()
+	r := connectTestRedis()
 	restaurantRepo := NewRRestaurantRepo(db, r)
 	id := pb.DeleteRestaurantRequest{
 		Id: "207815e3-0b01-46bb-952c-2ea8b8d728e5",
 	}
-	res, err := restaurantRepo.DeleteRestaurant(&id)
+	res, err := restaurantRepo.DeleteRestaurant(context.Background(), &id)
 	if err != nil {
 		t.Errorf("ERROR : %v", err)
 		return
@@ -135,3 +137,98 @@ func TestDeleteRestaurant(t *testing.T) {
 
 	assert.NotEmpty(t, res)
 }
+
+func TestRestoreRestaurant(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("Failed database connection")
+		return
+	}
+	r := connectTestRedis()
+	restaurantRepo := NewRRestaurantRepo(db, r)
+	id := pb.RestoreRestaurantRequest{
+		Id: "207815e3-0b01-46bb-952c-2ea8b8d728e5",
+	}
+	res, err := restaurantRepo.RestoreRestaurant(context.Background(), &id)
+	if err != nil {
+		t.Errorf("ERROR : %v", err)
+		return
+	}
+
+	assert.Equal(t, id.Id, res.Restaurant.Id)
+}
+
+func TestDeactivateRestaurant(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("Failed database connection")
+		return
+	}
+	r := connectTestRedis()
+	restaurantRepo := NewRRestaurantRepo(db, r)
+	restaurant, err := restaurantRepo.CreateRestaurant(context.Background(), &pb.CreateRestaurantRequest{Name: "Deactivate test", Address: "Mirobod"})
+	if err != nil {
+		t.Errorf("ERROR : %v", err)
+		return
+	}
+	upcoming, err := restaurantRepo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurant.Restaurant.Id,
+		ReservationTime: "2030-07-01 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	paid, err := restaurantRepo.CreateReservation(context.Background(), &pb.CreateReservationRequest{
+		UserId:          "67188541-6344-42bd-8be2-a14c558d30aa",
+		RestaurantId:    restaurant.Restaurant.Id,
+		ReservationTime: "2030-07-02 19:00:00",
+		Status:          "Confirmed",
+	})
+	assert.NoError(t, err)
+	paymentId := "5b0c6a2e-9d1f-4e3a-8c7b-2f4e6d8a0c1e"
+	err = restaurantRepo.SetReservationPayment(context.Background(), paid.Reservation.Id, paymentId, &pb.Money{}, "card")
+	assert.NoError(t, err)
+
+	req := pb.DeactivateRestaurantRequest{
+		Id:     restaurant.Restaurant.Id,
+		Reason: "Renovation",
+	}
+	_, err = restaurantRepo.DeactivateRestaurant(context.Background(), &req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	payments, err := restaurantRepo.UpcomingPayments(context.Background(), restaurant.Restaurant.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []UpcomingPayment{{ReservationId: paid.Reservation.Id, PaymentId: paymentId, Currency: "USD"}}, payments)
+
+	req.Force = true
+	res, err := restaurantRepo.DeactivateRestaurant(context.Background(), &req)
+	if err != nil {
+		t.Errorf("ERROR : %v", err)
+		return
+	}
+	assert.Equal(t, int32(2), res.CancelledReservations)
+	got, err := restaurantRepo.GetReservation(context.Background(), &pb.GetReservationRequest{Id: upcoming.Reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, "Cancelled", got.Reservation.Status)
+	assert.Equal(t, "Renovation", got.Reservation.CancellationReason)
+}
+
+func TestActivateRestaurant(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("Failed database connection")
+		return
+	}
+	r := connectTestRedis()
+	restaurantRepo := NewRRestaurantRepo(db, r)
+	id := pb.ActivateRestaurantRequest{
+		Id: "6751a219-6c1c-4676-b2fb-34dac8bfe41a",
+	}
+	res, err := restaurantRepo.ActivateRestaurant(context.Background(), &id)
+	if err != nil {
+		t.Errorf("ERROR : %v", err)
+		return
+	}
+
+	assert.True(t, res.Restaurant.IsActive)
+}
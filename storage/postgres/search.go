@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Search result types.
+const (
+	SearchRestaurant = "restaurant"
+	SearchMenuItem   = "menu_item"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// searchTerms splits a search query into lower-case words, dropping
+// punctuation and anything else that is not a letter or digit.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// prefixTSQuery builds a tsquery matching documents that contain every term,
+// or a word starting with it, so that "pizz nap" finds "Pizza Napoli".
+func prefixTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// escapeHTML returns SQL that escapes the HTML special characters of the text
+// expr evaluates to, so that highlights carry no markup but the <mark> tags.
+func escapeHTML(expr string) string {
+	return "replace(replace(replace(replace(replace(" + expr +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// Search finds active restaurants and their menu items whose names,
+// descriptions or addresses, untranslated or in any translation, contain the
+// words of the query, or whose names are spelled almost like it, ranked by
+// full-text relevance plus name similarity. Each result is shown in the text
+// that matched best.
+func (r *ReservationRepo) Search(ctx context.Context, req *pb.SearchRequest) (_ *pb.SearchResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReservationRepo.Search", semconv.DBSystemPostgreSQL)
+	defer func() { tracing.End(span, err) }()
+
+	terms := searchTerms(req.Query)
+	if len(terms) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query must contain a word")
+	}
+	if req.Type != "" && req.Type != SearchRestaurant && req.Type != SearchMenuItem {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid type %q, use %s or %s", req.Type, SearchRestaurant, SearchMenuItem)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	rows, err := r.DB.QueryContext(ctx, `
+		WITH q AS (
+			SELECT to_tsquery('simple', $1) AS query
+		),
+		docs AS (
+			SELECT
+				'restaurant' AS type,
+				r.id,
+				r.id AS restaurant_id,
+				r.name AS restaurant_name,
+				'' AS locale,
+				r.name,
+				concat_ws(' - ', NULLIF(r.description, ''), r.address) AS body,
+				r.search_vector
+			FROM
+				Restaurants r
+			WHERE
+				r.deleted_at = 0 AND r.is_active
+				AND $3 IN ('', 'restaurant')
+				AND ($4 = '' OR r.id::text = $4)
+			UNION ALL
+			SELECT
+				'restaurant',
+				r.id,
+				r.id,
+				r.name,
+				t.locale,
+				COALESCE(NULLIF(t.name, ''), r.name),
+				concat_ws(' - ', COALESCE(NULLIF(t.description, ''), NULLIF(r.description, '')), r.address),
+				t.search_vector
+			FROM
+				RestaurantTranslations t
+				JOIN Restaurants r ON r.id = t.restaurant_id
+			WHERE
+				r.deleted_at = 0 AND r.is_active
+				AND $3 IN ('', 'restaurant')
+				AND ($4 = '' OR r.id::text = $4)
+			UNION ALL
+			SELECT
+				'menu_item',
+				m.id,
+				m.restaurant_id,
+				r.name,
+				'',
+				m.name,
+				COALESCE(m.description, ''),
+				m.search_vector
+			FROM
+				Menu m
+				JOIN Restaurants r ON r.id = m.restaurant_id
+			WHERE
+				m.deleted_at = 0 AND r.deleted_at = 0 AND r.is_active
+				AND $3 IN ('', 'menu_item')
+				AND ($4 = '' OR m.restaurant_id::text = $4)
+			UNION ALL
+			SELECT
+				'menu_item',
+				m.id,
+				m.restaurant_id,
+				r.name,
+				t.locale,
+				COALESCE(NULLIF(t.name, ''), m.name),
+				COALESCE(NULLIF(t.description, ''), m.description, ''),
+				t.search_vector
+			FROM
+				MenuItemTranslations t
+				JOIN Menu m ON m.id = t.menu_item_id
+				JOIN Restaurants r ON r.id = m.restaurant_id
+			WHERE
+				m.deleted_at = 0 AND r.deleted_at = 0 AND r.is_active
+				AND $3 IN ('', 'menu_item')
+				AND ($4 = '' OR m.restaurant_id::text = $4)
+		),
+		-- The best matching text of each restaurant and menu item
+		results AS (
+			SELECT DISTINCT ON (docs.type, docs.id)
+				docs.type,
+				docs.id,
+				docs.restaurant_id,
+				docs.restaurant_name,
+				docs.locale,
+				docs.name,
+				docs.body,
+				ts_rank(docs.search_vector, q.query) + word_similarity($2, docs.name) AS rank
+			FROM
+				docs, q
+			WHERE
+				docs.search_vector @@ q.query OR $2 <% docs.name
+			ORDER BY
+				docs.type, docs.id, rank DESC, docs.locale
+		)
+		SELECT
+			results.type,
+			results.id,
+			results.restaurant_id,
+			results.restaurant_name,
+			results.locale,
+			results.name,
+			ts_headline('simple', `+escapeHTML("results.name")+`, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline('simple', `+escapeHTML("results.body")+`, q.query, 'StartSel=<mark>, StopSel=</mark>, MinWords=5, MaxWords=20'),
+			results.rank
+		FROM
+			results, q
+		ORDER BY
+			results.rank DESC, results.name, results.id
+		LIMIT $5 OFFSET $6
+	`, prefixTSQuery(terms), strings.Join(terms, " "), req.Type, req.RestaurantId, limit, max(req.Offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %v", err)
+	}
+	defer rows.Close()
+
+	res := &pb.SearchResponse{}
+	for rows.Next() {
+		result := &pb.SearchResult{}
+		err = rows.Scan(&result.Type, &result.Id, &result.RestaurantId, &result.RestaurantName, &result.Locale, &result.Name,
+			&result.NameHighlight, &result.Snippet, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %v", err)
+		}
+		res.Results = append(res.Results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search: %v", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrefixTSQuery(t *testing.T) {
+	terms := searchTerms("  Pizza, NAPOLI!  ")
+	assert.Equal(t, []string{"pizza", "napoli"}, terms)
+	assert.Equal(t, "pizza:* & napoli:*", prefixTSQuery(terms))
+	// Split like the Postgres parser splits "o'zbek", so the prefixes still match.
+	assert.Equal(t, []string{"o", "zbek", "oshi"}, searchTerms("o'zbek & oshi"))
+	assert.Empty(t, searchTerms(" !:*& "))
+}
+
+func TestSearch(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	ctx := context.Background()
+
+	created, err := repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{
+		Name:        "Search test Pizza Napoli",
+		Address:     "Amir Temur 1",
+		Description: "Wood fired pizza",
+	})
+	assert.NoError(t, err)
+	restaurantId := created.Restaurant.Id
+	defer repo.DeleteRestaurant(ctx, &pb.DeleteRestaurantRequest{Id: restaurantId, Force: true})
+
+	_, err = repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Margherita",
+		Description:  "Tomato, mozzarella and basil",
+		Price:        &pb.Money{MinorUnits: 900},
+		Translations: []*pb.Translation{{Locale: "uz", Name: "Pomidorli pitsa"}},
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Fish & Chips <large>",
+		Price:        &pb.Money{MinorUnits: 1200},
+	})
+	assert.NoError(t, err)
+
+	res, err := repo.Search(ctx, &pb.SearchRequest{Query: "pizza", RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, res.Results) {
+		assert.Equal(t, SearchRestaurant, res.Results[0].Type)
+		assert.Contains(t, res.Results[0].NameHighlight, "<mark>Pizza</mark>")
+	}
+
+	res, err = repo.Search(ctx, &pb.SearchRequest{Query: "mozz", RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Results, 1) {
+		assert.Equal(t, SearchMenuItem, res.Results[0].Type)
+		assert.Equal(t, "Search test Pizza Napoli", res.Results[0].RestaurantName)
+		assert.Contains(t, res.Results[0].Snippet, "<mark>mozzarella</mark>")
+	}
+
+	res, err = repo.Search(ctx, &pb.SearchRequest{Query: "margarita", RestaurantId: restaurantId, Type: SearchMenuItem})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Results, 1) {
+		assert.Equal(t, "Margherita", res.Results[0].Name)
+	}
+
+	res, err = repo.Search(ctx, &pb.SearchRequest{Query: "pitsa", RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Results, 1) {
+		assert.Equal(t, "uz", res.Results[0].Locale)
+		assert.Equal(t, "Pomidorli pitsa", res.Results[0].Name)
+		assert.Equal(t, "Pomidorli <mark>pitsa</mark>", res.Results[0].NameHighlight)
+	}
+
+	res, err = repo.Search(ctx, &pb.SearchRequest{Query: "chips", RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Results, 1) {
+		assert.Equal(t, "Fish & Chips <large>", res.Results[0].Name)
+		assert.Equal(t, "Fish &amp; <mark>Chips</mark> &lt;large&gt;", res.Results[0].NameHighlight)
+		assert.Empty(t, res.Results[0].Locale)
+	}
+
+	res, err = repo.Search(ctx, &pb.SearchRequest{Query: "basil", RestaurantId: restaurantId, Type: SearchRestaurant})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Results)
+
+	_, err = repo.Search(ctx, &pb.SearchRequest{Query: "?!"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = repo.Search(ctx, &pb.SearchRequest{Query: "pizza", Type: "dish"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
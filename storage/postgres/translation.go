@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/locale"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// translationTable is a table of translations and the column of the row
+// they translate.
+type translationTable struct {
+	name string
+	key  string
+}
+
+var (
+	restaurantTranslations = translationTable{name: "RestaurantTranslations", key: "restaurant_id"}
+	menuItemTranslations   = translationTable{name: "MenuItemTranslations", key: "menu_item_id"}
+)
+
+// normalizeTranslations validates the locales of translations and returns
+// them in stored form. A locale may be given only once.
+func normalizeTranslations(translations []*pb.Translation) ([]*pb.Translation, error) {
+	seen := make(map[string]bool, len(translations))
+	normalized := make([]*pb.Translation, 0, len(translations))
+	for _, t := range translations {
+		l, ok := locale.Normalize(t.Locale)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid locale %q, use a language tag such as uz or en-GB", t.Locale)
+		}
+		if seen[l] {
+			return nil, status.Errorf(codes.InvalidArgument, "locale %q is translated more than once", l)
+		}
+		seen[l] = true
+		normalized = append(normalized, &pb.Translation{Locale: l, Name: t.Name, Description: t.Description})
+	}
+	return normalized, nil
+}
+
+// saveTranslations stores the translations of the row id, replacing those of
+// the same locale. A translation without a name and description removes its
+// locale.
+func saveTranslations(ctx context.Context, tx *sql.Tx, table translationTable, id string, translations []*pb.Translation) error {
+	for _, t := range translations {
+		var err error
+		if t.Name == "" && t.Description == "" {
+			_, err = tx.ExecContext(ctx, `DELETE FROM `+table.name+` WHERE `+table.key+` = $1 AND locale = $2`, id, t.Locale)
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO `+table.name+` (
+					`+table.key+`,
+					locale,
+					name,
+					description
+				)
+				VALUES (
+					$1,
+					$2,
+					$3,
+					$4
+				)
+				ON CONFLICT (`+table.key+`, locale) DO UPDATE SET
+					name = EXCLUDED.name,
+					description = EXCLUDED.description
+			`, id, t.Locale, t.Name, t.Description)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save translations: %v", err)
+		}
+	}
+	return nil
+}
+
+// loadTranslations returns the translations of the rows ids by row, ordered
+// by locale.
+func loadTranslations(ctx context.Context, q queryer, table translationTable, ids []string) (map[string][]*pb.Translation, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT
+			`+table.key+`,
+			locale,
+			name,
+			description
+		FROM
+			`+table.name+`
+		WHERE
+			`+table.key+` = ANY($1::uuid[])
+		ORDER BY
+			locale
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %v", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[string][]*pb.Translation)
+	for rows.Next() {
+		var id string
+		t := &pb.Translation{}
+		if err := rows.Scan(&id, &t.Locale, &t.Name, &t.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %v", err)
+		}
+		translations[id] = append(translations[id], t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get translations: %v", err)
+	}
+	return translations, nil
+}
+
+// localize replaces name and description with the translation matching the
+// preferred locales, field by field, and returns its locale. It returns ""
+// and leaves them alone when no translation matches.
+func localize(preferred []string, translations []*pb.Translation, name, description *string) string {
+	locales := make([]string, len(translations))
+	for i, t := range translations {
+		locales[i] = t.Locale
+	}
+	l, ok := locale.Match(preferred, locales)
+	if !ok {
+		return ""
+	}
+	for _, t := range translations {
+		if t.Locale != l {
+			continue
+		}
+		if t.Name != "" {
+			*name = t.Name
+		}
+		if t.Description != "" {
+			*description = t.Description
+		}
+	}
+	return l
+}
+
+// attachMenuTranslations fills in the translations of the items and puts
+// their name and description in the locale the caller asked for.
+func attachMenuTranslations(ctx context.Context, q queryer, items ...*pb.MenuItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+	translations, err := loadTranslations(ctx, q, menuItemTranslations, ids)
+	if err != nil {
+		return err
+	}
+	preferred := locale.FromContext(ctx)
+	for _, item := range items {
+		item.Translations = translations[item.Id]
+		item.Locale = localize(preferred, item.Translations, &item.Name, &item.Description)
+	}
+	return nil
+}
+
+// attachRestaurantTranslations does for restaurants what
+// attachMenuTranslations does for menu items.
+func attachRestaurantTranslations(ctx context.Context, q queryer, restaurants ...*pb.Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+	ids := make([]string, len(restaurants))
+	for i, restaurant := range restaurants {
+		ids[i] = restaurant.Id
+	}
+	translations, err := loadTranslations(ctx, q, restaurantTranslations, ids)
+	if err != nil {
+		return err
+	}
+	preferred := locale.FromContext(ctx)
+	for _, restaurant := range restaurants {
+		restaurant.Translations = translations[restaurant.Id]
+		restaurant.Locale = localize(preferred, restaurant.Translations, &restaurant.Name, &restaurant.Description)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pb "reservation-service/generated/reservation_service"
+	"reservation-service/locale"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeTranslations(t *testing.T) {
+	translations, err := normalizeTranslations([]*pb.Translation{{Locale: "en_GB", Name: "Plov"}, {Locale: "ru", Name: "Плов"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "en-gb", translations[0].Locale)
+
+	_, err = normalizeTranslations([]*pb.Translation{{Locale: "russian"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = normalizeTranslations([]*pb.Translation{{Locale: "ru"}, {Locale: "RU"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLocalize(t *testing.T) {
+	translations := []*pb.Translation{
+		{Locale: "ru", Name: "Плов", Description: "Рис с бараниной"},
+		{Locale: "uz", Name: "Palov"},
+	}
+
+	name, description := "Plov", "Rice with lamb"
+	assert.Equal(t, "ru", localize([]string{"ru-ru"}, translations, &name, &description))
+	assert.Equal(t, "Плов", name)
+	assert.Equal(t, "Рис с бараниной", description)
+
+	name, description = "Plov", "Rice with lamb"
+	assert.Equal(t, "uz", localize([]string{"uz"}, translations, &name, &description))
+	assert.Equal(t, "Palov", name)
+	assert.Equal(t, "Rice with lamb", description)
+
+	name, description = "Plov", "Rice with lamb"
+	assert.Empty(t, localize([]string{"de"}, translations, &name, &description))
+	assert.Equal(t, "Plov", name)
+}
+
+func TestTranslations(t *testing.T) {
+	db, err := connectTestDB()
+	if err != nil {
+		t.Errorf("failed to setup test database: %v", err)
+		return
+	}
+	defer db.Close()
+
+	repo := ReservationRepo{DB: db}
+	ctx := context.Background()
+	inRussian := metadata.NewIncomingContext(ctx, metadata.Pairs(locale.MetadataKey, "ru-RU, uz;q=0.5"))
+
+	created, err := repo.CreateRestaurant(ctx, &pb.CreateRestaurantRequest{
+		Name:        "Translation test",
+		Description: "A place to eat",
+		Translations: []*pb.Translation{
+			{Locale: "ru", Description: "Место, где поесть"},
+			{Locale: "uz", Description: "Ovqatlanish joyi"},
+		},
+	})
+	assert.NoError(t, err)
+	restaurantId := created.Restaurant.Id
+	defer repo.DeleteRestaurant(ctx, &pb.DeleteRestaurantRequest{Id: restaurantId, Force: true})
+	assert.Len(t, created.Restaurant.Translations, 2)
+	assert.Empty(t, created.Restaurant.Locale)
+
+	got, err := repo.GetRestaurant(inRussian, &pb.GetRestaurantRequest{Id: restaurantId})
+	assert.NoError(t, err)
+	assert.Equal(t, "ru", got.Restaurant.Locale)
+	assert.Equal(t, "Translation test", got.Restaurant.Name)
+	assert.Equal(t, "Место, где поесть", got.Restaurant.Description)
+
+	updated, err := repo.UpdateRestaurant(inRussian, &pb.UpdateRestaurantRequest{
+		Id:           restaurantId,
+		Name:         "Translation test",
+		Description:  "A place to eat",
+		Translations: []*pb.Translation{{Locale: "ru"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "uz", updated.Restaurant.Locale)
+	assert.Equal(t, "Ovqatlanish joyi", updated.Restaurant.Description)
+
+	item, err := repo.CreateMenuItem(ctx, &pb.CreateMenuItemRequest{
+		RestaurantId: restaurantId,
+		Name:         "Plov",
+		Price:        &pb.Money{MinorUnits: 1000},
+		Translations: []*pb.Translation{{Locale: "ru", Name: "Плов"}},
+	})
+	assert.NoError(t, err)
+
+	list, err := repo.ListMenuItems(inRussian, &pb.ListMenuItemsRequest{RestaurantId: restaurantId})
+	assert.NoError(t, err)
+	if assert.Len(t, list.MenuItems, 1) {
+		assert.Equal(t, "Плов", list.MenuItems[0].Name)
+		assert.Equal(t, "ru", list.MenuItems[0].Locale)
+	}
+
+	_, err = repo.UpdateMenuItem(ctx, &pb.UpdateMenuItemRequest{
+		Id:           item.MenuItem.Id,
+		RestaurantId: restaurantId,
+		Name:         "Plov",
+		Price:        &pb.Money{MinorUnits: 1000},
+		Translations: []*pb.Translation{{Locale: "xx-invalid-locale-"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -1,14 +1,21 @@
 package redis
 
-import "github.com/redis/go-redis/v9"
+import (
+	"reservation-service/config"
 
-func ConnectR() (*redis.Client) {
+	"github.com/redis/go-redis/v9"
+)
+
+func ConnectR(cfg config.RedisConfig) (*redis.Client) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "", // no password set
-		DB:       0,  // use default DB
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	})
 
 	return client
 }
-
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"reservation-service/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitRejectsUnknownExporter(t *testing.T) {
+	_, err := Init(context.Background(), config.TracingConfig{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
+
+func TestEndRecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	_, span := Start(context.Background(), "ReservationRepo.GetReservation")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "ReservationRepo.GetReservation", spans[0].Name())
+	assert.Equal(t, "boom", spans[0].Status().Description)
+}